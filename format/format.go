@@ -38,6 +38,16 @@ Set PrintContextObjects = true to enable printing of the context internals.
 */
 var PrintContextObjects = false
 
+/*
+By default, matchers render the actual and expected values in full and leave it to you to spot the differences.
+
+Set UseStructuralDiff = true to have the matchers that compare two values (Equal, BeEquivalentTo and BeComparableTo) also render a
+path-annotated list of differences when both values are composite values (structs, maps, slices, arrays or pointers to them) of the same type:
+
+	.Spec.Containers[2].Env["FOO"]: "a" != "b"
+*/
+var UseStructuralDiff = false
+
 // TruncatedDiff choose if we should display a truncated pretty diff or not
 var TruncatedDiff = true
 
@@ -49,6 +59,10 @@ var TruncateThreshold uint = 50
 // after the first diff location in a truncated string assertion error message.
 var CharactersAroundMismatchToInclude uint = 5
 
+// DiffContextLines (default 3) specifies how many unchanged lines are printed around each change when a line-oriented
+// diff of two multi-line strings is rendered in an assertion error message.
+var DiffContextLines uint = 3
+
 var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
 var timeType = reflect.TypeOf(time.Time{})
 
@@ -143,9 +157,14 @@ Expected
 to equal               |
     <string>: "...aaaaazaaaaa..."
 
+If both strings span multiple lines a line-oriented unified diff is rendered instead (see MessageWithLineDiff).
+
 */
 
 func MessageWithDiff(actual, message, expected string) string {
+	if strings.Contains(actual, "\n") && strings.Contains(expected, "\n") {
+		return MessageWithLineDiff(actual, message, expected)
+	}
 	if TruncatedDiff && len(actual) >= int(TruncateThreshold) && len(expected) >= int(TruncateThreshold) {
 		diffPoint := findFirstMismatch(actual, expected)
 		formattedActual := truncateAndFormat(actual, diffPoint)
@@ -349,6 +349,236 @@ var _ = Describe("Format", func() {
 		})
 	})
 
+	Describe("UnifiedDiff", func() {
+		lines := func(n int) []string {
+			result := []string{}
+			for i := range n {
+				result = append(result, fmt.Sprintf("line %d", i+1))
+			}
+			return result
+		}
+
+		It("returns nothing for identical strings", func() {
+			Expect(UnifiedDiff("a\nb", "a\nb")).To(BeEmpty())
+		})
+
+		It("renders a hunk for each change with surrounding context", func() {
+			actual := lines(20)
+			expected := lines(20)
+			expected[2] = "changed"
+			expected = append(expected[:15], expected[16:]...)
+
+			Expect(UnifiedDiff(strings.Join(actual, "\n"), strings.Join(expected, "\n"))).To(Equal(strings.Join([]string{
+				"@@ -1,6 +1,6 @@",
+				" line 1",
+				" line 2",
+				"-line 3",
+				"+changed",
+				" line 4",
+				" line 5",
+				" line 6",
+				"@@ -13,7 +13,6 @@",
+				" line 13",
+				" line 14",
+				" line 15",
+				"-line 16",
+				" line 17",
+				" line 18",
+				" line 19",
+			}, "\n")))
+		})
+
+		It("merges changes whose context overlaps into a single hunk", func() {
+			actual := lines(8)
+			expected := lines(8)
+			expected[1] = "two"
+			expected[6] = "seven"
+
+			Expect(UnifiedDiff(strings.Join(actual, "\n"), strings.Join(expected, "\n"))).To(HavePrefix("@@ -1,8 +1,8 @@\n"))
+			Expect(strings.Count(UnifiedDiff(strings.Join(actual, "\n"), strings.Join(expected, "\n")), "@@ ")).To(Equal(1))
+		})
+
+		It("gives up on strings that are too different to diff usefully", func() {
+			actual, expected := lines(4000), lines(4000)
+			for i := range expected {
+				expected[i] = "other " + expected[i]
+			}
+			start := time.Now()
+			Expect(UnifiedDiff(strings.Join(actual, "\n"), strings.Join(expected, "\n"))).To(BeEmpty())
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+
+			Expect(MessageWithLineDiff(strings.Join(actual, "\n"), "to equal", strings.Join(expected, "\n"))).NotTo(ContainSubstring("Diff (-actual +expected)"))
+		})
+
+		It("still diffs long strings with a few changes", func() {
+			actual, expected := lines(4000), lines(4000)
+			expected[2000] = "changed"
+			Expect(UnifiedDiff(strings.Join(actual, "\n"), strings.Join(expected, "\n"))).To(HavePrefix("@@ -1998,7 +1998,7 @@\n"))
+		})
+
+		Context("with an alternative number of context lines", func() {
+			BeforeEach(func() {
+				DiffContextLines = 0
+			})
+
+			AfterEach(func() {
+				DiffContextLines = 3
+			})
+
+			It("only renders the changed lines", func() {
+				Expect(UnifiedDiff("a\nb\nc", "a\nB\nc\nd")).To(Equal("@@ -2 +2 @@\n-b\n+B\n@@ -3,0 +4 @@\n+d"))
+			})
+		})
+
+		Describe("MessageWithDiff", func() {
+			It("renders a unified diff when both strings span multiple lines", func() {
+				Expect(MessageWithDiff("a\nb\nc", "to equal", "a\nB\nc")).To(Equal(strings.Join([]string{
+					"Expected",
+					"    <string>: a",
+					"    b",
+					"    c",
+					"to equal",
+					"    <string>: a",
+					"    B",
+					"    c",
+					"Diff (-actual +expected):",
+					"    @@ -1,3 +1,3 @@",
+					"     a",
+					"    -b",
+					"    +B",
+					"     c",
+				}, "\n")))
+			})
+
+			It("sticks with the single-line rendering when only one side spans multiple lines", func() {
+				Expect(MessageWithDiff("\n", "to equal", "something_else")).To(Equal(expectedSpecialCharacterFailureMessage))
+			})
+		})
+	})
+
+	Describe("StructuralDiff", func() {
+		type Env map[string]string
+		type Container struct {
+			Name string
+			Env  Env
+		}
+		type Spec struct {
+			Containers []Container
+			Replicas   *int
+			labels     []string
+		}
+		type Pod struct {
+			Spec Spec
+		}
+
+		var actual, expected Pod
+		BeforeEach(func() {
+			one, two := 1, 2
+			actual = Pod{Spec: Spec{
+				Containers: []Container{{Name: "a"}, {Name: "b"}, {Name: "c", Env: Env{"FOO": "a", "BAR": "x"}}},
+				Replicas:   &one,
+				labels:     []string{"x"},
+			}}
+			expected = Pod{Spec: Spec{
+				Containers: []Container{{Name: "a"}, {Name: "b"}, {Name: "c", Env: Env{"FOO": "b", "BAZ": "y"}}},
+				Replicas:   &two,
+				labels:     []string{"x", "y"},
+			}}
+		})
+
+		It("reports a path-annotated difference for every differing leaf", func() {
+			differences := StructuralDiff(actual, expected)
+			rendered := []string{}
+			for _, difference := range differences {
+				rendered = append(rendered, difference.String())
+			}
+			Expect(rendered).To(Equal([]string{
+				`.Spec.Containers[2].Env["BAR"]: "x" != <missing>`,
+				`.Spec.Containers[2].Env["BAZ"]: <missing> != "y"`,
+				`.Spec.Containers[2].Env["FOO"]: "a" != "b"`,
+				`.Spec.Replicas: 1 != 2`,
+				`.Spec.labels[1]: <missing> != "y"`,
+			}))
+		})
+
+		It("returns no differences for equal values", func() {
+			Expect(StructuralDiff(actual, actual)).To(BeEmpty())
+		})
+
+		It("only applies to composite values of the same type", func() {
+			Expect(StructuralDiff(1, 2)).To(BeNil())
+			Expect(StructuralDiff([]int{1}, []string{"1"})).To(BeNil())
+			Expect(StructuralDiff(time.Now(), time.Now().Add(time.Hour))).To(BeNil())
+			Expect(StructuralDiff((*Pod)(nil), &expected)).To(BeNil())
+		})
+
+		It("reports differences in the type of interface values", func() {
+			differences := StructuralDiff([]any{1, "a"}, []any{1, 2})
+			Expect(differences).To(HaveLen(1))
+			Expect(differences[0].String()).To(Equal(`[1]: <string>"a" != <int>2`))
+		})
+
+		It("distinguishes nil from empty", func() {
+			differences := StructuralDiff(Container{Env: nil}, Container{Env: Env{}})
+			Expect(differences).To(HaveLen(1))
+			Expect(differences[0].String()).To(Equal(`.Env: nil != {}`))
+		})
+
+		It("handles recursive structures", func() {
+			type Node struct {
+				Value int
+				Next  *Node
+			}
+			a := &Node{Value: 1}
+			a.Next = a
+			b := &Node{Value: 2}
+			b.Next = b
+			differences := StructuralDiff(a, b)
+			Expect(differences).To(HaveLen(1))
+			Expect(differences[0].String()).To(Equal(`.Value: 1 != 2`))
+		})
+
+		Context("when the values are nested more deeply than MaxDepth", func() {
+			BeforeEach(func() {
+				MaxDepth = 2
+			})
+
+			AfterEach(func() {
+				MaxDepth = 10
+			})
+
+			It("stops descending and reports the differing subtree", func() {
+				differences := StructuralDiff(actual, expected)
+				paths := []string{}
+				for _, difference := range differences {
+					paths = append(paths, difference.Path)
+				}
+				Expect(paths).To(Equal([]string{".Spec.Containers[2]", ".Spec.Replicas", ".Spec.labels[1]"}))
+			})
+		})
+
+		Describe("MessageWithStructuralDiff", func() {
+			It("renders the differences after the usual message", func() {
+				message := MessageWithStructuralDiff(actual, "to equal", expected, StructuralDiff(actual, expected))
+				Expect(message).To(HavePrefix(Message(actual, "to equal", expected) + "\nwith differences:\n"))
+				Expect(message).To(ContainSubstring("\n" + Indent + `.Spec.Containers[2].Env["FOO"]: "a" != "b"` + "\n"))
+			})
+
+			It("truncates the differences when they exceed MaxLength", func() {
+				MaxLength = 40
+				defer func() { MaxLength = 4000 }()
+				a, b := []int{1, 2, 3, 4, 5, 6, 7, 8}, []int{8, 7, 6, 5, 4, 3, 2, 1}
+				Expect(MessageWithStructuralDiff(a, "to equal", b, StructuralDiff(a, b))).To(HaveSuffix(IndentString(truncateHelpText, 1)))
+			})
+
+			It("is not rendered by Message, even when UseStructuralDiff is set", func() {
+				UseStructuralDiff = true
+				defer func() { UseStructuralDiff = false }()
+				Expect(Message(actual, "to equal", expected)).NotTo(ContainSubstring("with differences"))
+			})
+		})
+	})
+
 	Describe("IndentString", func() {
 		It("should indent the string", func() {
 			Expect(IndentString("foo\n  bar\nbaz", 2)).Should(Equal("        foo\n          bar\n        baz"))
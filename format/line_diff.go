@@ -0,0 +1,230 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+)
+
+type lineEditOp byte
+
+const (
+	lineEqual  lineEditOp = ' '
+	lineDelete lineEditOp = '-'
+	lineInsert lineEditOp = '+'
+)
+
+// line diffs are skipped for inputs with more lines (combined) or more differing lines than this - they'd be too large to make sense of,
+// and too expensive to compute for every failure message
+const (
+	maxLineDiffLines = 10000
+	maxLineDiffEdits = 1000
+)
+
+type lineEdit struct {
+	op   lineEditOp
+	text string
+}
+
+/*
+UnifiedDiff computes a line-oriented diff between actual and expected and renders it in unified format:
+
+	@@ -2,3 +2,3 @@
+	 unchanged
+	-actual line
+	+expected line
+	 unchanged
+
+Each hunk includes up to format.DiffContextLines lines of unchanged context.  UnifiedDiff returns "" if actual and expected are identical,
+and also if they are too large (over 10000 lines between them) or too different (over 1000 changed lines) for a diff to be of use.
+The rendered diff is subject to format.MaxLength.
+*/
+func UnifiedDiff(actual, expected string) string {
+	if actual == expected {
+		return ""
+	}
+	a, b := strings.Split(actual, "\n"), strings.Split(expected, "\n")
+	if len(a)+len(b) > maxLineDiffLines {
+		return ""
+	}
+	edits, ok := myersDiff(a, b, maxLineDiffEdits)
+	if !ok {
+		return ""
+	}
+	return truncateLongStrings(renderHunks(edits, int(DiffContextLines)))
+}
+
+/*
+MessageWithLineDiff generates a message like Message(...) and, when both actual and expected span multiple lines,
+follows it with the UnifiedDiff of the two:
+
+	Expected
+		<actual>
+	<message>
+		<expected>
+	Diff (-actual +expected):
+		@@ -2,3 +2,3 @@
+		 unchanged
+		-actual line
+		+expected line
+		 unchanged
+*/
+func MessageWithLineDiff(actual, message, expected string) string {
+	return Message(actual, message, expected) + LineDiff(actual, expected)
+}
+
+/*
+LineDiff returns the "Diff (-actual +expected):" section that MessageWithLineDiff appends to its message, so that matchers can append
+it to messages of their own.  It returns "" unless both actual and expected span multiple lines and UnifiedDiff renders a diff for them.
+*/
+func LineDiff(actual, expected string) string {
+	if !strings.Contains(actual, "\n") || !strings.Contains(expected, "\n") {
+		return ""
+	}
+	diff := UnifiedDiff(actual, expected)
+	if diff == "" {
+		return ""
+	}
+	return "\nDiff (-actual +expected):\n" + IndentString(diff, 1)
+}
+
+// myersDiff implements Myers' O(ND) shortest edit script algorithm over lines.  It gives up (returning false) once the
+// edit script would exceed maxEdits, which bounds the trace it keeps to O(maxEdits²).
+func myersDiff(a, b []string, maxEdits int) ([]lineEdit, bool) {
+	n, m := len(a), len(b)
+	maxD := min(n+m, maxEdits)
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	// trace[d] holds the furthest reaching paths on diagonals -d-1...d+1 before step d
+	trace := [][]int{}
+
+	found := false
+	for d := 0; d <= maxD && !found; d++ {
+		trace = append(trace, append([]int{}, v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	edits := []lineEdit{}
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v, offset := trace[d], d+1
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, lineEdit{lineEqual, a[x-1]})
+			x, y = x-1, y-1
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, lineEdit{lineInsert, b[y-1]})
+			} else {
+				edits = append(edits, lineEdit{lineDelete, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits, true
+}
+
+func renderHunks(edits []lineEdit, context int) string {
+	out := &strings.Builder{}
+	i := 0
+	aLine, bLine := 1, 1
+	for i < len(edits) {
+		// skip ahead to the next change
+		if edits[i].op == lineEqual {
+			i, aLine, bLine = i+1, aLine+1, bLine+1
+			continue
+		}
+
+		// back up to include leading context
+		start := i
+		for start > 0 && i-start < context && edits[start-1].op == lineEqual {
+			start--
+		}
+		hunkALine, hunkBLine := aLine-(i-start), bLine-(i-start)
+
+		// extend the hunk until the changes are separated by more than 2*context unchanged lines
+		end := i
+		equalRun := 0
+		for end < len(edits) {
+			if edits[end].op == lineEqual {
+				equalRun++
+			} else {
+				equalRun = 0
+			}
+			end++
+			if equalRun > 2*context {
+				break
+			}
+		}
+		// trailing unchanged lines beyond the context belong to the gap, not the hunk
+		if equalRun > context {
+			end -= equalRun - context
+		}
+
+		aCount, bCount := 0, 0
+		for _, edit := range edits[start:end] {
+			if edit.op != lineInsert {
+				aCount++
+			}
+			if edit.op != lineDelete {
+				bCount++
+			}
+		}
+		fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(hunkALine, aCount), hunkRange(hunkBLine, bCount))
+		for _, edit := range edits[start:end] {
+			fmt.Fprintf(out, "%c%s\n", edit.op, edit.text)
+		}
+
+		for _, edit := range edits[i:end] {
+			if edit.op != lineInsert {
+				aLine++
+			}
+			if edit.op != lineDelete {
+				bLine++
+			}
+		}
+		i = end
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,219 @@
+package format
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+Difference describes a single point at which two values differ.
+
+Path is a Go-like accessor expression rooted at the compared values (e.g. `.Spec.Containers[2].Env["FOO"]`).
+Actual and Expected hold the differing values.  A zero (invalid) reflect.Value signals that the value is missing on that side
+(e.g. a map key that only exists in one of the two maps, or a trailing slice element).
+*/
+type Difference struct {
+	Path     string
+	Actual   reflect.Value
+	Expected reflect.Value
+}
+
+// String renders the difference as `<path>: <actual> != <expected>`
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "<root>"
+	}
+	return fmt.Sprintf("%s: %s != %s", path, formatDifferenceValue(d.Actual), formatDifferenceValue(d.Expected))
+}
+
+func formatDifferenceValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<missing>"
+	}
+	return formatValue(v, 1, false, map[uintptr]struct{}{})
+}
+
+/*
+StructuralDiff walks actual and expected in lock-step and returns every leaf at which they differ.
+
+StructuralDiff only applies to composite values (structs, maps, slices, arrays and pointers to them) of identical type; in all other cases it returns nil.
+The walk stops at format.MaxDepth - deeper subtrees that differ are reported as a single Difference.
+*/
+func StructuralDiff(actual, expected any) []Difference {
+	a, e := reflect.ValueOf(actual), reflect.ValueOf(expected)
+	if !isStructurallyDiffable(a) || !isStructurallyDiffable(e) || a.Type() != e.Type() {
+		return nil
+	}
+	differ := &structuralDiffer{visited: map[[2]uintptr]struct{}{}}
+	differ.diff("", a, e, 0)
+	return differ.differences
+}
+
+func isStructurallyDiffable(v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return v.Type() != timeType
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+/*
+MessageWithStructuralDiff generates a failure message like Message(...) and appends the passed-in differences:
+
+	Expected
+		<pretty printed actual>
+	<message>
+		<pretty printed expected>
+	with differences:
+		.Spec.Containers[2].Env["FOO"]: "a" != "b"
+
+The list of differences is subject to format.MaxLength.
+*/
+func MessageWithStructuralDiff(actual any, message string, expected any, differences []Difference) string {
+	lines := make([]string, len(differences))
+	for i, difference := range differences {
+		lines[i] = difference.String()
+	}
+	return fmt.Sprintf("Expected\n%s\n%s\n%s\nwith differences:\n%s", Object(actual, 1), message, Object(expected, 1), IndentString(truncateLongStrings(strings.Join(lines, "\n")), 1))
+}
+
+type structuralDiffer struct {
+	differences []Difference
+	visited     map[[2]uintptr]struct{}
+}
+
+func (d *structuralDiffer) report(path string, a, e reflect.Value) {
+	d.differences = append(d.differences, Difference{Path: path, Actual: a, Expected: e})
+}
+
+func (d *structuralDiffer) diff(path string, a, e reflect.Value, depth uint) {
+	if isNilValue(a) || isNilValue(e) {
+		if isNilValue(a) != isNilValue(e) || a.IsValid() != e.IsValid() {
+			d.report(path, a, e)
+		}
+		return
+	}
+	if a.Type() != e.Type() {
+		d.report(path, a, e)
+		return
+	}
+	if depth > MaxDepth {
+		if !reflectValuesDeepEqual(a, e) {
+			d.report(path, a, e)
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		pair := [2]uintptr{a.Pointer(), e.Pointer()}
+		if pair[0] == pair[1] {
+			return
+		}
+		if _, ok := d.visited[pair]; ok {
+			return
+		}
+		d.visited[pair] = struct{}{}
+		d.diff(path, a.Elem(), e.Elem(), depth)
+	case reflect.Interface:
+		if a.Elem().Type() != e.Elem().Type() {
+			d.report(path, a, e)
+			return
+		}
+		d.diff(path, a.Elem(), e.Elem(), depth)
+	case reflect.Struct:
+		if a.Type() == timeType {
+			if !reflectValuesDeepEqual(a, e) {
+				d.report(path, a, e)
+			}
+			return
+		}
+		t := a.Type()
+		for i := range a.NumField() {
+			d.diff(path+"."+t.Field(i).Name, a.Field(i), e.Field(i), depth+1)
+		}
+	case reflect.Map:
+		keys := map[string]reflect.Value{}
+		for _, key := range a.MapKeys() {
+			keys[formatValue(key, 0, false, map[uintptr]struct{}{})] = key
+		}
+		for _, key := range e.MapKeys() {
+			keys[formatValue(key, 0, false, map[uintptr]struct{}{})] = key
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for k := range keys {
+			sortedKeys = append(sortedKeys, k)
+		}
+		sort.Strings(sortedKeys)
+		for _, k := range sortedKeys {
+			av, ev := a.MapIndex(keys[k]), e.MapIndex(keys[k])
+			if !av.IsValid() || !ev.IsValid() {
+				d.report(path+"["+k+"]", av, ev)
+				continue
+			}
+			d.diff(path+"["+k+"]", av, ev, depth+1)
+		}
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.Type().Elem().Kind() == reflect.Uint8 {
+			if string(a.Bytes()) != string(e.Bytes()) {
+				d.report(path, a, e)
+			}
+			return
+		}
+		n := max(a.Len(), e.Len())
+		for i := range n {
+			elementPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= a.Len():
+				d.report(elementPath, reflect.Value{}, e.Index(i))
+			case i >= e.Len():
+				d.report(elementPath, a.Index(i), reflect.Value{})
+			default:
+				d.diff(elementPath, a.Index(i), e.Index(i), depth+1)
+			}
+		}
+	default:
+		if !reflectValuesDeepEqual(a, e) {
+			d.report(path, a, e)
+		}
+	}
+}
+
+// reflectValuesDeepEqual compares two values of the same type, including values obtained through unexported fields
+func reflectValuesDeepEqual(a, e reflect.Value) bool {
+	if a.CanInterface() && e.CanInterface() {
+		if a.Type() == timeType {
+			return a.Interface().(time.Time).Equal(e.Interface().(time.Time))
+		}
+		return reflect.DeepEqual(a.Interface(), e.Interface())
+	}
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == e.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == e.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == e.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == e.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == e.Complex()
+	case reflect.String:
+		return a.String() == e.String()
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return a.Pointer() == e.Pointer()
+	}
+	return formatValue(a, 0, false, map[uintptr]struct{}{}) == formatValue(e, 0, false, map[uintptr]struct{}{})
+}
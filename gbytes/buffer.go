@@ -11,6 +11,7 @@ access the entire buffer's contents with Contents().
 package gbytes
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -258,3 +259,55 @@ func (b *Buffer) didSay(re *regexp.Regexp) (bool, []byte) {
 	}
 	return false, copyOfUnreadBytes
 }
+
+/*
+didSayFunc passes the unread portion of the buffer to find.  When find returns a non-negative offset, the read cursor is fast forwarded by that offset.
+Holding the lock throughout makes multi-pattern matches atomic.
+*/
+func (b *Buffer) didSayFunc(find func(unread []byte) int) (bool, []byte) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	unreadBytes := b.contents[b.readCursor:]
+	copyOfUnreadBytes := make([]byte, len(unreadBytes))
+	copy(copyOfUnreadBytes, unreadBytes)
+
+	if advance := find(copyOfUnreadBytes); advance >= 0 {
+		b.readCursor += uint64(advance)
+		return true, copyOfUnreadBytes
+	}
+	return false, copyOfUnreadBytes
+}
+
+/*
+didSayLine scans the unread portion of the buffer line by line, and fast forwards the read cursor past the first line that satisfies matches.
+Only complete lines are considered until the buffer is closed, at which point a trailing line without a newline is considered too.
+*/
+func (b *Buffer) didSayLine(matches func(line []byte) bool) (bool, []byte, []byte) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	unreadBytes := b.contents[b.readCursor:]
+	copyOfUnreadBytes := make([]byte, len(unreadBytes))
+	copy(copyOfUnreadBytes, unreadBytes)
+
+	offset := 0
+	for offset < len(unreadBytes) {
+		end := bytes.IndexByte(unreadBytes[offset:], '\n')
+		var line []byte
+		if end == -1 {
+			if !b.closed {
+				break
+			}
+			line, end = unreadBytes[offset:], len(unreadBytes)
+		} else {
+			line, end = unreadBytes[offset:offset+end], offset+end+1
+		}
+		if matches(line) {
+			b.readCursor += uint64(end)
+			return true, copyOfUnreadBytes, append([]byte{}, line...)
+		}
+		offset = end
+	}
+	return false, copyOfUnreadBytes, nil
+}
@@ -0,0 +1,156 @@
+package gbytes
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/matchers/support/goraph/bipartitegraph"
+)
+
+/*
+SayAll is a Gomega matcher that operates on gbytes.Buffers:
+
+	Eventually(buffer).Should(SayAll("worker 1 ready", "worker 2 ready", "worker 3 ready"))
+
+will succeed if the unread portion of the buffer matches each of the regular expressions, in any order.  Each pattern must match at a
+distinct position in the buffer, so SayAll("ready", "ready") requires "ready" to appear twice (and SayAll(`worker \d`, "worker 1") requires
+two workers).  Matches that start at different positions may overlap, though: SayAll("worker 1 ready", "ready") succeeds on a single
+"worker 1 ready" line.
+
+When SayAll succeeds, it fast forwards the gbytes.Buffer's read cursor to just after the last of the matches (picking the matches that end
+earliest when there is a choice).  When it fails, the read cursor is
+left untouched - even if some of the patterns matched - and the failure message shows which patterns matched.
+
+In addition to gbytes.Buffers, SayAll can operate on objects that implement the gbytes.BufferProvider interface.
+*/
+func SayAll(patterns ...string) *sayAllMatcher {
+	return &sayAllMatcher{res: compilePatterns(patterns)}
+}
+
+type sayAllMatcher struct {
+	res             []*regexp.Regexp
+	receivedSayings []byte
+	matched         []bool
+}
+
+func (m *sayAllMatcher) Match(actual any) (success bool, err error) {
+	buffer, ok := bufferFor(actual)
+	if !ok {
+		return false, fmt.Errorf("SayAll must be passed a *gbytes.Buffer or BufferProvider.  Got:\n%s", format.Object(actual, 1))
+	}
+
+	didSay, sayings := buffer.didSayFunc(func(unread []byte) int {
+		candidates := make([][][]int, len(m.res))
+		for i, re := range m.res {
+			candidates[i] = re.FindAllIndex(unread, -1)
+		}
+		m.matched = make([]bool, len(m.res))
+		end, success := 0, true
+		for i, loc := range assignMatches(candidates) {
+			m.matched[i] = loc != nil
+			if loc != nil {
+				end = max(end, loc[1])
+			}
+			success = success && m.matched[i]
+		}
+		if !success {
+			return -1
+		}
+		return end
+	})
+	m.receivedSayings = sayings
+
+	return didSay, nil
+}
+
+// assignMatches picks one of its candidate matches for as many patterns as possible, without two patterns matching at the same position in the
+// buffer.  That's a maximum matching between patterns and the positions their matches start at.  When every pattern can be matched it
+// returns the assignment that ends earliest.  Patterns left without a match get a nil location.
+func assignMatches(candidates [][][]int) [][]int {
+	assignment := assignMatchesEndingBy(candidates, math.MaxInt)
+	if !assignsEveryPattern(assignment) {
+		return assignment
+	}
+
+	//every pattern can be matched: find the earliest end that still allows it
+	ends := []int{}
+	for _, locs := range candidates {
+		for _, loc := range locs {
+			ends = append(ends, loc[1])
+		}
+	}
+	slices.Sort(ends)
+	i := sort.Search(len(ends), func(i int) bool {
+		return assignsEveryPattern(assignMatchesEndingBy(candidates, ends[i]))
+	})
+	if i == len(ends) {
+		return assignment
+	}
+	return assignMatchesEndingBy(candidates, ends[i])
+}
+
+func assignsEveryPattern(assignment [][]int) bool {
+	for _, loc := range assignment {
+		if loc == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// assignMatchesEndingBy is assignMatches restricted to the candidate matches that end by end
+func assignMatchesEndingBy(candidates [][][]int, end int) [][]int {
+	patterns, starts := []any{}, []any{}
+	seen := map[int]bool{}
+	for i, locs := range candidates {
+		patterns = append(patterns, i)
+		for _, loc := range locs {
+			if loc[1] <= end && !seen[loc[0]] {
+				seen[loc[0]] = true
+				starts = append(starts, loc[0])
+			}
+		}
+	}
+	candidateAt := func(pattern int, start int) []int {
+		for _, loc := range candidates[pattern] {
+			if loc[0] == start && loc[1] <= end {
+				return loc
+			}
+		}
+		return nil
+	}
+
+	graph, _ := bipartitegraph.NewBipartiteGraph(patterns, starts, func(pattern, start any) (bool, error) {
+		return candidateAt(pattern.(int), start.(int)) != nil, nil
+	})
+	assignment := make([][]int, len(candidates))
+	for _, edge := range graph.LargestMatching() {
+		pattern, start := graph.Left[edge.Node1].Value.(int), graph.Right[edge.Node2-len(graph.Left)].Value.(int)
+		assignment[pattern] = candidateAt(pattern, start)
+	}
+	return assignment
+}
+
+func (m *sayAllMatcher) FailureMessage(actual any) (message string) {
+	return fmt.Sprintf(
+		"Got stuck at:\n%s\nWaiting for all of these patterns, in any order:\n%s",
+		format.IndentString(string(m.receivedSayings), 1),
+		format.IndentString(patternChecklist(m.res, func(i int) bool { return m.matched[i] }, -1), 1),
+	)
+}
+
+func (m *sayAllMatcher) NegatedFailureMessage(actual any) (message string) {
+	return fmt.Sprintf(
+		"Saw:\n%s\nWhich matches all of the unexpected patterns:\n%s",
+		format.IndentString(string(m.receivedSayings), 1),
+		format.IndentString(patternList(m.res), 1),
+	)
+}
+
+func (m *sayAllMatcher) MatchMayChangeInTheFuture(actual any) bool {
+	return bufferMayChange(actual)
+}
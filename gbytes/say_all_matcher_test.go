@@ -0,0 +1,94 @@
+package gbytes_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/gomega/gbytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SayAll", func() {
+	var buffer *Buffer
+
+	BeforeEach(func() {
+		buffer = NewBuffer()
+		buffer.Write([]byte("worker 2 ready\nworker 1 ready\nworker 3 ready\ndone\n"))
+	})
+
+	It("errors when not passed a Buffer or BufferProvider", func() {
+		_, err := SayAll("a").Match("foo")
+		Expect(err).Should(MatchError(ContainSubstring("SayAll must be passed a *gbytes.Buffer or BufferProvider")))
+	})
+
+	It("succeeds when every pattern appears, in any order, fast-forwarding past the last match", func() {
+		Expect(buffer).Should(SayAll("worker 1 ready", "worker 2 ready"))
+		Expect(buffer).ShouldNot(SayAll("worker 2 ready"))
+		Expect(buffer).Should(SayAll("done", "worker 3 ready"))
+	})
+
+	It("fails when any pattern is missing, leaving the read cursor untouched", func() {
+		Expect(buffer).ShouldNot(SayAll("worker 1 ready", "worker 4 ready"))
+		Expect(buffer).Should(SayAll("worker 2 ready"))
+	})
+
+	It("requires each pattern to match a distinct portion of the buffer", func() {
+		Expect(buffer).Should(SayAll("ready", "ready", "ready"))
+		buffer = BufferWithBytes([]byte("worker 1 ready\n"))
+		Expect(buffer).ShouldNot(SayAll(`worker \d`, "worker 1"))
+		Expect(buffer).Should(SayAll("worker", "ready"))
+	})
+
+	It("lets matches that start at different positions overlap", func() {
+		buffer = BufferWithBytes([]byte("worker 1 ready\n"))
+		Expect(buffer).Should(SayAll("worker 1 ready", "ready"))
+	})
+
+	It("stays fast on large buffers", func() {
+		buffer = BufferWithBytes([]byte(strings.Repeat("worker ready\n", 300)))
+		start := time.Now()
+		Expect(buffer).ShouldNot(SayAll("ready", "ready", "ready", "ready", "never"))
+		Expect(buffer).Should(SayAll("ready", "ready", "ready", "ready"))
+		Expect(time.Since(start)).Should(BeNumerically("<", 5*time.Second))
+		Expect(buffer).Should(Say(`^\nworker ready\n`))
+	})
+
+	It("finds an assignment even when a pattern's first match is needed by another pattern", func() {
+		buffer = BufferWithBytes([]byte("ab a"))
+		Expect(buffer).Should(SayAll("a", "ab"))
+		Expect(buffer.Contents()).Should(Equal([]byte("ab a")))
+		Expect(buffer).ShouldNot(SayAll("a"))
+
+		buffer = BufferWithBytes([]byte("ab a ab"))
+		Expect(buffer).Should(SayAll("a", "ab"))
+		Expect(buffer).Should(SayAll("ab"))
+	})
+
+	It("works with BufferProviders and Eventually", func() {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			buffer.Write([]byte("worker 4 ready\n"))
+		}()
+		Eventually(&speaker{buffer: buffer}).Should(SayAll("worker 4 ready", "worker 1 ready"))
+	})
+
+	Describe("failure messages", func() {
+		It("shows which patterns matched and the unread buffer", func() {
+			buffer = BufferWithBytes([]byte("worker 2 ready\n"))
+			failures := InterceptGomegaFailures(func() {
+				Expect(buffer).Should(SayAll("worker 1", "worker 2"))
+			})
+			Expect(failures).Should(ConsistOf("Got stuck at:\n    worker 2 ready\n    \nWaiting for all of these patterns, in any order:\n      worker 1\n    ✓ worker 2"))
+		})
+
+		It("shows the patterns when negated", func() {
+			buffer = BufferWithBytes([]byte("worker 2 ready\n"))
+			failures := InterceptGomegaFailures(func() {
+				Expect(buffer).ShouldNot(SayAll("worker 2"))
+			})
+			Expect(failures).Should(ConsistOf("Saw:\n    worker 2 ready\n    \nWhich matches all of the unexpected patterns:\n    worker 2"))
+		})
+	})
+})
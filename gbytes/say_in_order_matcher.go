@@ -0,0 +1,107 @@
+package gbytes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+)
+
+/*
+SayInOrder is a Gomega matcher that operates on gbytes.Buffers:
+
+	Eventually(buffer).Should(SayInOrder("starting", "listening on :\\d+", "ready"))
+
+will succeed if the unread portion of the buffer matches each of the regular expressions, one after the other.
+
+When SayInOrder succeeds, it fast forwards the gbytes.Buffer's read cursor to just after the match for the last pattern.  When it fails,
+the read cursor is left untouched - even if some of the patterns matched - and the failure message shows which patterns matched.
+
+In addition to gbytes.Buffers, SayInOrder can operate on objects that implement the gbytes.BufferProvider interface.
+*/
+func SayInOrder(patterns ...string) *sayInOrderMatcher {
+	return &sayInOrderMatcher{res: compilePatterns(patterns)}
+}
+
+type sayInOrderMatcher struct {
+	res             []*regexp.Regexp
+	receivedSayings []byte
+	matched         int
+	stuckAt         int
+}
+
+func (m *sayInOrderMatcher) Match(actual any) (success bool, err error) {
+	buffer, ok := bufferFor(actual)
+	if !ok {
+		return false, fmt.Errorf("SayInOrder must be passed a *gbytes.Buffer or BufferProvider.  Got:\n%s", format.Object(actual, 1))
+	}
+
+	didSay, sayings := buffer.didSayFunc(func(unread []byte) int {
+		m.matched, m.stuckAt = 0, 0
+		for _, re := range m.res {
+			loc := re.FindIndex(unread[m.stuckAt:])
+			if loc == nil {
+				return -1
+			}
+			m.matched += 1
+			m.stuckAt += loc[1]
+		}
+		return m.stuckAt
+	})
+	m.receivedSayings = sayings
+
+	return didSay, nil
+}
+
+func (m *sayInOrderMatcher) FailureMessage(actual any) (message string) {
+	return fmt.Sprintf(
+		"Got stuck at:\n%s\nWaiting for these patterns, in order:\n%s",
+		format.IndentString(string(m.receivedSayings[m.stuckAt:]), 1),
+		format.IndentString(patternChecklist(m.res, func(i int) bool { return i < m.matched }, m.matched), 1),
+	)
+}
+
+func (m *sayInOrderMatcher) NegatedFailureMessage(actual any) (message string) {
+	return fmt.Sprintf(
+		"Saw:\n%s\nWhich matches the unexpected patterns, in order:\n%s",
+		format.IndentString(string(m.receivedSayings), 1),
+		format.IndentString(patternList(m.res), 1),
+	)
+}
+
+func (m *sayInOrderMatcher) MatchMayChangeInTheFuture(actual any) bool {
+	return bufferMayChange(actual)
+}
+
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		res[i] = regexp.MustCompile(pattern)
+	}
+	return res
+}
+
+func patternList(res []*regexp.Regexp) string {
+	lines := make([]string, len(res))
+	for i, re := range res {
+		lines[i] = re.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// patternChecklist renders one line per pattern, marking the ones that matched with ✓ and the one that got stuck with ✗ (pass -1 for none)
+func patternChecklist(res []*regexp.Regexp, matched func(i int) bool, stuck int) string {
+	lines := make([]string, len(res))
+	for i, re := range res {
+		switch {
+		case matched(i):
+			lines[i] = "✓ " + re.String()
+		case i == stuck:
+			lines[i] = "✗ " + re.String()
+		default:
+			lines[i] = "  " + re.String()
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,78 @@
+package gbytes_test
+
+import (
+	"time"
+
+	. "github.com/onsi/gomega/gbytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SayInOrder", func() {
+	var buffer *Buffer
+
+	BeforeEach(func() {
+		buffer = NewBuffer()
+		buffer.Write([]byte("starting\nlistening on :8080\nready\n"))
+	})
+
+	It("errors when not passed a Buffer or BufferProvider", func() {
+		_, err := SayInOrder("a").Match("foo")
+		Expect(err).Should(MatchError(ContainSubstring("SayInOrder must be passed a *gbytes.Buffer or BufferProvider")))
+	})
+
+	It("succeeds when the patterns appear in order, fast-forwarding past the last match", func() {
+		Expect(buffer).Should(SayInOrder("starting", `listening on :\d+`))
+		Expect(buffer).ShouldNot(SayInOrder("starting"))
+		Expect(buffer).Should(SayInOrder("ready"))
+	})
+
+	It("fails when the patterns appear out of order, leaving the read cursor untouched", func() {
+		Expect(buffer).ShouldNot(SayInOrder("ready", "starting"))
+		Expect(buffer).Should(SayInOrder("starting", "ready"))
+	})
+
+	It("requires each pattern to match after the previous one", func() {
+		Expect(buffer).ShouldNot(SayInOrder("ready", "ready"))
+		buffer.Write([]byte("ready\n"))
+		Expect(buffer).Should(SayInOrder("ready", "ready"))
+	})
+
+	It("works with BufferProviders and Eventually", func() {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			buffer.Write([]byte("done\n"))
+		}()
+		Eventually(&speaker{buffer: buffer}).Should(SayInOrder("ready", "done"))
+	})
+
+	It("aborts Eventually when the buffer is closed", func() {
+		buffer.Close()
+		failures := InterceptGomegaFailures(func() {
+			Eventually(buffer, time.Minute).Should(SayInOrder("ready", "done"))
+		})
+		Expect(failures).Should(HaveLen(1))
+	})
+
+	Describe("failure messages", func() {
+		It("shows which patterns matched and the unread tail", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(buffer).Should(SayInOrder("starting", "ready", "listening", "done"))
+			})
+			Expect(failures).Should(ConsistOf("Got stuck at:\n    \n    \nWaiting for these patterns, in order:\n    ✓ starting\n    ✓ ready\n    ✗ listening\n      done"))
+
+			failures = InterceptGomegaFailures(func() {
+				Expect(buffer).Should(SayInOrder("starting", "done"))
+			})
+			Expect(failures).Should(ConsistOf("Got stuck at:\n    \n    listening on :8080\n    ready\n    \nWaiting for these patterns, in order:\n    ✓ starting\n    ✗ done"))
+		})
+
+		It("shows the patterns when negated", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(buffer).ShouldNot(SayInOrder("starting", "ready"))
+			})
+			Expect(failures).Should(ConsistOf("Saw:\n    starting\n    listening on :8080\n    ready\n    \nWhich matches the unexpected patterns, in order:\n    starting\n    ready"))
+		})
+	})
+})
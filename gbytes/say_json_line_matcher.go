@@ -0,0 +1,158 @@
+package gbytes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+/*
+SayJSONLine is a Gomega matcher that operates on gbytes.Buffers containing structured log output, one record per line:
+
+	Eventually(session.Err).Should(SayJSONLine(HaveKeyWithValue("level", "ERROR")))
+
+SayJSONLine decodes each unread line into a map[string]any and succeeds if any of the records satisfy the passed-in matcher.  Lines can be
+JSON objects (as emitted by slog's JSONHandler or zap) or logfmt (as emitted by slog's TextHandler):
+
+	{"time":"2024-01-01T00:00:00Z","level":"ERROR","msg":"boom","attempt":3}
+	time=2024-01-01T00:00:00Z level=ERROR msg=boom attempt=3
+
+JSON values are decoded with encoding/json, so numbers become float64s - use BeNumerically to match them.  logfmt values are always strings
+(a key without a value decodes to true).  Lines that are neither are skipped.
+
+When SayJSONLine succeeds, it fast forwards the gbytes.Buffer's read cursor to just after the matching line.  Like Say, subsequent calls
+will only match against the unread portion of the buffer.  Only complete lines are considered until the buffer is closed.
+
+In addition to gbytes.Buffers, SayJSONLine can operate on objects that implement the gbytes.BufferProvider interface.
+*/
+func SayJSONLine(matcher types.GomegaMatcher) *sayJSONLineMatcher {
+	return &sayJSONLineMatcher{matcher: matcher}
+}
+
+type sayJSONLineMatcher struct {
+	matcher         types.GomegaMatcher
+	receivedSayings []byte
+	matchedLine     []byte
+	lastRecord      map[string]any
+	lastErr         error
+}
+
+func (m *sayJSONLineMatcher) Match(actual any) (success bool, err error) {
+	buffer, ok := bufferFor(actual)
+	if !ok {
+		return false, fmt.Errorf("SayJSONLine must be passed a *gbytes.Buffer or BufferProvider.  Got:\n%s", format.Object(actual, 1))
+	}
+
+	m.lastRecord, m.lastErr = nil, nil
+	didSay, sayings, line := buffer.didSayLine(func(line []byte) bool {
+		record, ok := decodeStructuredLine(line)
+		if !ok {
+			return false
+		}
+		m.lastRecord = record
+		matches, err := m.matcher.Match(record)
+		m.lastErr = err
+		return err == nil && matches
+	})
+	m.receivedSayings, m.matchedLine = sayings, line
+
+	return didSay, nil
+}
+
+func (m *sayJSONLineMatcher) FailureMessage(actual any) (message string) {
+	message = fmt.Sprintf("Got stuck at:\n%s\nWaiting for a JSON or logfmt line satisfying the matcher", format.IndentString(string(m.receivedSayings), 1))
+	switch {
+	case m.lastRecord == nil:
+		message += ".  No records were found."
+	case m.lastErr != nil:
+		message += ".  The last record failed with:\n" + format.IndentString(m.lastErr.Error(), 1)
+	default:
+		message += ".  The last record failed with:\n" + format.IndentString(m.matcher.FailureMessage(m.lastRecord), 1)
+	}
+	return message
+}
+
+func (m *sayJSONLineMatcher) NegatedFailureMessage(actual any) (message string) {
+	return fmt.Sprintf("Saw:\n%s\nWhich satisfies the matcher:\n%s", format.IndentString(string(m.matchedLine), 1), format.IndentString(m.matcher.NegatedFailureMessage(m.lastRecord), 1))
+}
+
+func (m *sayJSONLineMatcher) MatchMayChangeInTheFuture(actual any) bool {
+	return bufferMayChange(actual)
+}
+
+// decodeStructuredLine decodes a JSON object or logfmt line into a record
+func decodeStructuredLine(line []byte) (map[string]any, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil, false
+	}
+	if line[0] == '{' {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, false
+		}
+		return record, true
+	}
+	return decodeLogfmt(string(line))
+}
+
+/*
+decodeLogfmt decodes a line of space-separated key=value pairs.  Values may be double-quoted (with Go escapes) and keys without a value decode to true.
+A line must contain at least one key=value pair to count as logfmt, which keeps ordinary prose from being mistaken for a record.
+*/
+func decodeLogfmt(line string) (map[string]any, bool) {
+	record := map[string]any{}
+	sawPair := false
+	i := 0
+	for i < len(line) {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '"' {
+			i++
+		}
+		key := line[start:i]
+		if key == "" {
+			return nil, false
+		}
+		if i == len(line) || line[i] == ' ' {
+			record[key] = true
+			continue
+		}
+		if line[i] == '"' {
+			return nil, false
+		}
+		i++ // skip '='
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, false
+			}
+			value, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil, false
+			}
+			record[key] = value
+			i = end + 1
+		} else {
+			start = i
+			for i < len(line) && line[i] != ' ' {
+				i++
+			}
+			record[key] = line[start:i]
+		}
+		sawPair = true
+	}
+	return record, sawPair
+}
@@ -0,0 +1,134 @@
+package gbytes_test
+
+import (
+	"log/slog"
+	"time"
+
+	. "github.com/onsi/gomega/gbytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SayJSONLine", func() {
+	var buffer *Buffer
+
+	BeforeEach(func() {
+		buffer = NewBuffer()
+	})
+
+	It("errors when not passed a Buffer or BufferProvider", func() {
+		_, err := SayJSONLine(HaveKey("level")).Match("foo")
+		Expect(err).Should(MatchError(ContainSubstring("SayJSONLine must be passed a *gbytes.Buffer or BufferProvider")))
+	})
+
+	It("matches JSON lines and advances the read cursor past the matching line", func() {
+		buffer.Write([]byte(`{"level":"INFO","msg":"starting"}` + "\n"))
+		buffer.Write([]byte(`{"level":"ERROR","msg":"boom","attempt":3}` + "\n"))
+		buffer.Write([]byte(`{"level":"ERROR","msg":"bang"}` + "\n"))
+
+		Expect(buffer).Should(SayJSONLine(HaveKeyWithValue("level", "ERROR")))
+		Expect(buffer).Should(SayJSONLine(HaveKeyWithValue("msg", "bang")))
+		Expect(buffer).ShouldNot(SayJSONLine(HaveKeyWithValue("msg", "boom")))
+		Expect(buffer).ShouldNot(SayJSONLine(HaveKey("level")))
+	})
+
+	It("decodes nested values", func() {
+		buffer.Write([]byte(`{"level":"INFO","request":{"method":"GET","status":200}}` + "\n"))
+		Expect(buffer).Should(SayJSONLine(HaveKeyWithValue("request", HaveKeyWithValue("status", BeNumerically("==", 200)))))
+	})
+
+	It("matches logfmt lines", func() {
+		buffer.Write([]byte(`time=2024-01-01T00:00:00Z level=WARN msg="disk \"almost\" full" used=93 verbose` + "\n"))
+		Expect(buffer).Should(SayJSONLine(And(
+			HaveKeyWithValue("level", "WARN"),
+			HaveKeyWithValue("msg", `disk "almost" full`),
+			HaveKeyWithValue("used", "93"),
+			HaveKeyWithValue("verbose", true),
+		)))
+	})
+
+	It("works with slog's handlers", func() {
+		slog.New(slog.NewJSONHandler(buffer, nil)).Error("json boom", "attempt", 3)
+		slog.New(slog.NewTextHandler(buffer, nil)).Warn("text boom", "attempt", 4)
+
+		Expect(buffer).Should(SayJSONLine(And(HaveKeyWithValue("level", "ERROR"), HaveKeyWithValue("msg", "json boom"), HaveKeyWithValue("attempt", 3.0))))
+		Expect(buffer).Should(SayJSONLine(And(HaveKeyWithValue("level", "WARN"), HaveKeyWithValue("msg", "text boom"), HaveKeyWithValue("attempt", "4"))))
+	})
+
+	It("skips lines that aren't structured", func() {
+		buffer.Write([]byte("plain old prose\n\n{not json\n" + `level=INFO msg=hi` + "\n"))
+		Expect(buffer).Should(SayJSONLine(HaveKeyWithValue("msg", "hi")))
+	})
+
+	It("skips records the matcher errors on", func() {
+		buffer.Write([]byte(`{"level":40}` + "\n" + `{"level":"ERROR"}` + "\n"))
+		Expect(buffer).Should(SayJSONLine(HaveKeyWithValue("level", MatchRegexp("ERR"))))
+	})
+
+	It("waits for complete lines until the buffer is closed", func() {
+		buffer.Write([]byte(`{"level":"ERROR"}`))
+		Expect(buffer).ShouldNot(SayJSONLine(HaveKey("level")))
+		buffer.Close()
+		Expect(buffer).Should(SayJSONLine(HaveKey("level")))
+	})
+
+	It("works with Eventually", func() {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			buffer.Write([]byte(`{"level":"ERROR"}` + "\n"))
+		}()
+		Eventually(buffer).Should(SayJSONLine(HaveKeyWithValue("level", "ERROR")))
+	})
+
+	It("works with BufferProviders", func() {
+		buffer.Write([]byte(`{"level":"ERROR"}` + "\n"))
+		Expect(&speaker{buffer: buffer}).Should(SayJSONLine(HaveKey("level")))
+	})
+
+	It("aborts Eventually when the buffer is closed", func() {
+		buffer.Close()
+		t := time.Now()
+		failures := InterceptGomegaFailures(func() {
+			Eventually(buffer).Should(SayJSONLine(HaveKey("level")))
+		})
+		Expect(failures).Should(HaveLen(1))
+		Expect(time.Since(t)).Should(BeNumerically("<", 500*time.Millisecond))
+	})
+
+	Describe("failure messages", func() {
+		It("shows the unread lines and why the last record failed", func() {
+			buffer.Write([]byte(`{"level":"INFO"}` + "\n"))
+			failures := InterceptGomegaFailures(func() {
+				Expect(buffer).Should(SayJSONLine(HaveKeyWithValue("level", "ERROR")))
+			})
+			Expect(failures).Should(ConsistOf(HavePrefix("Got stuck at:\n    {\"level\":\"INFO\"}\n    \nWaiting for a JSON or logfmt line satisfying the matcher.  The last record failed with:\n    Expected\n")))
+		})
+
+		It("explains when there are no records", func() {
+			buffer.Write([]byte("hello\n"))
+			failures := InterceptGomegaFailures(func() {
+				Expect(buffer).Should(SayJSONLine(HaveKey("level")))
+			})
+			Expect(failures).Should(ConsistOf("Got stuck at:\n    hello\n    \nWaiting for a JSON or logfmt line satisfying the matcher.  No records were found."))
+		})
+
+		It("shows matcher errors", func() {
+			buffer.Write([]byte(`{"level":40}` + "\n"))
+			failures := InterceptGomegaFailures(func() {
+				Expect(buffer).Should(SayJSONLine(HaveKeyWithValue("level", MatchRegexp("ERR"))))
+			})
+			Expect(failures).Should(HaveLen(1))
+			Expect(failures[0]).Should(ContainSubstring("The last record failed with:\n    HaveKeyWithValue's value matcher failed with:\n        RegExp matcher requires a string or stringer."))
+		})
+
+		It("shows the matching line when negated", func() {
+			buffer.Write([]byte(`{"level":"ERROR"}` + "\n"))
+			failures := InterceptGomegaFailures(func() {
+				Expect(buffer).ShouldNot(SayJSONLine(HaveKey("level")))
+			})
+			Expect(failures).Should(HaveLen(1))
+			Expect(failures[0]).Should(HavePrefix("Saw:\n    {\"level\":\"ERROR\"}\nWhich satisfies the matcher:\n    Expected\n"))
+		})
+	})
+})
@@ -52,6 +52,11 @@ type sayMatcher struct {
 }
 
 func (m *sayMatcher) buffer(actual any) (*Buffer, bool) {
+	return bufferFor(actual)
+}
+
+// bufferFor extracts the *Buffer from a *Buffer or BufferProvider
+func bufferFor(actual any) (*Buffer, bool) {
 	var buffer *Buffer
 
 	switch x := actual.(type) {
@@ -95,6 +100,11 @@ func (m *sayMatcher) NegatedFailureMessage(actual any) (message string) {
 }
 
 func (m *sayMatcher) MatchMayChangeInTheFuture(actual any) bool {
+	return bufferMayChange(actual)
+}
+
+// bufferMayChange tells Eventually to stop polling once the buffer has been closed
+func bufferMayChange(actual any) bool {
 	switch x := actual.(type) {
 	case *Buffer:
 		return !x.Closed()
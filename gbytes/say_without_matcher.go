@@ -0,0 +1,92 @@
+package gbytes
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/onsi/gomega/format"
+)
+
+/*
+SayWithout is a Gomega matcher that operates on gbytes.Buffers:
+
+	Eventually(buffer).Should(SayWithout("migration complete", "(?i)error"))
+
+will succeed if the unread portion of the buffer matches the regular expression pattern, and the regular expression forbidden does not match
+anything in between the read cursor and the start of that match.
+
+When SayWithout succeeds, it fast forwards the gbytes.Buffer's read cursor to just after the match for pattern.  Once forbidden has been seen
+before pattern, SayWithout can never succeed, so it tells Eventually to abort.
+
+In addition to gbytes.Buffers, SayWithout can operate on objects that implement the gbytes.BufferProvider interface.
+*/
+func SayWithout(pattern string, forbidden string) *sayWithoutMatcher {
+	return &sayWithoutMatcher{
+		re:        regexp.MustCompile(pattern),
+		forbidden: regexp.MustCompile(forbidden),
+	}
+}
+
+type sayWithoutMatcher struct {
+	re              *regexp.Regexp
+	forbidden       *regexp.Regexp
+	receivedSayings []byte
+	sawForbidden    []byte
+}
+
+func (m *sayWithoutMatcher) Match(actual any) (success bool, err error) {
+	buffer, ok := bufferFor(actual)
+	if !ok {
+		return false, fmt.Errorf("SayWithout must be passed a *gbytes.Buffer or BufferProvider.  Got:\n%s", format.Object(actual, 1))
+	}
+
+	didSay, sayings := buffer.didSayFunc(func(unread []byte) int {
+		m.sawForbidden = nil
+		loc := m.re.FindIndex(unread)
+		searchForForbidden := unread
+		if loc != nil {
+			searchForForbidden = unread[:loc[0]]
+		}
+		if forbiddenLoc := m.forbidden.FindIndex(searchForForbidden); forbiddenLoc != nil {
+			m.sawForbidden = unread[forbiddenLoc[0]:forbiddenLoc[1]]
+			return -1
+		}
+		if loc == nil {
+			return -1
+		}
+		return loc[1]
+	})
+	m.receivedSayings = sayings
+
+	return didSay, nil
+}
+
+func (m *sayWithoutMatcher) FailureMessage(actual any) (message string) {
+	if m.sawForbidden != nil {
+		return fmt.Sprintf(
+			"Saw:\n%s\nWhich matches the forbidden:\n%s\nBefore:\n%s",
+			format.IndentString(string(m.receivedSayings), 1),
+			format.IndentString(m.forbidden.String(), 1),
+			format.IndentString(m.re.String(), 1),
+		)
+	}
+	return fmt.Sprintf(
+		"Got stuck at:\n%s\nWaiting for:\n%s\nWithout seeing:\n%s",
+		format.IndentString(string(m.receivedSayings), 1),
+		format.IndentString(m.re.String(), 1),
+		format.IndentString(m.forbidden.String(), 1),
+	)
+}
+
+func (m *sayWithoutMatcher) NegatedFailureMessage(actual any) (message string) {
+	return fmt.Sprintf(
+		"Saw:\n%s\nWhich matches the unexpected:\n%s\nWithout the forbidden:\n%s",
+		format.IndentString(string(m.receivedSayings), 1),
+		format.IndentString(m.re.String(), 1),
+		format.IndentString(m.forbidden.String(), 1),
+	)
+}
+
+func (m *sayWithoutMatcher) MatchMayChangeInTheFuture(actual any) bool {
+	return m.sawForbidden == nil && bufferMayChange(actual)
+}
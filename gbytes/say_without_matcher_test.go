@@ -0,0 +1,80 @@
+package gbytes_test
+
+import (
+	"time"
+
+	. "github.com/onsi/gomega/gbytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SayWithout", func() {
+	var buffer *Buffer
+
+	BeforeEach(func() {
+		buffer = NewBuffer()
+	})
+
+	It("errors when not passed a Buffer or BufferProvider", func() {
+		_, err := SayWithout("a", "b").Match("foo")
+		Expect(err).Should(MatchError(ContainSubstring("SayWithout must be passed a *gbytes.Buffer or BufferProvider")))
+	})
+
+	It("succeeds when the pattern appears without the forbidden pattern before it", func() {
+		buffer.Write([]byte("migrating\nmigration complete\nerror: later\n"))
+		Expect(buffer).Should(SayWithout("migration complete", "(?i)error"))
+		Expect(buffer).ShouldNot(SayWithout("migration complete", "(?i)error"))
+		Expect(buffer).Should(Say("error: later"))
+	})
+
+	It("fails when the forbidden pattern appears first, leaving the read cursor untouched", func() {
+		buffer.Write([]byte("migrating\nERROR: disk full\nmigration complete\n"))
+		Expect(buffer).ShouldNot(SayWithout("migration complete", "(?i)error"))
+		Expect(buffer).Should(Say("migrating"))
+	})
+
+	It("aborts Eventually once the forbidden pattern has been seen", func() {
+		buffer.Write([]byte("ERROR: disk full\n"))
+		t := time.Now()
+		failures := InterceptGomegaFailures(func() {
+			Eventually(buffer, time.Minute).Should(SayWithout("migration complete", "ERROR"))
+		})
+		Expect(failures).Should(HaveLen(1))
+		Expect(time.Since(t)).Should(BeNumerically("<", time.Second))
+	})
+
+	It("works with BufferProviders and Eventually", func() {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			buffer.Write([]byte("migration complete\n"))
+		}()
+		Eventually(&speaker{buffer: buffer}).Should(SayWithout("migration complete", "ERROR"))
+	})
+
+	Describe("failure messages", func() {
+		It("shows what it is waiting for", func() {
+			buffer.Write([]byte("migrating\n"))
+			failures := InterceptGomegaFailures(func() {
+				Expect(buffer).Should(SayWithout("complete", "ERROR"))
+			})
+			Expect(failures).Should(ConsistOf("Got stuck at:\n    migrating\n    \nWaiting for:\n    complete\nWithout seeing:\n    ERROR"))
+		})
+
+		It("shows the forbidden pattern when it was seen", func() {
+			buffer.Write([]byte("ERROR\ncomplete\n"))
+			failures := InterceptGomegaFailures(func() {
+				Expect(buffer).Should(SayWithout("complete", "ERROR"))
+			})
+			Expect(failures).Should(ConsistOf("Saw:\n    ERROR\n    complete\n    \nWhich matches the forbidden:\n    ERROR\nBefore:\n    complete"))
+		})
+
+		It("shows the patterns when negated", func() {
+			buffer.Write([]byte("complete\n"))
+			failures := InterceptGomegaFailures(func() {
+				Expect(buffer).ShouldNot(SayWithout("complete", "ERROR"))
+			})
+			Expect(failures).Should(ConsistOf("Saw:\n    complete\n    \nWhich matches the unexpected:\n    complete\nWithout the forbidden:\n    ERROR"))
+		})
+	})
+})
@@ -48,24 +48,7 @@ func BuildIn(gopath string, packagePath string, args ...string) (compiledPath st
 }
 
 func doBuild(gopath, packagePath string, env []string, args ...string) (compiledPath string, err error) {
-	executable, err := newExecutablePath(gopath, packagePath)
-	if err != nil {
-		return "", err
-	}
-
-	cmdArgs := append([]string{"build"}, args...)
-	cmdArgs = append(cmdArgs, "-o", executable, packagePath)
-
-	build := exec.Command("go", cmdArgs...)
-	build.Env = replaceGoPath(os.Environ(), gopath)
-	build.Env = append(build.Env, env...)
-
-	output, err := build.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("Failed to build %s:\n\nError:\n%s\n\nOutput:\n%s", packagePath, err, string(output))
-	}
-
-	return executable, nil
+	return compile(gopath, packagePath, env, []string{"build"}, args)
 }
 
 /*
@@ -166,24 +149,49 @@ func doGet(gopath, packagePath string, env []string, args ...string) error {
 }
 
 func doCompileTest(gopath, packagePath string, env []string, args ...string) (compiledPath string, err error) {
-	executable, err := newExecutablePath(gopath, packagePath, ".test")
+	return compile(gopath, packagePath, env, []string{"test", "-c"}, args, ".test")
+}
+
+// compile runs the go subcommand (build or test -c) to compile packagePath, going through the build cache when it is enabled
+func compile(gopath, packagePath string, env []string, subcommand []string, args []string, suffixes ...string) (compiledPath string, err error) {
+	if len(gopath) == 0 {
+		return "", errors.New("$GOPATH not provided when building " + packagePath)
+	}
+
+	buildEnv := append(replaceGoPath(os.Environ(), gopath), env...)
+
+	if cacheDir := currentBuildCacheDir(); cacheDir != "" {
+		//if we can't work out the cache key (e.g. because the package doesn't exist) we fall back to go build, which reports the problem best
+		if key, err := buildCacheKey(buildEnv, packagePath, env, subcommand, args); err == nil {
+			return cachedCompile(filepath.Join(cacheDir, key), executableName(packagePath), func(output string) error {
+				return runGoCompile(buildEnv, packagePath, subcommand, args, output)
+			})
+		}
+	}
+
+	executable, err := newExecutablePath(gopath, packagePath, suffixes...)
 	if err != nil {
 		return "", err
 	}
 
-	cmdArgs := append([]string{"test", "-c"}, args...)
-	cmdArgs = append(cmdArgs, "-o", executable, packagePath)
+	if err := runGoCompile(buildEnv, packagePath, subcommand, args, executable); err != nil {
+		return "", err
+	}
+	return executable, nil
+}
+
+func runGoCompile(buildEnv []string, packagePath string, subcommand []string, args []string, output string) error {
+	cmdArgs := append(append([]string{}, subcommand...), args...)
+	cmdArgs = append(cmdArgs, "-o", output, packagePath)
 
 	build := exec.Command("go", cmdArgs...)
-	build.Env = replaceGoPath(os.Environ(), gopath)
-	build.Env = append(build.Env, env...)
+	build.Env = buildEnv
 
-	output, err := build.CombinedOutput()
+	out, err := build.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("Failed to build %s:\n\nError:\n%s\n\nOutput:\n%s", packagePath, err, string(output))
+		return fmt.Errorf("Failed to build %s:\n\nError:\n%s\n\nOutput:\n%s", packagePath, err, string(out))
 	}
-
-	return executable, nil
+	return nil
 }
 
 func replaceGoPath(environ []string, newGoPath string) []string {
@@ -206,13 +214,15 @@ func newExecutablePath(gopath, packagePath string, suffixes ...string) (string,
 		return "", errors.New("$GOPATH not provided when building " + packagePath)
 	}
 
-	executable := filepath.Join(tmpDir, path.Base(packagePath))
+	return filepath.Join(tmpDir, executableName(packagePath)), nil
+}
 
+func executableName(packagePath string) string {
+	name := path.Base(packagePath)
 	if runtime.GOOS == "windows" {
-		executable += ".exe"
+		name += ".exe"
 	}
-
-	return executable, nil
+	return name
 }
 
 /*
@@ -0,0 +1,266 @@
+package gexec
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	buildCacheMu  sync.Mutex
+	buildCacheDir string
+)
+
+/*
+EnableBuildCache makes Build, BuildWithEnvironment, BuildIn, BuildAll, BuildAllWithEnvironment and the CompileTest family reuse previously compiled binaries.
+
+Binaries are stored in dir, keyed on everything that goes into the build: the package path, the build arguments and environment, the
+Go toolchain and its configuration, and the contents of the package's source files (and those of its non-standard-library dependencies).
+When any of these change, the package is rebuilt.  Pass in "" to use a directory in the user's cache directory (see os.UserCacheDir).
+
+The cache is safe to share between processes, so Ginkgo's parallel processes (and subsequent test runs) only compile each binary once:
+
+	var _ = SynchronizedBeforeSuite(func() {
+		Expect(gexec.EnableBuildCache("")).To(Succeed())
+	}, ...)
+
+Binaries returned from the cache live in dir, not in a temporary directory, so CleanupBuildArtifacts does not remove them.  Treat them as read-only.
+*/
+func EnableBuildCache(dir string) error {
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return fmt.Errorf("gexec: failed to locate the user cache directory: %w", err)
+		}
+		dir = filepath.Join(userCacheDir, "gomega-gexec")
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("gexec: failed to create the build cache: %w", err)
+	}
+
+	buildCacheMu.Lock()
+	defer buildCacheMu.Unlock()
+	buildCacheDir = dir
+	return nil
+}
+
+/*
+DisableBuildCache turns the build cache off again.  It leaves the cached binaries in place.
+*/
+func DisableBuildCache() {
+	buildCacheMu.Lock()
+	defer buildCacheMu.Unlock()
+	buildCacheDir = ""
+}
+
+func currentBuildCacheDir() string {
+	buildCacheMu.Lock()
+	defer buildCacheMu.Unlock()
+	return buildCacheDir
+}
+
+// cachedCompile returns the binary called name from entryDir, calling compile to build it there first if necessary.
+// compile writes to a private directory that is then renamed into place, so concurrent builds of the same entry are safe.
+func cachedCompile(entryDir string, name string, compile func(output string) error) (string, error) {
+	target := filepath.Join(entryDir, name)
+	if _, err := os.Stat(target); err == nil {
+		return target, nil
+	}
+
+	if err := os.MkdirAll(entryDir, 0o755); err != nil {
+		return "", fmt.Errorf("gexec: failed to create the build cache entry: %w", err)
+	}
+	scratch, err := os.MkdirTemp(entryDir, "building")
+	if err != nil {
+		return "", fmt.Errorf("gexec: failed to create the build cache entry: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	if err := compile(filepath.Join(scratch, name)); err != nil {
+		return "", err
+	}
+	if err := os.Rename(filepath.Join(scratch, name), target); err != nil {
+		//another process may have beaten us to it
+		if _, statErr := os.Stat(target); statErr != nil {
+			return "", fmt.Errorf("gexec: failed to store the binary in the build cache: %w", err)
+		}
+	}
+	return target, nil
+}
+
+// listedPackage holds the fields of `go list -json` that buildCacheKey needs
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	Standard   bool
+	Module     *struct {
+		GoMod string
+	}
+	GoFiles, CgoFiles, CFiles, CXXFiles, MFiles, HFiles, FFiles, SFiles, SwigFiles, SwigCXXFiles, SysoFiles []string
+	EmbedFiles, TestGoFiles, XTestGoFiles, TestEmbedFiles, XTestEmbedFiles                                  []string
+}
+
+func (p listedPackage) files() []string {
+	files := [][]string{p.GoFiles, p.CgoFiles, p.CFiles, p.CXXFiles, p.MFiles, p.HFiles, p.FFiles, p.SFiles, p.SwigFiles, p.SwigCXXFiles, p.SysoFiles,
+		p.EmbedFiles, p.TestGoFiles, p.XTestGoFiles, p.TestEmbedFiles, p.XTestEmbedFiles}
+	out := []string{}
+	for _, f := range files {
+		out = append(out, f...)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// buildCacheKey hashes everything that goes into compiling packagePath
+func buildCacheKey(buildEnv []string, packagePath string, env []string, subcommand []string, args []string) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "subcommand: %q\npackage: %q\nargs: %q\nenv: %q\n", subcommand, packagePath, args, env)
+
+	goEnv, err := goCommandOutput(buildEnv, "env", "-json")
+	if err != nil {
+		return "", err
+	}
+	var goEnvVars map[string]string
+	if err := json.Unmarshal(goEnv, &goEnvVars); err != nil {
+		return "", err
+	}
+	//GOGCCFLAGS embeds a randomly named temporary directory
+	delete(goEnvVars, "GOGCCFLAGS")
+	fmt.Fprintf(hash, "go env: %v\n", goEnvVars)
+
+	listArgs := []string{"list", "-deps", "-json"}
+	if subcommand[0] == "test" {
+		listArgs = append(listArgs, "-test")
+	}
+	listArgs = append(append(listArgs, args...), packagePath)
+	listing, err := goCommandOutput(buildEnv, listArgs...)
+	if err != nil {
+		return "", err
+	}
+
+	hashedGoMods := map[string]bool{}
+	decoder := json.NewDecoder(bytes.NewReader(listing))
+	for {
+		var pkg listedPackage
+		if err := decoder.Decode(&pkg); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", err
+		}
+		//the standard library is covered by the toolchain's version in go env
+		if pkg.Standard {
+			continue
+		}
+		fmt.Fprintf(hash, "package: %s %s\n", pkg.ImportPath, pkg.Dir)
+		for _, file := range pkg.files() {
+			//go list -test reports the generated test main package's sources by absolute path; they're derived from the test files we hash anyway
+			if filepath.IsAbs(file) {
+				continue
+			}
+			if err := hashFile(hash, filepath.Join(pkg.Dir, file)); err != nil {
+				return "", err
+			}
+		}
+		if pkg.Module != nil && pkg.Module.GoMod != "" && !hashedGoMods[pkg.Module.GoMod] {
+			hashedGoMods[pkg.Module.GoMod] = true
+			if err := hashFile(hash, pkg.Module.GoMod); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func goCommandOutput(buildEnv []string, args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Env = buildEnv
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go %s failed: %w\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return output, nil
+}
+
+func hashFile(hash io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintf(hash, "file: %s\n", path)
+	_, err = io.Copy(hash, f)
+	return err
+}
+
+/*
+BuildAll compiles several packages concurrently, using go build just like Build.  packages maps names of your choosing to package paths, and
+BuildAll returns a map from the same names to the paths of the compiled binaries.  args are passed on to every `go build`:
+
+	binaries, err := gexec.BuildAll(map[string]string{
+		"server": "github.com/acme/app/cmd/server",
+		"client": "github.com/acme/app/cmd/client",
+	})
+	Expect(err).NotTo(HaveOccurred())
+	session, err := gexec.Start(exec.Command(binaries["server"]), GinkgoWriter, GinkgoWriter)
+
+BuildAll waits for every build to finish.  If any fail, it returns an error describing every failure and a map containing only the binaries that
+did build.  BuildAll goes through the build cache when it is enabled (see EnableBuildCache).
+*/
+func BuildAll(packages map[string]string, args ...string) (map[string]string, error) {
+	return buildAll(packages, nil, args...)
+}
+
+/*
+BuildAllWithEnvironment is identical to BuildAll but allows you to specify env vars to be set at build time (see BuildWithEnvironment).
+*/
+func BuildAllWithEnvironment(packages map[string]string, env []string, args ...string) (map[string]string, error) {
+	return buildAll(packages, env, args...)
+}
+
+func buildAll(packages map[string]string, env []string, args ...string) (map[string]string, error) {
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	compiledPaths := make([]string, len(names))
+	errs := make([]error, len(names))
+	wg := &sync.WaitGroup{}
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			compiledPaths[i], errs[i] = BuildWithEnvironment(packages[name], env, args...)
+		}()
+	}
+	wg.Wait()
+
+	out := map[string]string{}
+	failures := []error{}
+	for i, name := range names {
+		if errs[i] != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", name, errs[i]))
+			continue
+		}
+		out[name] = compiledPaths[i]
+	}
+	return out, errors.Join(failures...)
+}
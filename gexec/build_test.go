@@ -123,6 +123,162 @@ var _ = Describe(".BuildIn", func() {
 	})
 })
 
+var _ = Describe(".BuildAll", func() {
+	It("compiles all the packages", func() {
+		compiledPaths, err := gexec.BuildAll(map[string]string{
+			"firefly":       packagePath,
+			"firefly-again": packagePath,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(compiledPaths).Should(HaveLen(2))
+		Expect(compiledPaths["firefly"]).Should(BeAnExistingFile())
+		Expect(compiledPaths["firefly-again"]).Should(BeAnExistingFile())
+		Expect(compiledPaths["firefly"]).ShouldNot(Equal(compiledPaths["firefly-again"]))
+	})
+
+	It("passes the args on to go build", func() {
+		_, err := gexec.BuildAll(map[string]string{"firefly": packagePath}, "-not-a-flag")
+		Expect(err).Should(MatchError(ContainSubstring("-not-a-flag")))
+	})
+
+	It("reports every failure together, and returns the binaries that did build", func() {
+		compiledPaths, err := gexec.BuildAll(map[string]string{
+			"firefly":  packagePath,
+			"serenity": "./_fixture/serenity",
+			"reaver":   "./_fixture/reaver",
+		})
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("reaver: Failed to build ./_fixture/reaver"))
+		Expect(err.Error()).Should(ContainSubstring("serenity: Failed to build ./_fixture/serenity"))
+		Expect(compiledPaths).Should(HaveKey("firefly"))
+		Expect(compiledPaths).Should(HaveLen(1))
+	})
+})
+
+var _ = Describe(".BuildAllWithEnvironment", func() {
+	It("compiles all the packages with the specified env vars", func() {
+		compiledPaths, err := gexec.BuildAllWithEnvironment(map[string]string{"firefly": packagePath}, []string{"CGO_ENABLED=0"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(compiledPaths["firefly"]).Should(BeAnExistingFile())
+
+		_, err = gexec.BuildAllWithEnvironment(map[string]string{"firefly": packagePath}, []string{"GOOS=not-an-os"})
+		Expect(err).Should(MatchError(ContainSubstring("not-an-os")))
+	})
+})
+
+var _ = Describe("the build cache", func() {
+	var cacheDir string
+	var sourceDir string
+
+	writeSource := func(quote string) {
+		Expect(os.WriteFile(filepath.Join(sourceDir, "main.go"), []byte(fmt.Sprintf("package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(%q) }\n", quote)), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(sourceDir, "main_test.go"), []byte("package main\n\nimport \"testing\"\n\nfunc TestNothing(t *testing.T) {}\n"), 0o644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		cacheDir = GinkgoT().TempDir()
+		Expect(gexec.EnableBuildCache(cacheDir)).To(Succeed())
+		DeferCleanup(gexec.DisableBuildCache)
+
+		var err error
+		sourceDir, err = os.MkdirTemp("./_fixture", "cached")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.RemoveAll, sourceDir)
+		writeSource("Shiny.")
+	})
+
+	packagePath := func() string {
+		return "./" + filepath.ToSlash(sourceDir)
+	}
+
+	It("reuses binaries from the cache", func() {
+		compiledPath, err := gexec.Build(packagePath())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(compiledPath).Should(BeAnExistingFile())
+		Expect(compiledPath).Should(HavePrefix(cacheDir))
+
+		gexec.CleanupBuildArtifacts()
+		Expect(compiledPath).Should(BeAnExistingFile())
+
+		cachedPath, err := gexec.Build(packagePath())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cachedPath).Should(Equal(compiledPath))
+	})
+
+	It("rebuilds when the source changes", func() {
+		compiledPath, err := gexec.Build(packagePath())
+		Expect(err).NotTo(HaveOccurred())
+
+		writeSource("Gorram it.")
+		rebuiltPath, err := gexec.Build(packagePath())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rebuiltPath).ShouldNot(Equal(compiledPath))
+		Expect(compiledPath).Should(BeAnExistingFile())
+
+		writeSource("Shiny.")
+		Expect(gexec.Build(packagePath())).Should(Equal(compiledPath))
+	})
+
+	It("rebuilds when the args or environment change", func() {
+		compiledPath, err := gexec.Build(packagePath())
+		Expect(err).NotTo(HaveOccurred())
+
+		withArgs, err := gexec.Build(packagePath(), "-tags", "shiny")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(withArgs).ShouldNot(Equal(compiledPath))
+
+		withEnv, err := gexec.BuildWithEnvironment(packagePath(), []string{"CGO_ENABLED=0"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(withEnv).ShouldNot(Equal(compiledPath))
+		Expect(withEnv).ShouldNot(Equal(withArgs))
+	})
+
+	It("caches test binaries separately", func() {
+		compiledPath, err := gexec.Build(packagePath())
+		Expect(err).NotTo(HaveOccurred())
+
+		testPath, err := gexec.CompileTest(packagePath())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testPath).Should(HavePrefix(cacheDir))
+		Expect(testPath).ShouldNot(Equal(compiledPath))
+		Expect(gexec.CompileTest(packagePath())).Should(Equal(testPath))
+	})
+
+	It("is used by BuildAll", func() {
+		compiledPaths, err := gexec.BuildAll(map[string]string{"a": packagePath(), "b": packagePath()})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(compiledPaths["a"]).Should(HavePrefix(cacheDir))
+		Expect(compiledPaths["a"]).Should(Equal(compiledPaths["b"]))
+	})
+
+	It("still reports build failures", func() {
+		_, err := gexec.Build("./_fixture/serenity")
+		Expect(err).Should(MatchError(ContainSubstring("Failed to build ./_fixture/serenity")))
+
+		Expect(os.WriteFile(filepath.Join(sourceDir, "main.go"), []byte("package main\n\nfunc main() { undefined() }\n"), 0o644)).To(Succeed())
+		_, err = gexec.Build(packagePath())
+		Expect(err).Should(MatchError(ContainSubstring("undefined")))
+	})
+
+	It("does not create temporary directories for cached binaries", func() {
+		_, err := gexec.Build(packagePath())
+		Expect(err).NotTo(HaveOccurred())
+		gexec.CleanupBuildArtifacts()
+
+		before, _ := filepath.Glob(filepath.Join(os.TempDir(), "gexec_artifacts*"))
+		_, err = gexec.Build(packagePath())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filepath.Glob(filepath.Join(os.TempDir(), "gexec_artifacts*"))).Should(ConsistOf(before))
+	})
+
+	It("stops being used once disabled", func() {
+		gexec.DisableBuildCache()
+		compiledPath, err := gexec.Build(packagePath())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(compiledPath).ShouldNot(HavePrefix(cacheDir))
+	})
+})
+
 func copyFile(source, directory, basename string) {
 	Expect(os.MkdirAll(directory, 0755)).To(Succeed())
 	content, err := gutil.ReadFile(source)
@@ -0,0 +1,67 @@
+package gexec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+)
+
+/*
+HaveNoProcessGroupMembers succeeds if the session's command has exited and no process remains in its process group:
+
+	session.Kill()
+	Eventually(session).Should(HaveNoProcessGroupMembers())
+
+HaveNoProcessGroupMembers requires a session whose command was started in its own process group - see InProcessGroup.  It errors for any other session.
+Note that it only finds the command's descendants that stayed in its process group: descendants that moved to a process group (or session) of their
+own are not detected.
+
+When processes remain, the failure message lists them (on platforms that provide /proc).  Zombie processes that have exited but not yet been reaped
+do not count as remaining.
+*/
+func HaveNoProcessGroupMembers() *noProcessGroupMembersMatcher {
+	return &noProcessGroupMembersMatcher{}
+}
+
+type noProcessGroupMembersMatcher struct {
+	exited    bool
+	pgid      int
+	remaining []string
+}
+
+func (m *noProcessGroupMembersMatcher) Match(actual any) (success bool, err error) {
+	session, ok := actual.(*Session)
+	if !ok {
+		return false, fmt.Errorf("HaveNoProcessGroupMembers must be passed a *gexec.Session.  Got:\n%s", format.Object(actual, 1))
+	}
+	if !session.processGroup {
+		return false, fmt.Errorf("HaveNoProcessGroupMembers requires a session whose command was started in its own process group.  Use gexec.InProcessGroup(command) before starting it.")
+	}
+
+	m.exited = session.ExitCode() != -1
+	if !m.exited {
+		return false, nil
+	}
+
+	m.pgid = session.Command.Process.Pid
+	m.remaining = processGroupMembers(m.pgid)
+	if len(m.remaining) == 0 {
+		session.markProcessGroupGone()
+	}
+	return len(m.remaining) == 0, nil
+}
+
+func (m *noProcessGroupMembersMatcher) FailureMessage(actual any) (message string) {
+	if !m.exited {
+		return "Expected process to exit.  It did not."
+	}
+	return fmt.Sprintf("Expected process group %d to have no remaining processes.  It does.\nRemaining processes:\n%s", m.pgid, format.IndentString(strings.Join(m.remaining, "\n"), 1))
+}
+
+func (m *noProcessGroupMembersMatcher) NegatedFailureMessage(actual any) (message string) {
+	if !m.exited {
+		return "Expected process to exit.  It did not."
+	}
+	return fmt.Sprintf("Expected process group %d to have remaining processes.  It does not.", m.pgid)
+}
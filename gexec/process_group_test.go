@@ -0,0 +1,116 @@
+//go:build !windows
+// +build !windows
+
+package gexec_test
+
+import (
+	"os/exec"
+	"syscall"
+
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Process groups", func() {
+	var session *Session
+
+	start := func(command *exec.Cmd) {
+		var err error
+		session, err = Start(command, nil, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		DeferCleanup(func() { session.Kill().Wait() })
+	}
+
+	It("signals every process in the group", func() {
+		start(InProcessGroup(exec.Command("sh", "-c", "sleep 100 >/dev/null 2>&1 & echo started; wait")))
+		Eventually(session).Should(Say("started"))
+		session.Terminate()
+		Eventually(session).Should(Exit())
+		Eventually(session).Should(HaveNoProcessGroupMembers())
+	})
+
+	It("signals processes that outlive the command", func() {
+		start(InProcessGroup(exec.Command("sh", "-c", "sleep 100 >/dev/null 2>&1 & echo started")))
+		Eventually(session).Should(Exit(0))
+		Expect(session).Should(Say("started"))
+
+		Expect(session).ShouldNot(HaveNoProcessGroupMembers())
+		session.Kill()
+		Eventually(session).Should(HaveNoProcessGroupMembers())
+	})
+
+	It("only signals the group of commands passed to InProcessGroup", func() {
+		command := exec.Command("sh", "-c", "sleep 100 >/dev/null 2>&1 & echo started")
+		command.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		start(command)
+		pgid := session.Command.Process.Pid
+		DeferCleanup(syscall.Kill, -pgid, syscall.SIGKILL)
+		Eventually(session).Should(Exit(0))
+
+		session.Kill()
+		Consistently(func() error { return syscall.Kill(-pgid, 0) }, "100ms").Should(Succeed())
+		_, err := HaveNoProcessGroupMembers().Match(session)
+		Expect(err).Should(MatchError(ContainSubstring("gexec.InProcessGroup")))
+	})
+
+	It("keeps working once the group is gone", func() {
+		start(InProcessGroup(exec.Command("true")))
+		Eventually(session).Should(Exit(0))
+		Eventually(session).Should(HaveNoProcessGroupMembers())
+		Expect(session.Kill()).Should(Equal(session))
+		Expect(session).Should(HaveNoProcessGroupMembers())
+	})
+
+	It("waits for the whole group in KillAndWait", func() {
+		start(InProcessGroup(exec.Command("sh", "-c", "trap '' TERM; sleep 100 >/dev/null 2>&1 & echo started")))
+		Eventually(session).Should(Exit(0))
+		KillAndWait()
+		Expect(session).Should(HaveNoProcessGroupMembers())
+	})
+
+	Describe("HaveNoProcessGroupMembers", func() {
+		It("fails when the command has not exited", func() {
+			start(InProcessGroup(exec.Command("sleep", "100")))
+			failures := InterceptGomegaFailures(func() {
+				Expect(session).Should(HaveNoProcessGroupMembers())
+			})
+			Expect(failures).Should(ConsistOf("Expected process to exit.  It did not."))
+		})
+
+		It("lists the remaining processes", func() {
+			start(InProcessGroup(exec.Command("sh", "-c", "sleep 100 >/dev/null 2>&1 & echo started")))
+			Eventually(session).Should(Exit(0))
+			failures := InterceptGomegaFailures(func() {
+				Expect(session).Should(HaveNoProcessGroupMembers())
+			})
+			Expect(failures).Should(HaveLen(1))
+			Expect(failures[0]).Should(HavePrefix("Expected process group %d to have no remaining processes.  It does.", session.Command.Process.Pid))
+			Expect(failures[0]).Should(MatchRegexp(`Remaining processes:\n    \d+ sleep 100`))
+		})
+
+		It("has a negated failure message", func() {
+			start(InProcessGroup(exec.Command("true")))
+			Eventually(session).Should(Exit(0))
+			failures := InterceptGomegaFailures(func() {
+				Eventually(session).ShouldNot(HaveNoProcessGroupMembers())
+			})
+			Expect(failures).Should(HaveLen(1))
+			Expect(failures[0]).Should(ContainSubstring("to have remaining processes.  It does not."))
+		})
+
+		It("errors for sessions that were not started in their own process group", func() {
+			start(exec.Command("true"))
+			Eventually(session).Should(Exit(0))
+			_, err := HaveNoProcessGroupMembers().Match(session)
+			Expect(err).Should(MatchError(ContainSubstring("gexec.InProcessGroup")))
+		})
+
+		It("errors for anything but a session", func() {
+			_, err := HaveNoProcessGroupMembers().Match("foo")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,108 @@
+//go:build !windows
+// +build !windows
+
+package gexec
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"weak"
+)
+
+/*
+InProcessGroup configures command to start in its own process group and returns it:
+
+	session, err := gexec.Start(gexec.InProcessGroup(exec.Command("./run-with-sidecars.sh")), GinkgoWriter, GinkgoWriter)
+
+Sessions started from such commands send signals (Kill, Terminate, Interrupt, Signal and their package-level equivalents) to every
+process in the group, so helpers spawned by the command do not outlive it.  KillAndWait and TerminateAndWait also wait for the whole
+group to exit, and the HaveNoProcessGroupMembers matcher can assert that nothing in the group is left running.
+
+Once the session has seen the group empty it stops signaling it: the group's ID may then be reused by an unrelated process group.
+
+Signaling the group is opt-in: commands that set SysProcAttr.Setsid or SysProcAttr.Setpgid themselves (and sessions started with
+StartWithPTY) are signaled individually unless they are passed through InProcessGroup too.
+*/
+func InProcessGroup(command *exec.Cmd) *exec.Cmd {
+	if command.SysProcAttr == nil {
+		command.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	command.SysProcAttr.Setpgid = true
+	command.SysProcAttr.Pgid = 0
+	key := weak.Make(command)
+	if _, loaded := processGroupCommands.LoadOrStore(key, true); !loaded {
+		//commands that are never started are dropped once they are garbage collected
+		runtime.AddCleanup(command, processGroupCommands.Delete, any(key))
+	}
+	return command
+}
+
+// processGroupCommands holds the commands passed to InProcessGroup that have not been started yet.  It is keyed by weak pointers so it
+// doesn't keep commands alive.
+var processGroupCommands = &sync.Map{}
+
+// forgetProcessGroup drops the command's InProcessGroup opt-in, for commands that fail to start
+func forgetProcessGroup(command *exec.Cmd) {
+	processGroupCommands.Delete(weak.Make(command))
+}
+
+// inProcessGroup returns true if command was passed to InProcessGroup and (still) starts in its own process group.  It forgets the command
+// either way.
+func inProcessGroup(command *exec.Cmd) bool {
+	if _, ok := processGroupCommands.LoadAndDelete(weak.Make(command)); !ok {
+		return false
+	}
+	attr := command.SysProcAttr
+	return attr != nil && (attr.Setsid || (attr.Setpgid && attr.Pgid == 0))
+}
+
+// processGroupExists returns true if any process (zombies included) is in the process group
+func processGroupExists(pgid int) bool {
+	return !errors.Is(syscall.Kill(-pgid, 0), syscall.ESRCH)
+}
+
+func signalProcessGroup(pgid int, signal os.Signal) {
+	if sig, ok := signal.(syscall.Signal); ok {
+		syscall.Kill(-pgid, sig)
+	}
+}
+
+// processGroupMembers describes the live processes in the group, one "pid command line" per process.
+// Where /proc is unavailable it cannot tell which processes are in the group, and returns a placeholder if the group has any members at all.
+func processGroupMembers(pgid int) []string {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		if !processGroupExists(pgid) {
+			return nil
+		}
+		return []string{"(unable to list processes)"}
+	}
+	stats, _ := filepath.Glob("/proc/[0-9]*/stat")
+	members := []string{}
+	for _, stat := range stats {
+		content, err := os.ReadFile(stat)
+		if err != nil {
+			continue
+		}
+		//the command name is parenthesized and may contain spaces, the state and process group are the first and third fields after it
+		fields := strings.Fields(string(content[bytes.LastIndexByte(content, ')')+1:]))
+		if len(fields) < 3 || fields[2] != strconv.Itoa(pgid) {
+			continue
+		}
+		if fields[0] == "Z" || fields[0] == "X" {
+			//zombies have exited, they're just waiting for a parent to reap them
+			continue
+		}
+		dir := filepath.Dir(stat)
+		cmdline, _ := os.ReadFile(filepath.Join(dir, "cmdline"))
+		members = append(members, filepath.Base(dir)+" "+strings.TrimSpace(string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '}))))
+	}
+	return members
+}
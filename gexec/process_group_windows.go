@@ -0,0 +1,30 @@
+//go:build windows
+// +build windows
+
+package gexec
+
+import (
+	"os"
+	"os/exec"
+)
+
+// InProcessGroup is a no-op on Windows: process groups are not supported, so Sessions signal only the command itself.
+func InProcessGroup(command *exec.Cmd) *exec.Cmd {
+	return command
+}
+
+func forgetProcessGroup(command *exec.Cmd) {}
+
+func inProcessGroup(command *exec.Cmd) bool {
+	return false
+}
+
+func processGroupExists(pgid int) bool {
+	return false
+}
+
+func signalProcessGroup(pgid int, signal os.Signal) {}
+
+func processGroupMembers(pgid int) []string {
+	return nil
+}
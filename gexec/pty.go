@@ -0,0 +1,41 @@
+package gexec
+
+import "io"
+
+// Control characters that can be sent to a session started with StartWithPTY:
+//
+//	session.Send(gexec.CtrlC)
+const (
+	// CtrlC interrupts the command (the terminal sends it a SIGINT)
+	CtrlC = "\x03"
+	// CtrlD signals EOF when sent at the start of a line
+	CtrlD = "\x04"
+	// CtrlZ suspends the command (the terminal sends it a SIGTSTP)
+	CtrlZ = "\x1a"
+	// CtrlBackslash quits the command (the terminal sends it a SIGQUIT)
+	CtrlBackslash = "\x1c"
+)
+
+// PTYOptions configures the pseudo-terminal allocated by StartWithPTY
+type PTYOptions struct {
+	// Rows and Cols set the terminal's initial window size.  They default to 24 and 80.
+	Rows uint16
+	Cols uint16
+
+	// When OutWriter is non-nil, the session pipes the terminal's output both into session.Out and to OutWriter
+	OutWriter io.Writer
+}
+
+func (o PTYOptions) rows() uint16 {
+	if o.Rows == 0 {
+		return 24
+	}
+	return o.Rows
+}
+
+func (o PTYOptions) cols() uint16 {
+	if o.Cols == 0 {
+		return 80
+	}
+	return o.Cols
+}
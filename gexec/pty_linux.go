@@ -0,0 +1,174 @@
+//go:build linux
+// +build linux
+
+package gexec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync/atomic"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+/*
+StartWithPTY starts the passed-in *exec.Cmd command attached to a freshly allocated pseudo-terminal.  It wraps the command in a *gexec.Session.
+
+Use StartWithPTY to test commands that behave differently when attached to a terminal (colors, progress bars, password prompts and the like).
+The pty becomes the command's stdin, stdout, stderr and controlling terminal, so the command's output arrives on a single stream: session.Out
+receives everything written to the terminal (session.Err stays empty).  Since the terminal echoes its input, session.Out also includes
+whatever you send to the command.
+
+session.In writes to the terminal, so SendLine and ExpectThenSend work just as they do for StartInteractive.  The terminal interprets control
+characters, so sending CtrlC interrupts the command and sending CtrlD at the start of a line signals EOF:
+
+	session, err := StartWithPTY(command, PTYOptions{OutWriter: GinkgoWriter})
+	Expect(err).NotTo(HaveOccurred())
+
+	session.ExpectThenSend("password:", "serenity")
+	session.Send(CtrlC)
+	Eventually(session).Should(Exit(130))
+
+Closing session.In (including via Terminate or Kill) stops further writes but leaves the terminal open until the command exits.
+
+StartWithPTY is only available on Linux.
+*/
+func StartWithPTY(command *exec.Cmd, opts PTYOptions) (*Session, error) {
+	if command.Stdin != nil || command.Stdout != nil || command.Stderr != nil {
+		forgetProcessGroup(command)
+		return nil, errors.New("gexec: StartWithPTY requires the command's Stdin, Stdout, and Stderr to be unset")
+	}
+
+	terminal, tty, err := openPTY()
+	if err != nil {
+		forgetProcessGroup(command)
+		return nil, err
+	}
+	if err := setWindowSize(terminal, opts.rows(), opts.cols()); err != nil {
+		terminal.Close()
+		tty.Close()
+		forgetProcessGroup(command)
+		return nil, err
+	}
+
+	session, exited := newSession(command)
+	session.In = &ptyInput{terminal: terminal}
+	session.terminal = terminal
+	session.terminalDrained = make(chan struct{})
+
+	command.Stdin, command.Stdout, command.Stderr = tty, tty, tty
+	if command.SysProcAttr == nil {
+		command.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	command.SysProcAttr.Setsid = true
+	command.SysProcAttr.Setctty = true
+	command.SysProcAttr.Ctty = 0
+	//the new session already gives the command its own process group (so InProcessGroup works as is) and session leaders can't change their process group
+	command.SysProcAttr.Setpgid = false
+
+	var out io.Writer = session.Out
+	if opts.OutWriter != nil {
+		out = io.MultiWriter(out, opts.OutWriter)
+	}
+
+	err = session.run(exited)
+	//the command has its own copy of the tty; closing ours ensures reads from the terminal end once the command (and its descendants) let go of it
+	tty.Close()
+	if err != nil {
+		terminal.Close()
+		session.Out.Close()
+		session.Err.Close()
+		return session, err
+	}
+
+	go func() {
+		//reading from the terminal fails with EIO once the command's side has been closed - there's no more output to copy either way
+		io.Copy(out, terminal)
+		close(session.terminalDrained)
+	}()
+
+	return session, nil
+}
+
+/*
+SetWindowSize changes the size of the session's terminal.  The command receives a SIGWINCH signal.
+
+SetWindowSize returns an error if the session was not started with StartWithPTY.
+*/
+func (s *Session) SetWindowSize(rows uint16, cols uint16) error {
+	if s.terminal == nil {
+		return errors.New("gexec: SetWindowSize requires a session started with StartWithPTY")
+	}
+	return setWindowSize(s.terminal, rows, cols)
+}
+
+// ptyInput writes to the terminal.  Closing it stops further writes without closing the terminal, which would hang up on the command.
+type ptyInput struct {
+	terminal *os.File
+	closed   atomic.Bool
+}
+
+func (p *ptyInput) Write(data []byte) (int, error) {
+	if p.closed.Load() {
+		return 0, os.ErrClosed
+	}
+	return p.terminal.Write(data)
+}
+
+func (p *ptyInput) Close() error {
+	p.closed.Store(true)
+	return nil
+}
+
+func openPTY() (*os.File, *os.File, error) {
+	terminal, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gexec: failed to allocate a pty: %w", err)
+	}
+
+	if err := control(terminal, func(fd int) error { return unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0) }); err != nil {
+		terminal.Close()
+		return nil, nil, fmt.Errorf("gexec: failed to unlock the pty: %w", err)
+	}
+	var n uint32
+	err = control(terminal, func(fd int) (err error) {
+		n, err = unix.IoctlGetUint32(fd, unix.TIOCGPTN)
+		return err
+	})
+	if err != nil {
+		terminal.Close()
+		return nil, nil, fmt.Errorf("gexec: failed to look up the pty: %w", err)
+	}
+
+	tty, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		terminal.Close()
+		return nil, nil, fmt.Errorf("gexec: failed to open the pty: %w", err)
+	}
+	return terminal, tty, nil
+}
+
+func setWindowSize(terminal *os.File, rows uint16, cols uint16) error {
+	size := &unix.Winsize{Row: rows, Col: cols}
+	if err := control(terminal, func(fd int) error { return unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, size) }); err != nil {
+		return fmt.Errorf("gexec: failed to set the pty's window size: %w", err)
+	}
+	return nil
+}
+
+// control runs f with the file's descriptor, making sure the file isn't closed in the meantime
+func control(file *os.File, f func(fd int) error) error {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var ferr error
+	if err := conn.Control(func(fd uintptr) { ferr = f(int(fd)) }); err != nil {
+		return err
+	}
+	return ferr
+}
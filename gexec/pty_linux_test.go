@@ -0,0 +1,125 @@
+//go:build linux
+// +build linux
+
+package gexec_test
+
+import (
+	"os/exec"
+
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StartWithPTY", func() {
+	var session *Session
+
+	startWithPTY := func(script string, opts PTYOptions) {
+		var err error
+		session, err = StartWithPTY(exec.Command("sh", "-c", script), opts)
+		Expect(err).ShouldNot(HaveOccurred())
+		DeferCleanup(func() { session.Kill().Wait() })
+	}
+
+	It("attaches the command to a terminal", func() {
+		startWithPTY(`test -t 0 && test -t 1 && test -t 2 && echo "all terminals"; echo "err" >&2`, PTYOptions{})
+		Eventually(session).Should(Exit(0))
+		Expect(session).Should(Say("all terminals"))
+		Expect(session).Should(Say("err"))
+		Expect(session.Err.Contents()).Should(BeEmpty())
+	})
+
+	It("also pipes the terminal's output to the OutWriter", func() {
+		buffer := NewBuffer()
+		startWithPTY(`echo hello`, PTYOptions{OutWriter: buffer})
+		Eventually(session).Should(Exit(0))
+		Expect(buffer).Should(Say("hello"))
+	})
+
+	It("sets the window size, defaulting to 24x80", func() {
+		startWithPTY(`stty size`, PTYOptions{})
+		Eventually(session).Should(Exit(0))
+		Expect(session).Should(Say("24 80"))
+
+		startWithPTY(`stty size`, PTYOptions{Rows: 50, Cols: 132})
+		Eventually(session).Should(Exit(0))
+		Expect(session).Should(Say("50 132"))
+	})
+
+	It("changes the window size", func() {
+		startWithPTY(`read line; stty size`, PTYOptions{})
+		Expect(session.SetWindowSize(40, 100)).Should(Succeed())
+		session.SendLine("go")
+		Eventually(session).Should(Say("40 100"))
+		Eventually(session).Should(Exit(0))
+	})
+
+	It("supports sending input and control characters", func() {
+		startWithPTY(`printf "name? "; read name; echo "hello $name"; cat`, PTYOptions{})
+		session.ExpectThenSend(`name\?`, "Mal")
+		Eventually(session).Should(Say("hello Mal"))
+		session.Send(CtrlD)
+		Eventually(session).Should(Exit(0))
+
+		startWithPTY(`echo ready; cat`, PTYOptions{})
+		Eventually(session).Should(Say("ready"))
+		session.Send(CtrlC)
+		Eventually(session).Should(Exit(130))
+	})
+
+	It("stops accepting input when In is closed, without hanging up the terminal", func() {
+		startWithPTY(`echo ready; sleep 0.2; echo still here`, PTYOptions{})
+		Eventually(session).Should(Say("ready"))
+		session.CloseIn()
+		_, err := session.In.Write([]byte("hello"))
+		Expect(err).Should(HaveOccurred())
+		Eventually(session).Should(Say("still here"))
+		Eventually(session).Should(Exit(0))
+	})
+
+	It("works with Terminate and Kill", func() {
+		startWithPTY(`echo ready; sleep 10`, PTYOptions{})
+		Eventually(session).Should(Say("ready"))
+		session.Terminate()
+		Eventually(session).Should(Exit(128 + 15))
+		Expect(session.Out.Closed()).Should(BeTrue())
+
+		startWithPTY(`echo ready; sleep 10`, PTYOptions{})
+		Eventually(session).Should(Say("ready"))
+		session.Kill()
+		Eventually(session).Should(Exit(128 + 9))
+	})
+
+	It("signals the whole process group when asked to", func() {
+		var err error
+		session, err = StartWithPTY(InProcessGroup(exec.Command("sh", "-c", "trap '' HUP; sleep 100 & echo started; wait")), PTYOptions{})
+		Expect(err).ShouldNot(HaveOccurred())
+		DeferCleanup(func() { session.Kill().Wait() })
+		Eventually(session).Should(Say("started"))
+
+		session.Kill()
+		Eventually(session).Should(Exit(128 + 9))
+		Eventually(session).Should(HaveNoProcessGroupMembers())
+	})
+
+	It("refuses commands whose stdio is already set", func() {
+		command := exec.Command("cat")
+		command.Stdout = NewBuffer()
+		_, err := StartWithPTY(command, PTYOptions{})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("returns an error when the command fails to start", func() {
+		_, err := StartWithPTY(exec.Command("agklsjdfas"), PTYOptions{})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("refuses to set the window size of sessions without a terminal", func() {
+		session, err := Start(exec.Command("true"), nil, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(session.SetWindowSize(10, 10)).ShouldNot(Succeed())
+		Eventually(session).Should(Exit(0))
+	})
+})
@@ -0,0 +1,20 @@
+//go:build !linux
+// +build !linux
+
+package gexec
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// StartWithPTY is only available on Linux.  On other platforms it always returns an error.
+func StartWithPTY(command *exec.Cmd, opts PTYOptions) (*Session, error) {
+	forgetProcessGroup(command)
+	return nil, errors.New("gexec: StartWithPTY is only supported on Linux")
+}
+
+// SetWindowSize is only available on Linux.  On other platforms it always returns an error.
+func (s *Session) SetWindowSize(rows uint16, cols uint16) error {
+	return errors.New("gexec: SetWindowSize is only supported on Linux")
+}
@@ -0,0 +1,95 @@
+package gexec
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gmeasure"
+)
+
+// ResourceUsage describes the resources consumed by a session's command.  It is available once the command has exited.
+type ResourceUsage struct {
+	//WallTime is the time between starting the command and it exiting
+	WallTime time.Duration
+
+	//UserTime and SystemTime are the CPU time the command spent in user and kernel mode
+	UserTime   time.Duration
+	SystemTime time.Duration
+
+	//MaxRSS is the command's peak resident set size, in bytes.  It is zero where the platform does not report it.
+	MaxRSS uint64
+
+	//VoluntaryContextSwitches and InvoluntaryContextSwitches count the command's context switches.  They are zero where the platform does not report them.
+	VoluntaryContextSwitches   int64
+	InvoluntaryContextSwitches int64
+}
+
+// CPUTime returns the total CPU time used by the command: UserTime + SystemTime
+func (r ResourceUsage) CPUTime() time.Duration {
+	return r.UserTime + r.SystemTime
+}
+
+func newResourceUsage(state *os.ProcessState, wallTime time.Duration) *ResourceUsage {
+	usage := &ResourceUsage{
+		WallTime:   wallTime,
+		UserTime:   state.UserTime(),
+		SystemTime: state.SystemTime(),
+	}
+	fillPlatformResourceUsage(usage, state)
+	return usage
+}
+
+/*
+ResourceUsage returns the resources consumed by the session's command.  It returns nil if the command hasn't exited yet.
+
+	session.Wait()
+	fmt.Println(session.ResourceUsage().MaxRSS)
+
+To assert against resource usage it is more convenient to use the HaveUsedLessMemoryThan and HaveRunFor matchers.
+*/
+func (s *Session) ResourceUsage() *ResourceUsage {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.resourceUsage
+}
+
+/*
+RecordResourceUsage records the resources consumed by the session's command in experiment.  This makes it easy to benchmark a command over many runs:
+
+	experiment := gmeasure.NewExperiment("CLI performance")
+	AddReportEntry(experiment.Name, experiment)
+
+	experiment.Sample(func(idx int) {
+		session, err := gexec.Start(exec.Command(pathToCLI, "sync"), nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		gexec.RecordResourceUsage(experiment, "sync", session.Wait())
+	}, gmeasure.SamplingConfig{N: 20})
+
+RecordResourceUsage records the following measurements, each named after name:
+
+	"<name>: wall time", "<name>: user time" and "<name>: system time" - Duration Measurements
+	"<name>: max RSS" - a Value Measurement, in MB
+	"<name>: voluntary context switches" and "<name>: involuntary context switches" - Value Measurements
+
+Measurements the platform does not report are not recorded.  args are passed along to each measurement and may include
+gmeasure.Annotation and gmeasure.Style decorations.
+
+The command must have exited.  If it hasn't, RecordResourceUsage triggers a test failure.
+*/
+func RecordResourceUsage(experiment *gmeasure.Experiment, name string, session *Session, args ...any) {
+	usage := session.ResourceUsage()
+	if !ExpectWithOffset(1, usage).NotTo(BeNil(), "RecordResourceUsage requires a session that has exited") {
+		return
+	}
+	experiment.RecordDuration(name+": wall time", usage.WallTime, args...)
+	experiment.RecordDuration(name+": user time", usage.UserTime, args...)
+	experiment.RecordDuration(name+": system time", usage.SystemTime, args...)
+	if usage.MaxRSS > 0 {
+		experiment.RecordValue(name+": max RSS", float64(usage.MaxRSS)/1e6, append([]any{gmeasure.Units("MB"), gmeasure.Precision(1)}, args...)...)
+	}
+	if usage.VoluntaryContextSwitches > 0 || usage.InvoluntaryContextSwitches > 0 {
+		experiment.RecordValue(name+": voluntary context switches", float64(usage.VoluntaryContextSwitches), append([]any{gmeasure.Precision(0)}, args...)...)
+		experiment.RecordValue(name+": involuntary context switches", float64(usage.InvoluntaryContextSwitches), append([]any{gmeasure.Precision(0)}, args...)...)
+	}
+}
@@ -0,0 +1,101 @@
+package gexec
+
+import (
+	"fmt"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// ResourceReporter is implemented by *Session.  The HaveUsedLessMemoryThan and HaveRunFor matchers operate on ResourceReporters.
+type ResourceReporter interface {
+	ResourceUsage() *ResourceUsage
+}
+
+func resourceUsageFor(matcherName string, actual any) (*ResourceUsage, error) {
+	reporter, ok := actual.(ResourceReporter)
+	if !ok {
+		return nil, fmt.Errorf("%s must be passed a gexec.ResourceReporter (Missing method ResourceUsage() *ResourceUsage) Got:\n%s", matcherName, format.Object(actual, 1))
+	}
+	usage := reporter.ResourceUsage()
+	if usage == nil {
+		return nil, fmt.Errorf("%s requires a session that has exited.  The command is still running.", matcherName)
+	}
+	return usage, nil
+}
+
+/*
+HaveUsedLessMemoryThan succeeds if the session's command's peak resident set size (see ResourceUsage.MaxRSS) was less than the passed-in number of bytes:
+
+	Eventually(session).Should(HaveUsedLessMemoryThan(64 * 1024 * 1024))
+
+HaveUsedLessMemoryThan errors until the command has exited, so Eventually waits for the command to exit.  It also errors on platforms that do
+not report peak memory usage.
+*/
+func HaveUsedLessMemoryThan(bytes uint64) *usedLessMemoryThanMatcher {
+	return &usedLessMemoryThanMatcher{bytes: bytes}
+}
+
+type usedLessMemoryThanMatcher struct {
+	bytes uint64
+	usage *ResourceUsage
+}
+
+func (m *usedLessMemoryThanMatcher) Match(actual any) (success bool, err error) {
+	m.usage, err = resourceUsageFor("HaveUsedLessMemoryThan", actual)
+	if err != nil {
+		return false, err
+	}
+	if m.usage.MaxRSS == 0 {
+		return false, fmt.Errorf("HaveUsedLessMemoryThan requires peak memory usage, which this platform does not report")
+	}
+	return m.usage.MaxRSS < m.bytes, nil
+}
+
+func (m *usedLessMemoryThanMatcher) FailureMessage(actual any) (message string) {
+	return fmt.Sprintf("Expected process to have used less than %d bytes of memory.  Its peak resident set size was %d bytes.", m.bytes, m.usage.MaxRSS)
+}
+
+func (m *usedLessMemoryThanMatcher) NegatedFailureMessage(actual any) (message string) {
+	return fmt.Sprintf("Expected process to have used at least %d bytes of memory.  Its peak resident set size was %d bytes.", m.bytes, m.usage.MaxRSS)
+}
+
+func (m *usedLessMemoryThanMatcher) MatchMayChangeInTheFuture(actual any) bool {
+	return m.usage == nil
+}
+
+/*
+HaveRunFor succeeds if the session's command's wall-clock duration (see ResourceUsage.WallTime) satisfies the passed-in matcher:
+
+	Eventually(session).Should(HaveRunFor(BeNumerically("<", 2*time.Second)))
+
+HaveRunFor errors until the command has exited, so Eventually waits for the command to exit.
+*/
+func HaveRunFor(matcher types.GomegaMatcher) *runForMatcher {
+	return &runForMatcher{matcher: matcher}
+}
+
+type runForMatcher struct {
+	matcher types.GomegaMatcher
+	usage   *ResourceUsage
+}
+
+func (m *runForMatcher) Match(actual any) (success bool, err error) {
+	m.usage, err = resourceUsageFor("HaveRunFor", actual)
+	if err != nil {
+		return false, err
+	}
+	return m.matcher.Match(m.usage.WallTime)
+}
+
+func (m *runForMatcher) FailureMessage(actual any) (message string) {
+	return fmt.Sprintf("Expected process's run time to satisfy the matcher:\n%s", m.matcher.FailureMessage(m.usage.WallTime))
+}
+
+func (m *runForMatcher) NegatedFailureMessage(actual any) (message string) {
+	return fmt.Sprintf("Expected process's run time not to satisfy the matcher:\n%s", m.matcher.NegatedFailureMessage(m.usage.WallTime))
+}
+
+func (m *runForMatcher) MatchMayChangeInTheFuture(actual any) bool {
+	return m.usage == nil
+}
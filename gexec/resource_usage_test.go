@@ -0,0 +1,146 @@
+//go:build !windows
+// +build !windows
+
+package gexec_test
+
+import (
+	"os/exec"
+	"time"
+
+	. "github.com/onsi/gomega/gexec"
+	"github.com/onsi/gomega/gmeasure"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resource usage", func() {
+	var session *Session
+
+	start := func(script string) {
+		var err error
+		session, err = Start(exec.Command("sh", "-c", script), nil, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		DeferCleanup(func() { session.Kill().Wait() })
+	}
+
+	Describe("ResourceUsage", func() {
+		It("is nil until the command exits", func() {
+			start("exec sleep 100")
+			Expect(session.ResourceUsage()).Should(BeNil())
+		})
+
+		It("reports the resources consumed by the command", func() {
+			start("sleep 0.1")
+			usage := session.Wait().ResourceUsage()
+			Expect(usage).ShouldNot(BeNil())
+			Expect(usage.WallTime).Should(BeNumerically(">=", 100*time.Millisecond))
+			Expect(usage.WallTime).Should(BeNumerically("<", 5*time.Second))
+			Expect(usage.CPUTime()).Should(Equal(usage.UserTime + usage.SystemTime))
+			Expect(usage.MaxRSS).Should(BeNumerically(">", 1024))
+			Expect(usage.VoluntaryContextSwitches + usage.InvoluntaryContextSwitches).Should(BeNumerically(">", 0))
+		})
+	})
+
+	Describe("HaveUsedLessMemoryThan", func() {
+		It("compares the command's peak resident set size", func() {
+			start("true")
+			Eventually(session).Should(HaveUsedLessMemoryThan(1 << 30))
+			Expect(session).ShouldNot(HaveUsedLessMemoryThan(1))
+		})
+
+		It("has failure messages", func() {
+			start("true")
+			session.Wait()
+			failures := InterceptGomegaFailures(func() {
+				Expect(session).Should(HaveUsedLessMemoryThan(1))
+				Expect(session).ShouldNot(HaveUsedLessMemoryThan(1 << 30))
+			})
+			Expect(failures).Should(HaveLen(2))
+			Expect(failures[0]).Should(MatchRegexp(`^Expected process to have used less than 1 bytes of memory\.  Its peak resident set size was \d+ bytes\.$`))
+			Expect(failures[1]).Should(MatchRegexp(`^Expected process to have used at least 1073741824 bytes of memory\.  Its peak resident set size was \d+ bytes\.$`))
+		})
+
+		It("errors when the command has not exited", func() {
+			start("exec sleep 100")
+			success, err := HaveUsedLessMemoryThan(1 << 30).Match(session)
+			Expect(success).Should(BeFalse())
+			Expect(err).Should(MatchError("HaveUsedLessMemoryThan requires a session that has exited.  The command is still running."))
+			failures := InterceptGomegaFailures(func() {
+				Expect(session).ShouldNot(HaveUsedLessMemoryThan(1 << 30))
+			})
+			Expect(failures).Should(HaveLen(1))
+		})
+
+		It("errors when not passed a ResourceReporter", func() {
+			_, err := HaveUsedLessMemoryThan(1).Match("foo")
+			Expect(err).Should(MatchError(ContainSubstring("HaveUsedLessMemoryThan must be passed a gexec.ResourceReporter")))
+		})
+	})
+
+	Describe("HaveRunFor", func() {
+		It("matches the command's wall-clock duration", func() {
+			start("sleep 0.1")
+			Eventually(session).Should(HaveRunFor(BeNumerically(">=", 100*time.Millisecond)))
+			Expect(session).ShouldNot(HaveRunFor(BeNumerically(">", time.Minute)))
+		})
+
+		It("has failure messages", func() {
+			start("true")
+			session.Wait()
+			failures := InterceptGomegaFailures(func() {
+				Expect(session).Should(HaveRunFor(BeNumerically(">", time.Minute)))
+				Expect(session).ShouldNot(HaveRunFor(BeNumerically("<", time.Minute)))
+			})
+			Expect(failures).Should(HaveLen(2))
+			Expect(failures[0]).Should(HavePrefix("Expected process's run time to satisfy the matcher:\nExpected\n    <time.Duration>: "))
+			Expect(failures[1]).Should(HavePrefix("Expected process's run time not to satisfy the matcher:\nExpected\n    <time.Duration>: "))
+		})
+
+		It("errors when the command has not exited", func() {
+			start("exec sleep 100")
+			success, err := HaveRunFor(BeNumerically(">", 0)).Match(session)
+			Expect(success).Should(BeFalse())
+			Expect(err).Should(MatchError("HaveRunFor requires a session that has exited.  The command is still running."))
+			failures := InterceptGomegaFailures(func() {
+				Expect(session).ShouldNot(HaveRunFor(BeNumerically(">", 0)))
+			})
+			Expect(failures).Should(HaveLen(1))
+		})
+
+		It("errors when not passed a ResourceReporter", func() {
+			_, err := HaveRunFor(BeNumerically(">", 0)).Match("foo")
+			Expect(err).Should(MatchError(ContainSubstring("HaveRunFor must be passed a gexec.ResourceReporter")))
+		})
+	})
+
+	Describe("RecordResourceUsage", func() {
+		It("records the resources consumed by the command", func() {
+			experiment := gmeasure.NewExperiment("resource usage")
+			for i := 0; i < 3; i++ {
+				start("true")
+				RecordResourceUsage(experiment, "true", session.Wait(), gmeasure.Annotation("run"))
+			}
+
+			for _, name := range []string{"true: wall time", "true: user time", "true: system time"} {
+				Expect(experiment.Get(name).Durations).Should(HaveLen(3), name)
+				Expect(experiment.Get(name).Annotations).Should(ConsistOf("run", "run", "run"))
+			}
+			maxRSS := experiment.Get("true: max RSS")
+			Expect(maxRSS.Values).Should(HaveLen(3))
+			Expect(maxRSS.Units).Should(Equal("MB"))
+			Expect(experiment.Get("true: voluntary context switches").Values).Should(HaveLen(3))
+			Expect(experiment.Get("true: involuntary context switches").Values).Should(HaveLen(3))
+		})
+
+		It("fails when the command has not exited", func() {
+			start("exec sleep 100")
+			experiment := gmeasure.NewExperiment("resource usage")
+			failures := InterceptGomegaFailures(func() {
+				RecordResourceUsage(experiment, "sleep", session)
+			})
+			Expect(failures).Should(ConsistOf(ContainSubstring("RecordResourceUsage requires a session that has exited")))
+			Expect(experiment.Measurements).Should(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,24 @@
+//go:build !windows
+// +build !windows
+
+package gexec
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+func fillPlatformResourceUsage(usage *ResourceUsage, state *os.ProcessState) {
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || rusage == nil {
+		return
+	}
+	//ru_maxrss is reported in bytes on darwin and in kilobytes elsewhere
+	usage.MaxRSS = uint64(rusage.Maxrss)
+	if runtime.GOOS != "darwin" && runtime.GOOS != "ios" {
+		usage.MaxRSS *= 1024
+	}
+	usage.VoluntaryContextSwitches = int64(rusage.Nvcsw)
+	usage.InvoluntaryContextSwitches = int64(rusage.Nivcsw)
+}
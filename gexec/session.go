@@ -12,6 +12,7 @@ import (
 	"os/exec"
 	"sync"
 	"syscall"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
@@ -29,11 +30,26 @@ type Session struct {
 	//A *gbytes.Buffer connected to the command's stderr
 	Err *gbytes.Buffer
 
+	//An io.WriteCloser connected to the command's stdin.  In is only set for sessions started with StartInteractive
+	In io.WriteCloser
+
 	//A channel that will close when the command exits
 	Exited <-chan struct{}
 
 	lock     *sync.Mutex
 	exitCode int
+
+	//true when the command was started with InProcessGroup, in which case signals go to the whole group
+	processGroup bool
+	//true once the process group has been seen empty - from then on its ID may belong to an unrelated group
+	processGroupGone bool
+
+	startTime     time.Time
+	resourceUsage *ResourceUsage
+
+	//for sessions started with StartWithPTY: the pty's controlling side and a channel that closes once its output has been copied to Out
+	terminal        *os.File
+	terminalDrained chan struct{}
 }
 
 /*
@@ -56,6 +72,8 @@ This is useful for capturing the process's output or logging it to screen.  In p
 
 This will log output when running tests in verbose mode, but - otherwise - will only log output when a test fails.
 
+To drive a command's stdin, use StartInteractive instead.
+
 The session wrapper is responsible for waiting on the *exec.Cmd command.  You *should not* call command.Wait() yourself.
 Instead, to assert that the command has exited you can use the gexec.Exit matcher:
 
@@ -65,16 +83,28 @@ When the session exits it closes the stdout and stderr gbytes buffers.  This wil
 Eventuallys waiting for the buffers to Say something.
 */
 func Start(command *exec.Cmd, outWriter io.Writer, errWriter io.Writer) (*Session, error) {
-	exited := make(chan struct{})
+	return start(command, outWriter, errWriter, false)
+}
 
-	session := &Session{
-		Command:  command,
-		Out:      gbytes.NewBuffer(),
-		Err:      gbytes.NewBuffer(),
-		Exited:   exited,
-		lock:     &sync.Mutex{},
-		exitCode: -1,
-	}
+/*
+StartInteractive behaves like Start but also connects the command's stdin to session.In.  This allows you to drive
+interactive commands like prompts and REPLs:
+
+	session, err := StartInteractive(command, GinkgoWriter, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
+
+	session.ExpectThenSend("name\\?", "Mal")
+	Eventually(session).Should(gbytes.Say("hello Mal"))
+
+The command's stdin stays open until you call session.CloseIn(), session.Terminate() or session.Kill(), or until the command exits.
+StartInteractive returns an error if command.Stdin has already been set.
+*/
+func StartInteractive(command *exec.Cmd, outWriter io.Writer, errWriter io.Writer) (*Session, error) {
+	return start(command, outWriter, errWriter, true)
+}
+
+func start(command *exec.Cmd, outWriter io.Writer, errWriter io.Writer, interactive bool) (*Session, error) {
+	session, exited := newSession(command)
 
 	var commandOut, commandErr io.Writer
 
@@ -91,17 +121,48 @@ func Start(command *exec.Cmd, outWriter io.Writer, errWriter io.Writer) (*Sessio
 	command.Stdout = commandOut
 	command.Stderr = commandErr
 
-	err := command.Start()
-	if err == nil {
-		go session.monitorForExit(exited)
-		trackedSessionsMutex.Lock()
-		defer trackedSessionsMutex.Unlock()
-		trackedSessions = append(trackedSessions, session)
+	if interactive {
+		in, err := command.StdinPipe()
+		if err != nil {
+			forgetProcessGroup(command)
+			return session, err
+		}
+		session.In = in
 	}
 
+	err := session.run(exited)
 	return session, err
 }
 
+func newSession(command *exec.Cmd) (*Session, chan struct{}) {
+	exited := make(chan struct{})
+
+	return &Session{
+		Command:  command,
+		Out:      gbytes.NewBuffer(),
+		Err:      gbytes.NewBuffer(),
+		Exited:   exited,
+		lock:     &sync.Mutex{},
+		exitCode: -1,
+	}, exited
+}
+
+// run starts the session's command and, if it started successfully, monitors it for exit and tracks it
+func (s *Session) run(exited chan struct{}) error {
+	s.startTime = time.Now()
+	err := s.Command.Start()
+	if err != nil {
+		forgetProcessGroup(s.Command)
+		return err
+	}
+	s.processGroup = inProcessGroup(s.Command)
+	go s.monitorForExit(exited)
+	trackedSessionsMutex.Lock()
+	defer trackedSessionsMutex.Unlock()
+	trackedSessions = append(trackedSessions, s)
+	return nil
+}
+
 /*
 Buffer implements the gbytes.BufferProvider interface and returns s.Out
 This allows you to make gbytes.Say matcher assertions against stdout without having to reference .Out:
@@ -146,14 +207,67 @@ func (s *Session) Wait(timeout ...any) *Session {
 }
 
 /*
-Kill sends the running command a SIGKILL signal.  It does not wait for the process to exit.
+Send writes data to the command's stdin.  If the write fails, Send triggers a test failure.
+
+The session is returned to enable chaining.
+*/
+func (s *Session) Send(data string) *Session {
+	s.send(1, data)
+	return s
+}
+
+/*
+SendLine writes line, followed by a newline, to the command's stdin.  If the write fails, SendLine triggers a test failure.
+
+The session is returned to enable chaining.
+*/
+func (s *Session) SendLine(line string) *Session {
+	s.send(1, line+"\n")
+	return s
+}
+
+/*
+ExpectThenSend waits for the command's stdout to say pattern and then sends reply, followed by a newline, to the command's stdin.
+It can be passed an optional timeout and polling interval, just like Eventually.  If pattern does not appear in time, ExpectThenSend triggers a test failure.
+
+ExpectThenSend uses gbytes.Say under the hood, so it advances stdout's read cursor past the match, just as:
+
+	Eventually(session).Should(gbytes.Say(pattern))
+
+would.  This makes it easy to script a conversation with an interactive command:
+
+	session.ExpectThenSend("username:", "mal").ExpectThenSend("password:", "serenity")
+	Eventually(session).Should(gbytes.Say("welcome, mal"))
+
+The session is returned to enable chaining.
+*/
+func (s *Session) ExpectThenSend(pattern string, reply string, timeout ...any) *Session {
+	EventuallyWithOffset(1, s.Out, timeout...).Should(gbytes.Say(pattern))
+	s.send(1, reply+"\n")
+	return s
+}
+
+/*
+CloseIn closes the command's stdin, signaling EOF to the command.  It is safe to call CloseIn more than once.
+
+The session is returned to enable chaining.
+*/
+func (s *Session) CloseIn() *Session {
+	if s.In != nil {
+		s.In.Close()
+	}
+	return s
+}
+
+/*
+Kill sends the running command a SIGKILL signal and closes its stdin.  It does not wait for the process to exit.
 
 If the command has already exited, Kill returns silently.
 
 The session is returned to enable chaining.
 */
 func (s *Session) Kill() *Session {
-	return s.Signal(syscall.SIGKILL)
+	return s.Signal(syscall.SIGKILL).CloseIn()
 }
 
 /*
@@ -168,14 +282,14 @@ func (s *Session) Interrupt() *Session {
 }
 
 /*
-Terminate sends the running command a SIGTERM signal.  It does not wait for the process to exit.
+Terminate sends the running command a SIGTERM signal and closes its stdin.  It does not wait for the process to exit.
 
 If the command has already exited, Terminate returns silently.
 
 The session is returned to enable chaining.
 */
 func (s *Session) Terminate() *Session {
-	return s.Signal(syscall.SIGTERM)
+	return s.Signal(syscall.SIGTERM).CloseIn()
 }
 
 /*
@@ -183,20 +297,74 @@ Signal sends the running command the passed in signal.  It does not wait for the
 
 If the command has already exited, Signal returns silently.
 
+If the command was started in its own process group (see InProcessGroup) Signal sends the signal to every process in the group instead.
+This includes processes that outlive the command itself - but not once the group has been seen empty.
+
 The session is returned to enable chaining.
 */
 func (s *Session) Signal(signal os.Signal) *Session {
-	if s.processIsAlive() {
+	if s.processGroup {
+		if s.processGroupIsAlive() {
+			signalProcessGroup(s.Command.Process.Pid, signal)
+		}
+	} else if s.processIsAlive() {
 		s.Command.Process.Signal(signal)
 	}
 	return s
 }
 
+// processGroupIsAlive returns false once the command's process group has been seen empty
+func (s *Session) processGroupIsAlive() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.checkProcessGroup()
+	return !s.processGroupGone
+}
+
+// checkProcessGroup records whether the command's process group is gone.  It must be called with s.lock held.
+func (s *Session) checkProcessGroup() {
+	if !s.processGroupGone && !processGroupExists(s.Command.Process.Pid) {
+		s.processGroupGone = true
+	}
+}
+
+func (s *Session) markProcessGroupGone() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.processGroupGone = true
+}
+
+// waitForProcessGroup waits for the processes in the command's process group, if it was started in one, to exit
+func (s *Session) waitForProcessGroup(timeout ...any) {
+	if s.processGroup {
+		EventuallyWithOffset(2, s, timeout...).Should(HaveNoProcessGroupMembers())
+	}
+}
+
+func (s *Session) send(offset int, data string) {
+	if !ExpectWithOffset(offset+1, s.In).NotTo(BeNil(), "the session's stdin is not connected to session.In") {
+		return
+	}
+	_, err := io.WriteString(s.In, data)
+	ExpectWithOffset(offset+1, err).NotTo(HaveOccurred(), "failed to write to the session's stdin")
+}
+
 func (s *Session) monitorForExit(exited chan<- struct{}) {
 	err := s.Command.Wait()
+	wallTime := time.Since(s.startTime)
+	if s.terminal != nil {
+		//give the command's descendants a moment to let go of the terminal so that no output is lost
+		select {
+		case <-s.terminalDrained:
+		case <-time.After(time.Second):
+		}
+		s.terminal.Close()
+		<-s.terminalDrained
+	}
 	s.lock.Lock()
 	s.Out.Close()
 	s.Err.Close()
+	s.resourceUsage = newResourceUsage(s.Command.ProcessState, wallTime)
 	status := s.Command.ProcessState.Sys().(syscall.WaitStatus)
 	if status.Signaled() {
 		s.exitCode = 128 + int(status.Signal())
@@ -207,6 +375,10 @@ func (s *Session) monitorForExit(exited chan<- struct{}) {
 		}
 		s.exitCode = exitStatus
 	}
+	if s.processGroup {
+		//usually the group goes away with the command - noticing right away keeps us from signaling a group that reuses its ID
+		s.checkProcessGroup()
+	}
 	s.lock.Unlock()
 
 	close(exited)
@@ -223,6 +395,8 @@ var trackedSessionsMutex = &sync.Mutex{}
 Kill sends a SIGKILL signal to all the processes started by Run, and waits for them to exit.
 The timeout specified is applied to each process killed.
 
+For commands started in their own process group (see InProcessGroup) KillAndWait also waits for every process in the group to exit.
+
 If any of the processes already exited, KillAndWait returns silently.
 */
 func KillAndWait(timeout ...any) {
@@ -230,6 +404,7 @@ func KillAndWait(timeout ...any) {
 	defer trackedSessionsMutex.Unlock()
 	for _, session := range trackedSessions {
 		session.Kill().Wait(timeout...)
+		session.waitForProcessGroup(timeout...)
 	}
 	trackedSessions = []*Session{}
 }
@@ -238,6 +413,8 @@ func KillAndWait(timeout ...any) {
 Kill sends a SIGTERM signal to all the processes started by Run, and waits for them to exit.
 The timeout specified is applied to each process killed.
 
+For commands started in their own process group (see InProcessGroup) TerminateAndWait also waits for every process in the group to exit.
+
 If any of the processes already exited, TerminateAndWait returns silently.
 */
 func TerminateAndWait(timeout ...any) {
@@ -245,6 +422,7 @@ func TerminateAndWait(timeout ...any) {
 	defer trackedSessionsMutex.Unlock()
 	for _, session := range trackedSessions {
 		session.Terminate().Wait(timeout...)
+		session.waitForProcessGroup(timeout...)
 	}
 }
 
@@ -4,6 +4,7 @@
 package gexec_test
 
 import (
+	"bytes"
 	"io"
 	"os/exec"
 	"syscall"
@@ -398,6 +399,100 @@ var _ = Describe("Session", func() {
 		})
 	})
 
+	Describe("driving stdin", func() {
+		var session *Session
+
+		startInteractive := func(script string) {
+			var err error
+			session, err = StartInteractive(exec.Command("sh", "-c", script), nil, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			DeferCleanup(func() { session.Kill().Wait() })
+		}
+
+		It("does not connect stdin when started with Start", func() {
+			session, err := Start(exec.Command("cat"), nil, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(session.In).Should(BeNil())
+			Eventually(session).Should(Exit(0))
+		})
+
+		It("connects stdin to session.In", func() {
+			startInteractive("cat")
+			_, err := session.In.Write([]byte("hello\n"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(session).Should(Say("hello"))
+		})
+
+		It("sends data and lines", func() {
+			startInteractive("cat")
+			session.Send("ab").SendLine("c")
+			Eventually(session).Should(Say("abc\n"))
+		})
+
+		It("signals EOF when stdin is closed", func() {
+			startInteractive("cat")
+			session.SendLine("last words").CloseIn().CloseIn()
+			Eventually(session).Should(Exit(0))
+			Expect(session).Should(Say("last words"))
+		})
+
+		It("waits for a prompt before replying, cooperating with Say's read cursor", func() {
+			startInteractive(`printf "name? "; read name; echo "hello $name"; printf "name? "; read name; echo "bye $name"`)
+			session.ExpectThenSend(`name\?`, "Mal").ExpectThenSend(`name\?`, "Zoe")
+			Eventually(session).Should(Say("bye Zoe"))
+			Eventually(session).Should(Exit(0))
+			Expect(session.Out.Contents()).Should(ContainSubstring("hello Mal"))
+		})
+
+		It("fails if the prompt never appears", func() {
+			startInteractive("cat")
+			failures := InterceptGomegaFailures(func() {
+				session.ExpectThenSend("never", "reply", 50*time.Millisecond)
+			})
+			Expect(failures).Should(HaveLen(1))
+			Expect(failures[0]).Should(ContainSubstring("never"))
+		})
+
+		It("fails if the session's stdin is not connected", func() {
+			session, err := Start(exec.Command("cat"), nil, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			failures := InterceptGomegaFailures(func() {
+				session.SendLine("hello")
+			})
+			Expect(failures).Should(ConsistOf(ContainSubstring("the session's stdin is not connected to session.In")))
+		})
+
+		It("fails if writing to stdin fails", func() {
+			startInteractive("cat")
+			session.CloseIn()
+			failures := InterceptGomegaFailures(func() {
+				session.SendLine("hello")
+			})
+			Expect(failures).Should(ConsistOf(ContainSubstring("failed to write to the session's stdin")))
+		})
+
+		It("closes stdin on Terminate and Kill", func() {
+			startInteractive("trap '' TERM; echo ready; cat; echo done")
+			Eventually(session).Should(Say("ready"))
+			session.Terminate()
+			Eventually(session).Should(Say("done"))
+			Eventually(session).Should(Exit(0))
+
+			startInteractive("cat")
+			session.Kill()
+			Eventually(session).Should(Exit(128 + 9))
+			_, err := session.In.Write([]byte("hello"))
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("refuses to start interactively if the command's stdin is already set", func() {
+			command := exec.Command("cat")
+			command.Stdin = bytes.NewBufferString("hello")
+			_, err := StartInteractive(command, nil, nil)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
 	Describe("when the command fails to start", func() {
 		It("should return an error", func() {
 			_, err := Start(exec.Command("agklsjdfas"), nil, nil)
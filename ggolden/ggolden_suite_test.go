@@ -0,0 +1,13 @@
+package ggolden_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGgolden(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Ggolden Suite")
+}
@@ -0,0 +1,166 @@
+/*
+Package ggolden provides a Gomega matcher that compares test output against golden files stored on disk.
+
+	Expect(renderTemplate()).To(ggolden.MatchGoldenFile("testdata/template.golden"))
+
+When a golden file is out of date you can regenerate it by running your tests in update mode, either by setting the
+GOMEGA_UPDATE_GOLDEN environment variable:
+
+	GOMEGA_UPDATE_GOLDEN=true go test ./...
+
+or, if your test binary defines an `-update` flag, by passing it in:
+
+	go test ./... -update
+
+In update mode MatchGoldenFile writes the (normalized) actual value to the golden file and always succeeds.
+*/
+package ggolden
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/matchers"
+	"github.com/onsi/gomega/types"
+)
+
+// UpdateEnvVar is the environment variable that puts MatchGoldenFile in update mode when set to a true value (e.g. "true" or "1")
+const UpdateEnvVar = "GOMEGA_UPDATE_GOLDEN"
+
+// UpdateFlag is the name of the (optional) command-line flag that puts MatchGoldenFile in update mode.  ggolden does not define this flag - it only honors it if your test binary does.
+const UpdateFlag = "update"
+
+/*
+UpdateMode returns true if golden files should be rewritten instead of compared against.
+
+This is the case when the GOMEGA_UPDATE_GOLDEN environment variable is set to a true value or when the test binary defines an `-update` flag that is set to true.
+*/
+func UpdateMode() bool {
+	if update, err := strconv.ParseBool(os.Getenv(UpdateEnvVar)); err == nil && update {
+		return true
+	}
+	if f := flag.Lookup(UpdateFlag); f != nil {
+		if update, err := strconv.ParseBool(f.Value.String()); err == nil && update {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+MatchGoldenFile succeeds if actual matches the contents of the golden file at path.
+
+Actual must be a string, a []byte, a fmt.Stringer, a *gbytes.Buffer or a gbytes.BufferProvider (e.g. a *gexec.Session).  For buffers the entire contents are compared - the read cursor is neither consulted nor advanced.
+
+The comparison depends on the golden file's extension:
+
+  - .json files are compared with MatchJSON semantics (key order and whitespace do not matter)
+  - .yaml and .yml files are compared with MatchYAML semantics
+  - all other files must match exactly, mismatches are rendered as a line diff
+
+You can pass in Normalizers to strip volatile content (line endings, timestamps, UUIDs...) before the comparison.  Normalizers are applied, in order, to both the actual value and the golden file's contents:
+
+	Expect(output).To(ggolden.MatchGoldenFile("testdata/report.golden", ggolden.NormalizeLineEndings, ggolden.NormalizeTimestamps))
+
+In update mode (see UpdateMode) the normalized actual value is written to path - creating any missing parent directories - and MatchGoldenFile succeeds.
+*/
+func MatchGoldenFile(path string, normalizers ...Normalizer) types.GomegaMatcher {
+	return &goldenFileMatcher{
+		path:        path,
+		normalizers: normalizers,
+	}
+}
+
+type goldenFileMatcher struct {
+	path        string
+	normalizers []Normalizer
+
+	comparison types.GomegaMatcher
+	actual     string
+}
+
+func (matcher *goldenFileMatcher) Match(actual any) (success bool, err error) {
+	actualString, ok := toString(actual)
+	if !ok {
+		return false, fmt.Errorf("MatchGoldenFile matcher requires a string, []byte, stringer, *gbytes.Buffer or gbytes.BufferProvider.  Got:\n%s", format.Object(actual, 1))
+	}
+	matcher.actual = matcher.normalize(actualString)
+
+	if UpdateMode() {
+		if err := os.MkdirAll(filepath.Dir(matcher.path), 0755); err != nil {
+			return false, fmt.Errorf("MatchGoldenFile failed to create the directory for golden file %s:\n%w", matcher.path, err)
+		}
+		if err := os.WriteFile(matcher.path, []byte(matcher.actual), 0644); err != nil {
+			return false, fmt.Errorf("MatchGoldenFile failed to update golden file %s:\n%w", matcher.path, err)
+		}
+		return true, nil
+	}
+
+	golden, err := os.ReadFile(matcher.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("Golden file %s does not exist.\n%s", matcher.path, updateHint())
+	} else if err != nil {
+		return false, fmt.Errorf("MatchGoldenFile failed to read golden file %s:\n%w", matcher.path, err)
+	}
+	expected := matcher.normalize(string(golden))
+
+	switch strings.ToLower(filepath.Ext(matcher.path)) {
+	case ".json":
+		matcher.comparison = &matchers.MatchJSONMatcher{JSONToMatch: expected}
+	case ".yaml", ".yml":
+		matcher.comparison = &matchers.MatchYAMLMatcher{YAMLToMatch: expected}
+	default:
+		matcher.comparison = &matchers.EqualMatcher{Expected: expected}
+	}
+	return matcher.comparison.Match(matcher.actual)
+}
+
+func (matcher *goldenFileMatcher) FailureMessage(_ any) (message string) {
+	if matcher.comparison == nil {
+		return fmt.Sprintf("Golden file %s does not match.\n%s", matcher.path, updateHint())
+	}
+	return fmt.Sprintf("Golden file %s does not match.\n%s\n\n%s", matcher.path, updateHint(), matcher.comparison.FailureMessage(matcher.actual))
+}
+
+func (matcher *goldenFileMatcher) NegatedFailureMessage(_ any) (message string) {
+	if matcher.comparison == nil {
+		// in update mode Match succeeds without comparing anything
+		return fmt.Sprintf("Expected not to match golden file %s, but it was updated to match.", matcher.path)
+	}
+	return fmt.Sprintf("Expected not to match golden file %s.\n\n%s", matcher.path, matcher.comparison.NegatedFailureMessage(matcher.actual))
+}
+
+func (matcher *goldenFileMatcher) normalize(s string) string {
+	for _, normalizer := range matcher.normalizers {
+		s = normalizer(s)
+	}
+	return s
+}
+
+func updateHint() string {
+	return fmt.Sprintf("Run your tests with %s=true to update it.", UpdateEnvVar)
+}
+
+func toString(actual any) (string, bool) {
+	switch x := actual.(type) {
+	case string:
+		return x, true
+	case []byte:
+		return string(x), true
+	case *gbytes.Buffer:
+		return string(x.Contents()), true
+	case gbytes.BufferProvider:
+		return string(x.Buffer().Contents()), true
+	case fmt.Stringer:
+		return x.String(), true
+	}
+	return "", false
+}
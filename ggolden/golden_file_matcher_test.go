@@ -0,0 +1,124 @@
+package ggolden_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/ggolden"
+)
+
+type bufferProvider struct {
+	buffer *gbytes.Buffer
+}
+
+func (b bufferProvider) Buffer() *gbytes.Buffer {
+	return b.buffer
+}
+
+var _ = Describe("MatchGoldenFile", func() {
+	var dir string
+
+	writeGolden := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	Context("with a plain golden file", func() {
+		var path string
+		BeforeEach(func() {
+			path = writeGolden("output.golden", "hello\nworld\n")
+		})
+
+		It("matches strings, byte slices and buffers with identical contents", func() {
+			Expect("hello\nworld\n").To(MatchGoldenFile(path))
+			Expect([]byte("hello\nworld\n")).To(MatchGoldenFile(path))
+			Expect(gbytes.BufferWithBytes([]byte("hello\nworld\n"))).To(MatchGoldenFile(path))
+			Expect(bufferProvider{gbytes.BufferWithBytes([]byte("hello\nworld\n"))}).To(MatchGoldenFile(path))
+			Expect("hello\nthere\n").NotTo(MatchGoldenFile(path))
+		})
+
+		It("does not advance the read cursor of buffers", func() {
+			buffer := gbytes.BufferWithBytes([]byte("hello\nworld\n"))
+			Expect(buffer).To(MatchGoldenFile(path))
+			Expect(buffer).To(gbytes.Say("hello"))
+		})
+
+		It("renders a line diff on failure", func() {
+			matcher := MatchGoldenFile(path)
+			Expect(matcher.Match("hello\nthere\n")).To(BeFalse())
+			message := matcher.FailureMessage("hello\nthere\n")
+			Expect(message).To(HavePrefix("Golden file " + path + " does not match.\nRun your tests with GOMEGA_UPDATE_GOLDEN=true to update it.\n"))
+			Expect(message).To(ContainSubstring("Diff (-actual +expected):\n    @@ -1,3 +1,3 @@\n     hello\n    -there\n    +world\n"))
+		})
+
+		It("errors when the actual value is of the wrong type", func() {
+			success, err := MatchGoldenFile(path).Match(3)
+			Expect(success).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("MatchGoldenFile matcher requires a string")))
+		})
+	})
+
+	It("errors when the golden file does not exist", func() {
+		success, err := MatchGoldenFile(filepath.Join(dir, "missing.golden")).Match("hello")
+		Expect(success).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("missing.golden does not exist.\nRun your tests with GOMEGA_UPDATE_GOLDEN=true")))
+	})
+
+	It("compares .json golden files with MatchJSON semantics", func() {
+		path := writeGolden("response.json", `{"a": 1, "b": [1, 2]}`)
+		Expect(`{"b":[1,2],"a":1}`).To(MatchGoldenFile(path))
+		Expect(`{"b":[2,1],"a":1}`).NotTo(MatchGoldenFile(path))
+	})
+
+	It("compares .yaml and .yml golden files with MatchYAML semantics", func() {
+		path := writeGolden("config.yaml", "a: 1\nb: [1, 2]\n")
+		Expect("b:\n- 1\n- 2\na: 1").To(MatchGoldenFile(path))
+		path = writeGolden("config.yml", "a: 1\n")
+		Expect("a: 2").NotTo(MatchGoldenFile(path))
+	})
+
+	It("applies normalizers to both sides", func() {
+		path := writeGolden("log.golden", "2021-03-04T05:06:07Z created 123e4567-e89b-12d3-a456-426614174000\r\nid=17\r\n")
+		actual := "2024-11-12 13:14:15.123+02:00 created 00000000-0000-0000-0000-00000000ABCD\nid=42\n"
+		Expect(actual).NotTo(MatchGoldenFile(path))
+		Expect(actual).To(MatchGoldenFile(path, NormalizeLineEndings, NormalizeTimestamps, NormalizeUUIDs, NormalizeRegexp(`id=\d+`, "id=<id>")))
+	})
+
+	Context("in update mode", func() {
+		BeforeEach(func() {
+			os.Setenv(UpdateEnvVar, "true")
+			DeferCleanup(os.Unsetenv, UpdateEnvVar)
+		})
+
+		It("reports that it is in update mode", func() {
+			Expect(UpdateMode()).To(BeTrue())
+			os.Setenv(UpdateEnvVar, "false")
+			Expect(UpdateMode()).To(BeFalse())
+		})
+
+		It("writes the normalized actual value to the golden file, creating missing directories", func() {
+			path := filepath.Join(dir, "nested", "dir", "output.golden")
+			Expect("hello\r\nworld").To(MatchGoldenFile(path, NormalizeLineEndings))
+			Expect(os.ReadFile(path)).To(Equal([]byte("hello\nworld")))
+
+			Expect("goodbye").To(MatchGoldenFile(path))
+			Expect(os.ReadFile(path)).To(Equal([]byte("goodbye")))
+		})
+
+		It("does not panic when negated", func() {
+			path := filepath.Join(dir, "output.golden")
+			failures := InterceptGomegaFailures(func() {
+				Expect("hello").NotTo(MatchGoldenFile(path))
+			})
+			Expect(failures).To(ConsistOf("Expected not to match golden file " + path + ", but it was updated to match."))
+		})
+	})
+})
@@ -0,0 +1,35 @@
+package ggolden
+
+import (
+	"regexp"
+	"strings"
+)
+
+// A Normalizer transforms the actual value and the golden file's contents before they are compared.  Use Normalizers to strip content that legitimately varies from run to run.
+type Normalizer func(string) string
+
+var timestampRegexp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+var uuidRegexp = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+
+// NormalizeLineEndings converts Windows (\r\n) and classic Mac (\r) line endings to \n
+func NormalizeLineEndings(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "\n")
+}
+
+// NormalizeTimestamps replaces RFC3339-like timestamps (e.g. 2006-01-02T15:04:05.999Z07:00 or 2006-01-02 15:04:05) with <timestamp>
+func NormalizeTimestamps(s string) string {
+	return timestampRegexp.ReplaceAllString(s, "<timestamp>")
+}
+
+// NormalizeUUIDs replaces UUIDs with <uuid>
+func NormalizeUUIDs(s string) string {
+	return uuidRegexp.ReplaceAllString(s, "<uuid>")
+}
+
+// NormalizeRegexp returns a Normalizer that replaces all matches of pattern with replacement.  replacement may refer to submatches (see regexp.Regexp.ReplaceAllString).  NormalizeRegexp panics if pattern does not compile.
+func NormalizeRegexp(pattern string, replacement string) Normalizer {
+	re := regexp.MustCompile(pattern)
+	return func(s string) string {
+		return re.ReplaceAllString(s, replacement)
+	}
+}
@@ -0,0 +1,13 @@
+package ggrpc_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGGRPC(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GGRPC Suite")
+}
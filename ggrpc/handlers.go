@@ -0,0 +1,203 @@
+package ggrpc
+
+import (
+	"io"
+
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+type GGRPCWithGomega struct {
+	gomega Gomega
+}
+
+func NewGGRPCWithGomega(gomega Gomega) *GGRPCWithGomega {
+	return &GGRPCWithGomega{
+		gomega: gomega,
+	}
+}
+
+// CombineHandlers takes variadic list of handlers and produces one handler
+// that calls each handler in order.
+func CombineHandlers(handlers ...HandlerFunc) HandlerFunc {
+	return func(call *Call) {
+		for _, handler := range handlers {
+			handler(call)
+		}
+	}
+}
+
+// VerifyMethod returns a handler that verifies that the call is to the specified method, e.g. "/sprockets.Sprockets/GetSprocket"
+//
+// For fullMethod, you may pass in a string, in which case strict equality will be applied
+// Alternatively you can pass in a matcher (HaveSuffix("/GetSprocket") for example)
+func (g GGRPCWithGomega) VerifyMethod(fullMethod any) HandlerFunc {
+	return func(call *Call) {
+		switch m := fullMethod.(type) {
+		case types.GomegaMatcher:
+			g.gomega.Expect(call.FullMethod).Should(m, "Method mismatch")
+		default:
+			g.gomega.Expect(call.FullMethod).Should(Equal(fullMethod), "Method mismatch")
+		}
+	}
+}
+
+// VerifyMetadata returns a handler that verifies that the call's metadata contains the passed in metadata.
+// Metadata keys are case insensitive.
+func (g GGRPCWithGomega) VerifyMetadata(md metadata.MD) HandlerFunc {
+	return func(call *Call) {
+		for key, values := range md {
+			g.gomega.Expect(call.Metadata.Get(key)).Should(Equal(values), "Metadata mismatch for key: %s", key)
+		}
+	}
+}
+
+// VerifyMetadataKV returns a handler that verifies that the call's metadata contains the passed in key-value pair.
+// Additional values may be passed in.
+func (g GGRPCWithGomega) VerifyMetadataKV(key string, values ...string) HandlerFunc {
+	return g.VerifyMetadata(metadata.MD{key: values})
+}
+
+func (g GGRPCWithGomega) expectProtoEqual(expected proto.Message, actual proto.Message) {
+	g.gomega.Expect(proto.Equal(expected, actual)).Should(BeTrue(), "ProtoBuf Mismatch.  Expected:\n%s\nGot:\n%s", prototext.Format(expected), prototext.Format(actual))
+}
+
+// VerifyProtoRepresenting returns a handler that verifies that the call's request message is equal to expected.
+// Use it for unary and server-streaming calls.
+func (g GGRPCWithGomega) VerifyProtoRepresenting(expected proto.Message) HandlerFunc {
+	return func(call *Call) {
+		actual := expected.ProtoReflect().New().Interface()
+		g.gomega.Expect(call.Request(actual)).Should(Succeed(), "Failed to receive request message")
+		g.expectProtoEqual(expected, actual)
+	}
+}
+
+// VerifyProtosRepresenting returns a handler that receives all (remaining) request messages and verifies that they are equal to expected.
+// Use it for client-streaming calls.
+func (g GGRPCWithGomega) VerifyProtosRepresenting(expected ...proto.Message) HandlerFunc {
+	return func(call *Call) {
+		g.gomega.Expect(expected).ShouldNot(BeEmpty(), "VerifyProtosRepresenting must be passed at least one message")
+		var actual []proto.Message
+		for {
+			msg := expected[0].ProtoReflect().New().Interface()
+			err := call.Receive(msg)
+			if err == io.EOF {
+				break
+			}
+			g.gomega.Expect(err).ShouldNot(HaveOccurred(), "Failed to receive request message")
+			actual = append(actual, msg)
+		}
+		g.gomega.Expect(len(actual)).Should(Equal(len(expected)), "Expected %d request messages, received %d", len(expected), len(actual))
+		for i := range min(len(expected), len(actual)) {
+			g.expectProtoEqual(expected[i], actual[i])
+		}
+	}
+}
+
+/*
+RespondWith returns a handler that responds to a unary or client-streaming call with the passed in message
+
+Also, RespondWith can be given optional header metadata.  The metadata is sent to the client with the response.
+*/
+func (g GGRPCWithGomega) RespondWith(response proto.Message, optionalHeader ...metadata.MD) HandlerFunc {
+	return g.RespondWithStream([]proto.Message{response}, optionalHeader...)
+}
+
+/*
+RespondWithPtr returns a handler that responds to a unary or client-streaming call with the message pointed to by response.
+This is useful when setting up a handler in a BeforeEach that is modified by nested BeforeEaches.
+
+Also, RespondWithPtr can be given optional header metadata.  The metadata is sent to the client with the response.
+*/
+func (g GGRPCWithGomega) RespondWithPtr(response *proto.Message, optionalHeader ...metadata.MD) HandlerFunc {
+	return func(call *Call) {
+		g.RespondWith(*response, optionalHeader...)(call)
+	}
+}
+
+/*
+RespondWithStream returns a handler that responds to a server-streaming or bidirectional-streaming call by sending each of the passed in messages in order
+
+Also, RespondWithStream can be given optional header metadata.  The metadata is sent to the client with the first response.
+*/
+func (g GGRPCWithGomega) RespondWithStream(responses []proto.Message, optionalHeader ...metadata.MD) HandlerFunc {
+	return func(call *Call) {
+		if len(optionalHeader) == 1 {
+			g.gomega.Expect(call.SetHeader(optionalHeader[0])).Should(Succeed(), "Failed to set header metadata")
+		}
+		for _, response := range responses {
+			if call.Send(response) != nil {
+				//the client has gone away, there's no one left to respond to
+				return
+			}
+		}
+	}
+}
+
+/*
+RespondWithStatus returns a handler that fails the call with the passed in status.  Use it to respond with rich error details:
+
+	st, _ := status.New(codes.InvalidArgument, "bad sprocket").WithDetails(&errdetails.BadRequest{...})
+	server.AppendHandlers(ggrpc.RespondWithStatus(st))
+
+Also, RespondWithStatus can be given optional trailer metadata.  The metadata is sent to the client with the status.
+*/
+func (g GGRPCWithGomega) RespondWithStatus(st *status.Status, optionalTrailer ...metadata.MD) HandlerFunc {
+	return func(call *Call) {
+		if len(optionalTrailer) == 1 {
+			call.SetTrailer(optionalTrailer[0])
+		}
+		call.SetStatus(st)
+	}
+}
+
+// RespondWithError returns a handler that fails the call with the passed in code and message
+func (g GGRPCWithGomega) RespondWithError(code codes.Code, message string) HandlerFunc {
+	return g.RespondWithStatus(status.New(code, message))
+}
+
+func VerifyMethod(fullMethod any) HandlerFunc {
+	return NewGGRPCWithGomega(gomega.Default).VerifyMethod(fullMethod)
+}
+
+func VerifyMetadata(md metadata.MD) HandlerFunc {
+	return NewGGRPCWithGomega(gomega.Default).VerifyMetadata(md)
+}
+
+func VerifyMetadataKV(key string, values ...string) HandlerFunc {
+	return NewGGRPCWithGomega(gomega.Default).VerifyMetadataKV(key, values...)
+}
+
+func VerifyProtoRepresenting(expected proto.Message) HandlerFunc {
+	return NewGGRPCWithGomega(gomega.Default).VerifyProtoRepresenting(expected)
+}
+
+func VerifyProtosRepresenting(expected ...proto.Message) HandlerFunc {
+	return NewGGRPCWithGomega(gomega.Default).VerifyProtosRepresenting(expected...)
+}
+
+func RespondWith(response proto.Message, optionalHeader ...metadata.MD) HandlerFunc {
+	return NewGGRPCWithGomega(gomega.Default).RespondWith(response, optionalHeader...)
+}
+
+func RespondWithPtr(response *proto.Message, optionalHeader ...metadata.MD) HandlerFunc {
+	return NewGGRPCWithGomega(gomega.Default).RespondWithPtr(response, optionalHeader...)
+}
+
+func RespondWithStream(responses []proto.Message, optionalHeader ...metadata.MD) HandlerFunc {
+	return NewGGRPCWithGomega(gomega.Default).RespondWithStream(responses, optionalHeader...)
+}
+
+func RespondWithStatus(st *status.Status, optionalTrailer ...metadata.MD) HandlerFunc {
+	return NewGGRPCWithGomega(gomega.Default).RespondWithStatus(st, optionalTrailer...)
+}
+
+func RespondWithError(code codes.Code, message string) HandlerFunc {
+	return NewGGRPCWithGomega(gomega.Default).RespondWithError(code, message)
+}
@@ -0,0 +1,95 @@
+package ggrpc
+
+import (
+	"fmt"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+/*
+HaveGRPCStatus succeeds if actual carries a gRPC status with the passed in code.
+Actual may be an error returned by a gRPC client, a *status.Status, or nil (which has codes.OK).
+
+You may also pass in an optional message which is matched against the status' message.  The message may be a string,
+in which case strict equality will be applied, or a matcher:
+
+	_, err := client.GetSprocket(ctx, &pb.GetSprocketRequest{Name: "Banana"})
+	Expect(err).To(ggrpc.HaveGRPCStatus(codes.NotFound))
+	Expect(err).To(ggrpc.HaveGRPCStatus(codes.NotFound, ContainSubstring("Banana")))
+*/
+func HaveGRPCStatus(code codes.Code, optionalMessage ...any) types.GomegaMatcher {
+	matcher := &haveGRPCStatusMatcher{code: code}
+	if len(optionalMessage) == 1 {
+		switch m := optionalMessage[0].(type) {
+		case types.GomegaMatcher:
+			matcher.message = m
+		default:
+			matcher.message = Equal(m)
+		}
+	}
+	return matcher
+}
+
+type haveGRPCStatusMatcher struct {
+	code    codes.Code
+	message types.GomegaMatcher
+}
+
+func statusFor(actual any) (*status.Status, error) {
+	switch a := actual.(type) {
+	case nil:
+		return status.New(codes.OK, ""), nil
+	case *status.Status:
+		return a, nil
+	case error:
+		st, ok := status.FromError(a)
+		if !ok {
+			return nil, fmt.Errorf("HaveGRPCStatus matcher expects an error that carries a gRPC status.  Got:\n%s", format.Object(actual, 1))
+		}
+		return st, nil
+	default:
+		return nil, fmt.Errorf("HaveGRPCStatus matcher expects an error, a *status.Status, or nil.  Got:\n%s", format.Object(actual, 1))
+	}
+}
+
+func (m *haveGRPCStatusMatcher) Match(actual any) (bool, error) {
+	st, err := statusFor(actual)
+	if err != nil {
+		return false, err
+	}
+	if st.Code() != m.code {
+		return false, nil
+	}
+	if m.message == nil {
+		return true, nil
+	}
+	return m.message.Match(st.Message())
+}
+
+func (m *haveGRPCStatusMatcher) FailureMessage(actual any) string {
+	return fmt.Sprintf("Expected\n%s\nto have gRPC status\n%s", formatStatus(actual), m.expectedString())
+}
+
+func (m *haveGRPCStatusMatcher) NegatedFailureMessage(actual any) string {
+	return fmt.Sprintf("Expected\n%s\nnot to have gRPC status\n%s", formatStatus(actual), m.expectedString())
+}
+
+func (m *haveGRPCStatusMatcher) expectedString() string {
+	expected := format.Indent + m.code.String()
+	if m.message != nil {
+		expected += fmt.Sprintf("\nwith a message that satisfies\n%s", format.Object(m.message, 1))
+	}
+	return expected
+}
+
+func formatStatus(actual any) string {
+	st, err := statusFor(actual)
+	if err != nil {
+		return format.Object(actual, 1)
+	}
+	return fmt.Sprintf("%s%s: %q", format.Indent, st.Code(), st.Message())
+}
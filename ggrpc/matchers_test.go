@@ -0,0 +1,49 @@
+package ggrpc_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/ggrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("HaveGRPCStatus", func() {
+	It("matches errors by code", func() {
+		err := status.Error(codes.NotFound, "no such sprocket")
+		Expect(err).To(HaveGRPCStatus(codes.NotFound))
+		Expect(err).NotTo(HaveGRPCStatus(codes.Internal))
+	})
+
+	It("matches *status.Status and nil", func() {
+		Expect(status.New(codes.Aborted, "")).To(HaveGRPCStatus(codes.Aborted))
+		Expect(nil).To(HaveGRPCStatus(codes.OK))
+	})
+
+	It("matches the message", func() {
+		err := status.Error(codes.NotFound, "no such sprocket")
+		Expect(err).To(HaveGRPCStatus(codes.NotFound, "no such sprocket"))
+		Expect(err).To(HaveGRPCStatus(codes.NotFound, ContainSubstring("sprocket")))
+		Expect(err).NotTo(HaveGRPCStatus(codes.NotFound, "no such widget"))
+	})
+
+	It("errors for errors without a gRPC status and for other types", func() {
+		success, err := HaveGRPCStatus(codes.Unknown).Match(errors.New("boom"))
+		Expect(success).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("expects an error that carries a gRPC status")))
+
+		success, err = HaveGRPCStatus(codes.OK).Match(3)
+		Expect(success).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("expects an error, a *status.Status, or nil")))
+	})
+
+	It("has informative failure messages", func() {
+		matcher := HaveGRPCStatus(codes.NotFound, ContainSubstring("widget"))
+		err := status.Error(codes.NotFound, "no such sprocket")
+		Expect(matcher.FailureMessage(err)).To(HavePrefix("Expected\n    NotFound: \"no such sprocket\"\nto have gRPC status\n    NotFound\nwith a message that satisfies\n"))
+		Expect(matcher.FailureMessage(err)).To(ContainSubstring("widget"))
+		Expect(HaveGRPCStatus(codes.NotFound).NegatedFailureMessage(err)).To(Equal("Expected\n    NotFound: \"no such sprocket\"\nnot to have gRPC status\n    NotFound"))
+	})
+})
@@ -0,0 +1,552 @@
+/*
+Package ggrpc supports testing gRPC clients by providing an in-process test server that supports registering multiple handlers.
+It is the gRPC counterpart to ghttp: incoming calls are handled by the handlers registered with AppendHandlers in order - the first call
+is handled by the first handler, the second call by the second handler, etc. - unless they match a handler registered with RouteToHandler.
+
+The server does not need any generated service code.  It accepts calls to any method and hands them to your handlers as a *Call.  ggrpc
+provides a collection of bite-size handlers that verify the incoming call and respond to it.  These can be composed together with CombineHandlers:
+
+	var _ = Describe("A Sprockets Client", func() {
+		var server *ggrpc.Server
+		var client *SprocketClient
+		BeforeEach(func() {
+			server = ggrpc.NewServer()
+			client = NewSprocketClient(server.ClientConn())
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("fetches sprockets", func() {
+			server.AppendHandlers(ggrpc.CombineHandlers(
+				ggrpc.VerifyMethod("/sprockets.Sprockets/GetSprocket"),
+				ggrpc.VerifyMetadataKV("authorization", "Bearer tk427"),
+				ggrpc.VerifyProtoRepresenting(&pb.GetSprocketRequest{Name: "Alfalfa"}),
+				ggrpc.RespondWith(&pb.Sprocket{Name: "Alfalfa", Color: "green"}),
+			))
+
+			Expect(client.Sprocket("Alfalfa")).To(Equal(Sprocket{Name: "Alfalfa", Color: "green"}))
+		})
+
+		It("handles missing sprockets", func() {
+			server.AppendHandlers(ggrpc.RespondWithError(codes.NotFound, "no such sprocket"))
+
+			_, err := client.Sprocket("Banana")
+			Expect(err).To(ggrpc.HaveGRPCStatus(codes.NotFound))
+		})
+	})
+*/
+package ggrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+const bufconnSize = 1024 * 1024
+
+var errCallCompleted = errors.New("the call has completed - request messages that the handler did not receive are no longer available")
+
+// HandlerFunc handles a single gRPC call.  Handlers respond by sending messages on the call and setting its status - see Call.
+type HandlerFunc func(call *Call)
+
+/*
+Call is a gRPC call received by a ggrpc Server.
+
+Handlers read request messages with Request and Receive, send response messages with Send, and set the call's status with SetStatus.
+If the handler does not set a status the call succeeds.
+
+Calls are also returned by Server.ReceivedCalls - request messages remain available after the call has completed, provided the handler received them.
+*/
+type Call struct {
+	//FullMethod is the full name of the called method, e.g. "/sprockets.Sprockets/GetSprocket"
+	FullMethod string
+	//Metadata is the metadata sent by the client
+	Metadata metadata.MD
+	//ReceivedAt is the time the server received the call
+	ReceivedAt time.Time
+	//Handler describes the handler that the server passed the call to: "RouteToHandler(<route>)", "AppendHandlers[<index>]", or "unhandled"
+	Handler string
+
+	stream grpc.ServerStream
+
+	//recvLock serializes receiving messages from the client (and guards next) so that lock isn't held while the call blocks in RecvMsg
+	recvLock  *sync.Mutex
+	next      int
+	lock      *sync.Mutex
+	messages  [][]byte
+	recvErr   error
+	status    *status.Status
+	completed bool
+}
+
+func newCall(stream grpc.ServerStream) *Call {
+	fullMethod, _ := grpc.MethodFromServerStream(stream)
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	return &Call{
+		FullMethod: fullMethod,
+		Metadata:   md.Copy(),
+		ReceivedAt: time.Now(),
+		stream:     stream,
+		recvLock:   &sync.Mutex{},
+		lock:       &sync.Mutex{},
+	}
+}
+
+// String returns a one-line summary of the call, e.g. "/sprockets.Sprockets/GetSprocket (AppendHandlers[0])"
+func (c *Call) String() string {
+	return fmt.Sprintf("%s (%s)", c.FullMethod, c.Handler)
+}
+
+// Context returns the call's context.  It is cancelled when the client cancels the call or the server is closed.
+func (c *Call) Context() context.Context {
+	return c.stream.Context()
+}
+
+// message returns the i-th request message, receiving messages from the client as necessary.  It must be called with recvLock held.
+func (c *Call) message(i int) ([]byte, error) {
+	for {
+		c.lock.Lock()
+		if i < len(c.messages) {
+			data := c.messages[i]
+			c.lock.Unlock()
+			return data, nil
+		}
+		recvErr, completed := c.recvErr, c.completed
+		c.lock.Unlock()
+
+		if recvErr != nil {
+			return nil, recvErr
+		}
+		if completed {
+			return nil, errCallCompleted
+		}
+		raw := &rawMessage{}
+		err := c.stream.RecvMsg(raw)
+
+		c.lock.Lock()
+		if err != nil {
+			c.recvErr = err
+		} else {
+			c.messages = append(c.messages, raw.data)
+		}
+		c.lock.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Request decodes the first request message into msg.  Use it for unary and server-streaming calls - the first request message is the only request message.
+// Request can be called any number of times and does not affect the messages returned by Receive.
+func (c *Call) Request(msg proto.Message) error {
+	c.recvLock.Lock()
+	defer c.recvLock.Unlock()
+	data, err := c.message(0)
+	if err == io.EOF {
+		return errors.New("the client did not send a request message")
+	}
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+// Receive decodes the next request message into msg.  Use it for client-streaming and bidirectional-streaming calls.
+// Receive returns io.EOF once the client has finished sending messages.
+func (c *Call) Receive(msg proto.Message) error {
+	c.recvLock.Lock()
+	defer c.recvLock.Unlock()
+	data, err := c.message(c.next)
+	if err != nil {
+		return err
+	}
+	c.next++
+	return proto.Unmarshal(data, msg)
+}
+
+// Send sends msg to the client.  Unary and client-streaming calls must send exactly one message.
+func (c *Call) Send(msg proto.Message) error {
+	return c.stream.SendMsg(msg)
+}
+
+// SetHeader sets header metadata that is sent to the client with the first response message (or with the status, if no message is sent)
+func (c *Call) SetHeader(md metadata.MD) error {
+	return c.stream.SetHeader(md)
+}
+
+// SetTrailer sets trailer metadata that is sent to the client with the status
+func (c *Call) SetTrailer(md metadata.MD) {
+	c.stream.SetTrailer(md)
+}
+
+// SetStatus sets the status the call completes with.  The call succeeds if no status (or a status with codes.OK) is set.
+func (c *Call) SetStatus(st *status.Status) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.status = st
+}
+
+// Status returns the status the call completes (or completed) with
+func (c *Call) Status() *status.Status {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.status == nil {
+		return status.New(codes.OK, "")
+	}
+	return c.status
+}
+
+func (c *Call) complete() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.completed = true
+}
+
+// rawMessage carries a request message's wire bytes so that the server can accept calls to any method and leave decoding to the handlers
+type rawMessage struct {
+	data []byte
+}
+
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	switch x := v.(type) {
+	case *rawMessage:
+		return x.data, nil
+	case proto.Message:
+		return proto.Marshal(x)
+	default:
+		return nil, fmt.Errorf("ggrpc can only send protobuf messages.  Got %T", v)
+	}
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	switch x := v.(type) {
+	case *rawMessage:
+		x.data = append([]byte{}, data...)
+		return nil
+	case proto.Message:
+		return proto.Unmarshal(data, x)
+	default:
+		return fmt.Errorf("ggrpc can only receive protobuf messages.  Got %T", v)
+	}
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+type routedHandler struct {
+	fullMethodRegexp *regexp.Regexp
+	fullMethod       string
+	handler          HandlerFunc
+}
+
+func (rh routedHandler) route() string {
+	if rh.fullMethodRegexp != nil {
+		return rh.fullMethodRegexp.String()
+	}
+	return rh.fullMethod
+}
+
+/*
+Server is an in-process gRPC server for testing gRPC clients.
+
+Calls are handled in the following order:
+
+ 1. If the call's method matches a handler registered with RouteToHandler, that handler is called.
+ 2. Otherwise, if there are handlers registered via AppendHandlers, those handlers are called in order.
+ 3. If all registered handlers have been called then:
+    a) If SetAllowUnhandledCalls(true) has been called, the call fails with the code set by SetUnhandledCallCode (codes.Unimplemented by default)
+    b) Otherwise the call fails and the current test is marked as failed.
+*/
+type Server struct {
+	//The underlying gRPC server
+	GRPCServer *grpc.Server
+
+	//If provided, ggrpc will log about each call received to the provided io.Writer
+	//Defaults to nil
+	//If you're using Ginkgo, set this to GinkgoWriter to get improved output during failures
+	Writer io.Writer
+
+	listener   net.Listener
+	bufconn    *bufconn.Listener
+	clientConn *grpc.ClientConn
+
+	receivedCalls   []*Call
+	callHandlers    []HandlerFunc
+	routedHandlers  []routedHandler
+	allowUnhandled  bool
+	unhandledCode   codes.Code
+	calls           int
+	rwMutex         *sync.RWMutex
+	serveTerminated chan struct{}
+}
+
+func new(listener net.Listener) *Server {
+	s := &Server{
+		listener:        listener,
+		unhandledCode:   codes.Unimplemented,
+		rwMutex:         &sync.RWMutex{},
+		serveTerminated: make(chan struct{}),
+	}
+	s.GRPCServer = grpc.NewServer(grpc.UnknownServiceHandler(s.handleStream), grpc.ForceServerCodec(rawCodec{}))
+	go func() {
+		defer close(s.serveTerminated)
+		s.GRPCServer.Serve(listener)
+	}()
+	return s
+}
+
+// NewServer returns a new, started, `*ggrpc.Server` that listens on an in-memory connection.  Use ClientConn (or Dial) to connect to it.
+func NewServer() *Server {
+	listener := bufconn.Listen(bufconnSize)
+	s := new(listener)
+	s.bufconn = listener
+	return s
+}
+
+// NewLoopbackServer returns a new, started, `*ggrpc.Server` that listens on a loopback TCP port.  Use it when the client under test needs an address to dial - see Addr.
+func NewLoopbackServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	ExpectWithOffset(1, err).ShouldNot(HaveOccurred(), "Failed to listen on a loopback port")
+	return new(listener)
+}
+
+// Addr returns the address on which the server is listening.  For servers returned by NewServer this is not a dialable address - use ClientConn or Dial instead.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Dial returns a new client connection to the server.  The connection does not use transport security.  You are responsible for closing it.
+func (s *Server) Dial(opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	target := s.Addr()
+	defaults := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if s.bufconn != nil {
+		target = "passthrough:///bufconn"
+		defaults = append(defaults, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.bufconn.DialContext(ctx)
+		}))
+	}
+	return grpc.NewClient(target, append(defaults, opts...)...)
+}
+
+// ClientConn returns a client connection to the server.  The connection is shared by all callers and is closed when the server is closed.
+func (s *Server) ClientConn() *grpc.ClientConn {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	if s.clientConn == nil {
+		conn, err := s.Dial()
+		ExpectWithOffset(1, err).ShouldNot(HaveOccurred(), "Failed to connect to the ggrpc server")
+		s.clientConn = conn
+	}
+	return s.clientConn
+}
+
+// Close should be called at the end of each test.  It closes the shared client connection and stops the server - calls that are still in flight are cancelled.
+func (s *Server) Close() {
+	s.rwMutex.Lock()
+	conn := s.clientConn
+	s.clientConn = nil
+	s.rwMutex.Unlock()
+
+	if conn != nil {
+		conn.Close()
+	}
+	s.GRPCServer.Stop()
+	<-s.serveTerminated
+}
+
+func (s *Server) handleStream(_ any, stream grpc.ServerStream) (err error) {
+	call := newCall(stream)
+	defer func() {
+		call.complete()
+		e := recover()
+		if e != nil {
+			err = status.Error(codes.Internal, "ggrpc handler panicked")
+		}
+
+		//As in ghttp: if the handler is panicking because an assertion failed Ginkgo is already aware of the failure.
+		//Otherwise we fail the test (and, since the failed assertion panics, we need to defer within our defer).
+		eAsString, ok := e.(string)
+		if ok && strings.Contains(eAsString, "defer GinkgoRecover()") {
+			return
+		}
+		defer func() {
+			recover()
+		}()
+		Expect(e).Should(BeNil(), "Handler Panicked")
+	}()
+
+	if s.Writer != nil {
+		fmt.Fprintf(s.Writer, "GGRPC Received Call: %s\n", call.FullMethod)
+	}
+
+	s.rwMutex.Lock()
+	var handler HandlerFunc
+	if rh, ok := s.handlerForRoute(call.FullMethod); ok {
+		handler = rh.handler
+		call.Handler = "RouteToHandler(" + rh.route() + ")"
+	} else if s.calls < len(s.callHandlers) {
+		handler = s.callHandlers[s.calls]
+		call.Handler = fmt.Sprintf("AppendHandlers[%d]", s.calls)
+		s.calls++
+	} else {
+		call.Handler = "unhandled"
+	}
+	s.receivedCalls = append(s.receivedCalls, call)
+	s.rwMutex.Unlock()
+
+	if handler == nil {
+		if !s.GetAllowUnhandledCalls() {
+			Expect(call.FullMethod+"\n"+format.Object(call.Metadata, 1)).Should(BeNil(), "Received Unhandled Call")
+		}
+		return status.Errorf(s.GetUnhandledCallCode(), "ggrpc received an unhandled call to %s", call.FullMethod)
+	}
+	handler(call)
+	return call.Status().Err()
+}
+
+// ReceivedCalls returns all calls received by the server (both handled and unhandled calls)
+func (s *Server) ReceivedCalls() []*Call {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return append([]*Call{}, s.receivedCalls...)
+}
+
+// RouteToHandler can be used to register handlers that will always handle calls to methods that match fullMethod.
+//
+// fullMethod may be either a string (e.g. "/sprockets.Sprockets/GetSprocket") or a *regexp.Regexp (e.g. regexp.MustCompile(`^/sprockets\.Sprockets/`)).
+func (s *Server) RouteToHandler(fullMethod any, handler HandlerFunc) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	rh := routedHandler{handler: handler}
+	switch m := fullMethod.(type) {
+	case *regexp.Regexp:
+		rh.fullMethodRegexp = m
+	case string:
+		rh.fullMethod = m
+	default:
+		panic("fullMethod must be a string or a regular expression")
+	}
+
+	for i, existingRH := range s.routedHandlers {
+		if existingRH.route() == rh.route() && (existingRH.fullMethodRegexp == nil) == (rh.fullMethodRegexp == nil) {
+			s.routedHandlers[i] = rh
+			return
+		}
+	}
+	s.routedHandlers = append(s.routedHandlers, rh)
+}
+
+func (s *Server) handlerForRoute(fullMethod string) (routedHandler, bool) {
+	for _, rh := range s.routedHandlers {
+		if rh.fullMethodRegexp != nil {
+			if rh.fullMethodRegexp.MatchString(fullMethod) {
+				return rh, true
+			}
+		} else if rh.fullMethod == fullMethod {
+			return rh, true
+		}
+	}
+	return routedHandler{}, false
+}
+
+// AppendHandlers will appends HandlerFuncs to the server's list of registered handlers.  The first incoming call is handled by the first handler, the second by the second, etc...
+func (s *Server) AppendHandlers(handlers ...HandlerFunc) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.callHandlers = append(s.callHandlers, handlers...)
+}
+
+// SetHandler overrides the registered handler at the passed in index with the passed in handler
+// This is useful, for example, when a server has been set up in a shared context, but must be tweaked
+// for a particular test.
+func (s *Server) SetHandler(index int, handler HandlerFunc) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.callHandlers[index] = handler
+}
+
+// GetHandler returns the handler registered at the passed in index.
+func (s *Server) GetHandler(index int) HandlerFunc {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.callHandlers[index]
+}
+
+// WrapHandler combines the passed in handler with the handler registered at the passed in index.
+//
+// If the currently registered handler is A, and the new passed in handler is B then
+// WrapHandler will generate a new handler that first calls A, then calls B, and assign it to index
+func (s *Server) WrapHandler(index int, handler HandlerFunc) {
+	existingHandler := s.GetHandler(index)
+	s.SetHandler(index, CombineHandlers(existingHandler, handler))
+}
+
+// Reset clears the server's registered handlers and received calls
+func (s *Server) Reset() {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.calls = 0
+	s.receivedCalls = nil
+	s.callHandlers = nil
+	s.routedHandlers = nil
+}
+
+// SetAllowUnhandledCalls enables the server to accept unhandled calls.
+func (s *Server) SetAllowUnhandledCalls(allowUnhandledCalls bool) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.allowUnhandled = allowUnhandledCalls
+}
+
+// GetAllowUnhandledCalls returns true if the server accepts unhandled calls.
+func (s *Server) GetAllowUnhandledCalls() bool {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.allowUnhandled
+}
+
+// SetUnhandledCallCode sets the status code that unhandled calls fail with.  Defaults to codes.Unimplemented.
+func (s *Server) SetUnhandledCallCode(code codes.Code) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.unhandledCode = code
+}
+
+// GetUnhandledCallCode returns the status code that unhandled calls fail with
+func (s *Server) GetUnhandledCallCode() codes.Code {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.unhandledCode
+}
@@ -0,0 +1,320 @@
+package ggrpc_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/ggrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const getSprocket = "/sprockets.Sprockets/GetSprocket"
+const listSprockets = "/sprockets.Sprockets/ListSprockets"
+const uploadSprockets = "/sprockets.Sprockets/UploadSprockets"
+
+var _ = Describe("TestServer", func() {
+	var s *Server
+	var ctx context.Context
+
+	BeforeEach(func() {
+		s = NewServer()
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		s.Close()
+	})
+
+	invoke := func(method string, request string, opts ...grpc.CallOption) (string, error) {
+		response := &wrapperspb.StringValue{}
+		err := s.ClientConn().Invoke(ctx, method, wrapperspb.String(request), response, opts...)
+		return response.GetValue(), err
+	}
+
+	Describe("handling calls", func() {
+		It("handles calls with the appended handlers in order", func() {
+			s.AppendHandlers(
+				RespondWith(wrapperspb.String("first")),
+				RespondWith(wrapperspb.String("second")),
+			)
+
+			Expect(invoke(getSprocket, "a")).To(Equal("first"))
+			Expect(invoke(listSprockets, "b")).To(Equal("second"))
+		})
+
+		It("routes calls to the routed handlers", func() {
+			s.AppendHandlers(RespondWith(wrapperspb.String("appended")))
+			s.RouteToHandler(getSprocket, RespondWith(wrapperspb.String("routed")))
+			s.RouteToHandler(regexp.MustCompile(`^/sprockets\.Sprockets/List`), RespondWith(wrapperspb.String("regexp")))
+
+			Expect(invoke(getSprocket, "a")).To(Equal("routed"))
+			Expect(invoke(getSprocket, "a")).To(Equal("routed"))
+			Expect(invoke(listSprockets, "a")).To(Equal("regexp"))
+			Expect(invoke(uploadSprockets, "a")).To(Equal("appended"))
+
+			s.RouteToHandler(getSprocket, RespondWith(wrapperspb.String("rerouted")))
+			Expect(invoke(getSprocket, "a")).To(Equal("rerouted"))
+		})
+
+		It("records the calls it receives", func() {
+			s.AppendHandlers(VerifyProtoRepresenting(wrapperspb.String("a")))
+			s.RouteToHandler(listSprockets, func(call *Call) {})
+			s.SetAllowUnhandledCalls(true)
+
+			invoke(getSprocket, "a")
+			invoke(listSprockets, "b")
+			invoke(uploadSprockets, "c")
+
+			calls := s.ReceivedCalls()
+			Expect(calls).To(HaveLen(3))
+			Expect(calls[0].String()).To(Equal(getSprocket + " (AppendHandlers[0])"))
+			Expect(calls[1].String()).To(Equal(listSprockets + " (RouteToHandler(" + listSprockets + "))"))
+			Expect(calls[2].String()).To(Equal(uploadSprockets + " (unhandled)"))
+
+			request := &wrapperspb.StringValue{}
+			Expect(calls[0].Request(request)).To(Succeed())
+			Expect(request.GetValue()).To(Equal("a"))
+			Expect(calls[1].Request(request)).To(MatchError(ContainSubstring("the call has completed")))
+		})
+
+		It("logs calls to the Writer", func() {
+			buffer := &bytes.Buffer{}
+			s.Writer = buffer
+			s.AppendHandlers(func(call *Call) {})
+			invoke(getSprocket, "a")
+			Expect(buffer.String()).To(Equal("GGRPC Received Call: " + getSprocket + "\n"))
+		})
+
+		It("can set, get, and wrap handlers", func() {
+			s.AppendHandlers(RespondWith(wrapperspb.String("a")), RespondWith(wrapperspb.String("b")))
+			s.SetHandler(1, RespondWith(wrapperspb.String("c")))
+			s.WrapHandler(0, RespondWithError(codes.Aborted, "wrapped"))
+			Expect(s.GetHandler(1)).NotTo(BeNil())
+
+			_, err := invoke(getSprocket, "a")
+			Expect(err).To(HaveGRPCStatus(codes.Aborted, "wrapped"))
+			Expect(invoke(getSprocket, "a")).To(Equal("c"))
+		})
+
+		It("can be reset", func() {
+			s.AppendHandlers(RespondWith(wrapperspb.String("a")))
+			s.RouteToHandler(listSprockets, RespondWith(wrapperspb.String("b")))
+			invoke(getSprocket, "a")
+			s.Reset()
+			Expect(s.ReceivedCalls()).To(BeEmpty())
+
+			s.AppendHandlers(RespondWith(wrapperspb.String("c")))
+			Expect(invoke(listSprockets, "a")).To(Equal("c"))
+		})
+
+		Context("when there are no more handlers", func() {
+			It("fails the test", func() {
+				var err error
+				failures := InterceptGomegaFailures(func() {
+					_, err = invoke(getSprocket, "a", grpc.Header(&metadata.MD{}))
+				})
+				Expect(failures).To(ConsistOf(And(ContainSubstring("Received Unhandled Call"), ContainSubstring(getSprocket))))
+				Expect(err).To(HaveGRPCStatus(codes.Unimplemented))
+			})
+
+			It("fails the call with the unhandled call code when unhandled calls are allowed", func() {
+				s.SetAllowUnhandledCalls(true)
+				Expect(s.GetAllowUnhandledCalls()).To(BeTrue())
+				_, err := invoke(getSprocket, "a")
+				Expect(err).To(HaveGRPCStatus(codes.Unimplemented, "ggrpc received an unhandled call to "+getSprocket))
+
+				s.SetUnhandledCallCode(codes.Unavailable)
+				Expect(s.GetUnhandledCallCode()).To(Equal(codes.Unavailable))
+				_, err = invoke(getSprocket, "a")
+				Expect(err).To(HaveGRPCStatus(codes.Unavailable))
+			})
+		})
+
+		It("fails the test when a handler panics", func() {
+			s.AppendHandlers(func(call *Call) {
+				panic("boom")
+			})
+			var err error
+			failures := InterceptGomegaFailures(func() {
+				_, err = invoke(getSprocket, "a")
+			})
+			Expect(failures).To(ConsistOf(ContainSubstring("Handler Panicked")))
+			Expect(err).To(HaveGRPCStatus(codes.Internal))
+		})
+	})
+
+	Describe("verifying calls", func() {
+		It("verifies the method", func() {
+			s.AppendHandlers(VerifyMethod(getSprocket), VerifyMethod(HaveSuffix("/GetSprocket")))
+			failures := InterceptGomegaFailures(func() {
+				invoke(getSprocket, "a")
+				invoke(listSprockets, "a")
+			})
+			Expect(failures).To(ConsistOf(ContainSubstring("Method mismatch")))
+		})
+
+		It("verifies metadata", func() {
+			s.AppendHandlers(
+				VerifyMetadataKV("Authorization", "Bearer tk427"),
+				VerifyMetadata(metadata.Pairs("x-sprocket", "red", "x-sprocket", "blue")),
+			)
+			failures := InterceptGomegaFailures(func() {
+				ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer tk427")
+				invoke(getSprocket, "a")
+				ctx = metadata.AppendToOutgoingContext(context.Background(), "x-sprocket", "red")
+				invoke(getSprocket, "a")
+			})
+			Expect(failures).To(ConsistOf(ContainSubstring("Metadata mismatch for key: x-sprocket")))
+		})
+
+		It("verifies the request message", func() {
+			s.AppendHandlers(
+				CombineHandlers(VerifyProtoRepresenting(wrapperspb.String("a")), RespondWith(wrapperspb.String("ok"))),
+				VerifyProtoRepresenting(wrapperspb.String("a")),
+			)
+			failures := InterceptGomegaFailures(func() {
+				Expect(invoke(getSprocket, "a")).To(Equal("ok"))
+				invoke(getSprocket, "b")
+			})
+			Expect(failures).To(ConsistOf(And(ContainSubstring("ProtoBuf Mismatch"), MatchRegexp(`value:\s+"b"`))))
+		})
+
+		It("verifies client-streamed request messages", func() {
+			s.AppendHandlers(
+				CombineHandlers(
+					VerifyProtosRepresenting(wrapperspb.String("a"), wrapperspb.String("b")),
+					RespondWith(wrapperspb.String("ok")),
+				),
+				VerifyProtosRepresenting(wrapperspb.String("a"), wrapperspb.String("b")),
+			)
+
+			upload := func(values ...string) (string, error) {
+				stream, err := s.ClientConn().NewStream(ctx, &grpc.StreamDesc{ClientStreams: true}, uploadSprockets)
+				Expect(err).NotTo(HaveOccurred())
+				for _, value := range values {
+					Expect(stream.SendMsg(wrapperspb.String(value))).To(Succeed())
+				}
+				Expect(stream.CloseSend()).To(Succeed())
+				response := &wrapperspb.StringValue{}
+				err = stream.RecvMsg(response)
+				return response.GetValue(), err
+			}
+
+			Expect(upload("a", "b")).To(Equal("ok"))
+			failures := InterceptGomegaFailures(func() {
+				upload("a")
+			})
+			Expect(failures).To(ConsistOf(ContainSubstring("Expected 2 request messages, received 1")))
+		})
+	})
+
+	Describe("responding to calls", func() {
+		It("responds with header metadata", func() {
+			s.AppendHandlers(RespondWith(wrapperspb.String("a"), metadata.Pairs("x-sprocket", "red")))
+			header := metadata.MD{}
+			Expect(invoke(getSprocket, "a", grpc.Header(&header))).To(Equal("a"))
+			Expect(header.Get("x-sprocket")).To(Equal([]string{"red"}))
+		})
+
+		It("responds with a pointer to a message", func() {
+			var response proto.Message = wrapperspb.String("a")
+			s.AppendHandlers(RespondWithPtr(&response))
+			response = wrapperspb.String("b")
+			Expect(invoke(getSprocket, "a")).To(Equal("b"))
+		})
+
+		It("responds with streams", func() {
+			s.AppendHandlers(RespondWithStream([]proto.Message{wrapperspb.String("a"), wrapperspb.String("b")}))
+			stream, err := s.ClientConn().NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, listSprockets)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stream.SendMsg(wrapperspb.String("all"))).To(Succeed())
+			Expect(stream.CloseSend()).To(Succeed())
+
+			var values []string
+			for {
+				response := &wrapperspb.StringValue{}
+				err := stream.RecvMsg(response)
+				if err == io.EOF {
+					break
+				}
+				Expect(err).NotTo(HaveOccurred())
+				values = append(values, response.GetValue())
+			}
+			Expect(values).To(Equal([]string{"a", "b"}))
+		})
+
+		It("can hold bidirectional conversations", func() {
+			s.AppendHandlers(func(call *Call) {
+				for {
+					request := &wrapperspb.StringValue{}
+					if call.Receive(request) != nil {
+						return
+					}
+					call.Send(wrapperspb.String(request.GetValue() + "!"))
+				}
+			})
+			stream, err := s.ClientConn().NewStream(ctx, &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}, "/sprockets.Sprockets/Chat")
+			Expect(err).NotTo(HaveOccurred())
+			for _, value := range []string{"hi", "bye"} {
+				Expect(stream.SendMsg(wrapperspb.String(value))).To(Succeed())
+				response := &wrapperspb.StringValue{}
+				Expect(stream.RecvMsg(response)).To(Succeed())
+				Expect(response.GetValue()).To(Equal(value + "!"))
+			}
+			Expect(stream.CloseSend()).To(Succeed())
+			Expect(stream.RecvMsg(&wrapperspb.StringValue{})).To(MatchError(io.EOF))
+		})
+
+		It("can set the status while another goroutine waits for a message", func() {
+			s.AppendHandlers(func(call *Call) {
+				received := make(chan error, 1)
+				go func() {
+					received <- call.Receive(&wrapperspb.StringValue{})
+				}()
+				Consistently(received, "50ms").ShouldNot(Receive())
+				call.SetStatus(status.New(codes.Aborted, "gave up waiting"))
+				Expect(call.Status().Code()).To(Equal(codes.Aborted))
+			})
+			stream, err := s.ClientConn().NewStream(ctx, &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}, "/sprockets.Sprockets/Chat")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stream.RecvMsg(&wrapperspb.StringValue{})).To(HaveGRPCStatus(codes.Aborted, "gave up waiting"))
+		})
+
+		It("responds with statuses and trailers", func() {
+			st, err := status.New(codes.InvalidArgument, "bad sprocket").WithDetails(wrapperspb.String("color"))
+			Expect(err).NotTo(HaveOccurred())
+			s.AppendHandlers(RespondWithStatus(st, metadata.Pairs("x-retry", "never")))
+
+			trailer := metadata.MD{}
+			_, err = invoke(getSprocket, "a", grpc.Trailer(&trailer))
+			Expect(err).To(HaveGRPCStatus(codes.InvalidArgument, "bad sprocket"))
+			Expect(status.Convert(err).Details()).To(HaveLen(1))
+			Expect(trailer.Get("x-retry")).To(Equal([]string{"never"}))
+		})
+	})
+
+	Describe("loopback servers", func() {
+		It("listens on a loopback port", func() {
+			server := NewLoopbackServer()
+			DeferCleanup(server.Close)
+			server.AppendHandlers(RespondWith(wrapperspb.String("loopback")))
+			Expect(server.Addr()).To(HavePrefix("127.0.0.1:"))
+
+			conn, err := server.Dial()
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(conn.Close)
+			response := &wrapperspb.StringValue{}
+			Expect(conn.Invoke(ctx, getSprocket, wrapperspb.String("a"), response)).To(Succeed())
+			Expect(response.GetValue()).To(Equal("loopback"))
+		})
+	})
+})
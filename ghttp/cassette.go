@@ -0,0 +1,428 @@
+package ghttp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	. "github.com/onsi/gomega"
+	"go.yaml.in/yaml/v3"
+)
+
+// ScrubbedHeaderValue replaces the values of scrubbed headers when a cassette is saved
+const ScrubbedHeaderValue = "[SCRUBBED]"
+
+// DefaultScrubbedHeaders lists the headers that a CassetteServer scrubs from the cassettes it records unless told otherwise
+var DefaultScrubbedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+/*
+Cassette holds a sequence of recorded HTTP interactions.  Cassettes are stored as YAML if the cassette path ends in .yaml or .yml and as JSON otherwise.
+
+You will typically not need to work with Cassettes directly - instead use NewRecordingServer and NewReplayServer.
+*/
+type Cassette struct {
+	Interactions []CassetteInteraction `json:"interactions" yaml:"interactions"`
+}
+
+// CassetteInteraction is a single recorded request and the response the upstream server returned for it
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request" yaml:"request"`
+	Response CassetteResponse `json:"response" yaml:"response"`
+}
+
+// CassetteRequest is the recorded form of a request.  Body is stored as text when it is valid UTF-8 and base64-encoded in Base64Body otherwise.
+type CassetteRequest struct {
+	Method     string      `json:"method" yaml:"method"`
+	Path       string      `json:"path" yaml:"path"`
+	RawQuery   string      `json:"rawQuery,omitempty" yaml:"rawQuery,omitempty"`
+	Header     http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+	Body       string      `json:"body,omitempty" yaml:"body,omitempty"`
+	Base64Body string      `json:"base64Body,omitempty" yaml:"base64Body,omitempty"`
+}
+
+// BodyBytes returns the recorded request body
+func (r CassetteRequest) BodyBytes() ([]byte, error) {
+	return decodeCassetteBody(r.Body, r.Base64Body)
+}
+
+// CassetteResponse is the recorded form of a response.  Body is stored as text when it is valid UTF-8 and base64-encoded in Base64Body otherwise.
+type CassetteResponse struct {
+	StatusCode int         `json:"statusCode" yaml:"statusCode"`
+	Header     http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+	Body       string      `json:"body,omitempty" yaml:"body,omitempty"`
+	Base64Body string      `json:"base64Body,omitempty" yaml:"base64Body,omitempty"`
+}
+
+// BodyBytes returns the recorded response body
+func (r CassetteResponse) BodyBytes() ([]byte, error) {
+	return decodeCassetteBody(r.Body, r.Base64Body)
+}
+
+func encodeCassetteBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return "", base64.StdEncoding.EncodeToString(body)
+}
+
+func decodeCassetteBody(body string, base64Body string) ([]byte, error) {
+	if base64Body != "" {
+		return base64.StdEncoding.DecodeString(base64Body)
+	}
+	return []byte(body), nil
+}
+
+func isYAMLCassette(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// LoadCassette reads the cassette stored at path
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := &Cassette{}
+	if isYAMLCassette(path) {
+		err = yaml.Unmarshal(data, cassette)
+	} else {
+		err = json.Unmarshal(data, cassette)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return cassette, nil
+}
+
+// Save writes the cassette to path, creating any missing parent directories
+func (c *Cassette) Save(path string) error {
+	var data []byte
+	var err error
+	if isYAMLCassette(path) {
+		data, err = yaml.Marshal(c)
+	} else {
+		data, err = json.MarshalIndent(c, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+/*
+CassetteRequestMatcher decides whether an incoming request (whose body has been read into body) corresponds to a recorded request.
+A replaying CassetteServer serves the first recorded interaction for which all of its CassetteRequestMatchers return true.
+
+ghttp provides MatchMethod, MatchPath, MatchQuery, and MatchBody.  You can pass your own to CassetteServer.MatchRequestsOn.
+*/
+type CassetteRequestMatcher func(req *http.Request, body []byte, recorded CassetteRequest) bool
+
+// MatchMethod matches requests with the same HTTP method
+func MatchMethod(req *http.Request, body []byte, recorded CassetteRequest) bool {
+	return req.Method == recorded.Method
+}
+
+// MatchPath matches requests with the same URL path
+func MatchPath(req *http.Request, body []byte, recorded CassetteRequest) bool {
+	return req.URL.Path == recorded.Path
+}
+
+// MatchQuery matches requests with equivalent query parameters (the order of parameters is ignored)
+func MatchQuery(req *http.Request, body []byte, recorded CassetteRequest) bool {
+	recordedQuery, err := url.ParseQuery(recorded.RawQuery)
+	if err != nil {
+		return false
+	}
+	query := req.URL.Query()
+	if len(query) != len(recordedQuery) {
+		return false
+	}
+	for key, values := range query {
+		recordedValues, ok := recordedQuery[key]
+		if !ok || strings.Join(values, "\x00") != strings.Join(recordedValues, "\x00") {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchBody matches requests with identical bodies
+func MatchBody(req *http.Request, body []byte, recorded CassetteRequest) bool {
+	recordedBody, err := recorded.BodyBytes()
+	return err == nil && bytes.Equal(body, recordedBody)
+}
+
+/*
+CassetteServer is a ghttp.Server that either records the exchanges it proxies to a real upstream server into a cassette file (see NewRecordingServer)
+or replays the exchanges stored in a cassette file without touching the network (see NewReplayServer).
+
+CassetteServer embeds *Server so you can still use RouteToHandler and AppendHandlers - requests that match a routed or appended handler are served by that handler
+and are neither recorded nor replayed.  ReceivedRequests, RecordedRequests, and the HaveReceivedRequest matchers work as usual.
+*/
+type CassetteServer struct {
+	*Server
+
+	// Transport is used to forward requests to the upstream server when recording.  Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	cassettePath string
+	upstream     *url.URL
+	recording    bool
+
+	lock            *sync.Mutex
+	cassette        *Cassette
+	played          []bool
+	requestMatchers []CassetteRequestMatcher
+	scrubbedHeaders []string
+}
+
+func newCassetteServer(cassettePath string) *CassetteServer {
+	s := &CassetteServer{
+		Server:          new(),
+		cassettePath:    cassettePath,
+		lock:            &sync.Mutex{},
+		cassette:        &Cassette{},
+		requestMatchers: []CassetteRequestMatcher{MatchMethod, MatchPath, MatchQuery},
+		scrubbedHeaders: append([]string{}, DefaultScrubbedHeaders...),
+	}
+	return s
+}
+
+/*
+NewRecordingServer returns a started CassetteServer that proxies every request it can't otherwise handle to upstreamURL and records the exchange.
+The recorded exchanges are written to cassettePath (as YAML if it ends in .yaml or .yml, and JSON otherwise) when you call Save or Close.  Any existing cassette at cassettePath is replaced.
+
+Headers listed in DefaultScrubbedHeaders (and any you add with ScrubHeaders) are scrubbed from the saved cassette so that secrets don't end up in your repository.
+*/
+func NewRecordingServer(upstreamURL string, cassettePath string) *CassetteServer {
+	s := newCassetteServer(cassettePath)
+	upstream, err := url.Parse(upstreamURL)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred(), "Invalid upstream URL")
+	s.upstream = upstream
+	s.recording = true
+	s.fallbackHandler, s.fallbackHandlerName = s.record, "cassette (recording)"
+	s.HTTPTestServer = httptest.NewServer(s.Server)
+	return s
+}
+
+/*
+NewReplayServer returns a started CassetteServer that serves the interactions recorded in the cassette at cassettePath.
+
+Each request is matched against the recorded requests using the server's CassetteRequestMatchers (by default MatchMethod, MatchPath, and MatchQuery - use MatchRequestsOn to change this).
+The first matching interaction that has not yet been played is served.  If every matching interaction has already been played the last matching interaction is served again - this
+allows clients to poll an endpoint more often than they did while the cassette was being recorded.
+
+Requests that match no interaction are treated like any other unhandled request: the test fails unless you SetAllowUnhandledRequests(true).
+*/
+func NewReplayServer(cassettePath string) *CassetteServer {
+	s := newCassetteServer(cassettePath)
+	cassette, err := LoadCassette(cassettePath)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred(), "Failed to load cassette")
+	if cassette != nil {
+		s.cassette = cassette
+	}
+	s.played = make([]bool, len(s.cassette.Interactions))
+	s.fallbackHandler, s.fallbackHandlerName = s.replay, "cassette (replaying)"
+	s.HTTPTestServer = httptest.NewServer(s.Server)
+	return s
+}
+
+// IsRecording returns true if the server was created by NewRecordingServer
+func (s *CassetteServer) IsRecording() bool {
+	return s.recording
+}
+
+// MatchRequestsOn replaces the CassetteRequestMatchers used to pair incoming requests with recorded interactions when replaying
+func (s *CassetteServer) MatchRequestsOn(matchers ...CassetteRequestMatcher) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.requestMatchers = matchers
+}
+
+// ScrubHeaders adds to the set of headers whose values are replaced with ScrubbedHeaderValue when the cassette is saved
+func (s *CassetteServer) ScrubHeaders(headers ...string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.scrubbedHeaders = append(s.scrubbedHeaders, headers...)
+}
+
+// Cassette returns a copy of the server's cassette.  When recording, scrubbed headers have already been scrubbed.
+func (s *CassetteServer) Cassette() Cassette {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.scrubbedCassette()
+}
+
+// UnplayedInteractions returns the interactions that a replaying server has not served yet.  This is useful for asserting that your client made every recorded request.
+func (s *CassetteServer) UnplayedInteractions() []CassetteInteraction {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	unplayed := []CassetteInteraction{}
+	for i, played := range s.played {
+		if !played {
+			unplayed = append(unplayed, s.cassette.Interactions[i])
+		}
+	}
+	return unplayed
+}
+
+// Save writes the recorded interactions to the cassette path.  It does nothing when replaying.
+func (s *CassetteServer) Save() error {
+	if !s.recording {
+		return nil
+	}
+	s.lock.Lock()
+	cassette := s.scrubbedCassette()
+	s.lock.Unlock()
+	return cassette.Save(s.cassettePath)
+}
+
+// Close saves the cassette (when recording) and shuts down the server.  A failure to save the cassette fails the test.
+func (s *CassetteServer) Close() {
+	s.Server.Close()
+	ExpectWithOffset(1, s.Save()).To(Succeed(), "Failed to save cassette")
+}
+
+func (s *CassetteServer) scrubbedCassette() Cassette {
+	scrub := func(header http.Header) http.Header {
+		if header == nil {
+			return nil
+		}
+		header = header.Clone()
+		for _, key := range s.scrubbedHeaders {
+			key = http.CanonicalHeaderKey(key)
+			if values, ok := header[key]; ok {
+				for i := range values {
+					values[i] = ScrubbedHeaderValue
+				}
+			}
+		}
+		return header
+	}
+	cassette := Cassette{Interactions: make([]CassetteInteraction, len(s.cassette.Interactions))}
+	for i, interaction := range s.cassette.Interactions {
+		interaction.Request.Header = scrub(interaction.Request.Header)
+		interaction.Response.Header = scrub(interaction.Response.Header)
+		cassette.Interactions[i] = interaction
+	}
+	return cassette
+}
+
+func (s *CassetteServer) record(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	Expect(err).NotTo(HaveOccurred(), "Failed to read request body")
+
+	upstreamURL := *s.upstream
+	upstreamURL.Path = strings.TrimSuffix(upstreamURL.Path, "/") + req.URL.Path
+	upstreamURL.RawPath = ""
+	upstreamURL.RawQuery = req.URL.RawQuery
+	upstreamReq, err := http.NewRequestWithContext(req.Context(), req.Method, upstreamURL.String(), bytes.NewReader(body))
+	Expect(err).NotTo(HaveOccurred(), "Failed to construct upstream request")
+	upstreamReq.Header = req.Header.Clone()
+
+	transport := s.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(upstreamReq)
+	Expect(err).NotTo(HaveOccurred(), "Failed to reach upstream server")
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	Expect(err).NotTo(HaveOccurred(), "Failed to read upstream response body")
+
+	interaction := CassetteInteraction{
+		Request: CassetteRequest{
+			Method:   req.Method,
+			Path:     req.URL.Path,
+			RawQuery: req.URL.RawQuery,
+			Header:   req.Header.Clone(),
+		},
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+		},
+	}
+	interaction.Request.Body, interaction.Request.Base64Body = encodeCassetteBody(body)
+	interaction.Response.Body, interaction.Response.Base64Body = encodeCassetteBody(respBody)
+	//the body we hand back is already fully read, so the transfer-related headers no longer apply
+	interaction.Response.Header.Del("Content-Length")
+	interaction.Response.Header.Del("Transfer-Encoding")
+
+	s.lock.Lock()
+	s.cassette.Interactions = append(s.cassette.Interactions, interaction)
+	s.lock.Unlock()
+
+	writeCassetteResponse(w, interaction.Response.Header, resp.StatusCode, respBody)
+}
+
+func (s *CassetteServer) replay(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	Expect(err).NotTo(HaveOccurred(), "Failed to read request body")
+
+	s.lock.Lock()
+	index, lastMatch := -1, -1
+	for i, interaction := range s.cassette.Interactions {
+		if !s.matches(req, body, interaction.Request) {
+			continue
+		}
+		if !s.played[i] {
+			index = i
+			break
+		}
+		lastMatch = i
+	}
+	if index == -1 {
+		index = lastMatch
+	}
+	var response CassetteResponse
+	if index != -1 {
+		s.played[index] = true
+		response = s.cassette.Interactions[index].Response
+	}
+	s.lock.Unlock()
+
+	if index == -1 {
+		if s.GetAllowUnhandledRequests() {
+			w.WriteHeader(s.GetUnhandledRequestStatusCode())
+			return
+		}
+		Expect(fmt.Sprintf("%s %s", req.Method, req.URL.RequestURI())).Should(BeNil(), "Received a request that matches no interaction in cassette %s", s.cassettePath)
+		return
+	}
+
+	respBody, err := response.BodyBytes()
+	Expect(err).NotTo(HaveOccurred(), "Failed to decode recorded response body")
+	writeCassetteResponse(w, response.Header, response.StatusCode, respBody)
+}
+
+func (s *CassetteServer) matches(req *http.Request, body []byte, recorded CassetteRequest) bool {
+	for _, matcher := range s.requestMatchers {
+		if !matcher(req, body, recorded) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeCassetteResponse(w http.ResponseWriter, header http.Header, statusCode int, body []byte) {
+	for key, values := range header {
+		w.Header()[key] = append([]string{}, values...)
+	}
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
@@ -0,0 +1,221 @@
+package ghttp_test
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Cassettes", func() {
+	var upstream *Server
+	var cassettePath string
+
+	BeforeEach(func() {
+		upstream = NewServer()
+		cassettePath = filepath.Join(GinkgoT().TempDir(), "cassettes", "sprockets.json")
+	})
+
+	AfterEach(func() {
+		upstream.Close()
+	})
+
+	do := func(method string, url string, body string, header ...string) (int, http.Header, string) {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return resp.StatusCode, resp.Header, string(data)
+	}
+
+	record := func() {
+		upstream.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest("GET", "/api/sprockets", "color=red"),
+				VerifyHeaderKV("Authorization", "Bearer secret"),
+				RespondWith(http.StatusOK, `[{"color":"red"}]`, http.Header{"Content-Type": []string{"application/json"}, "Set-Cookie": []string{"session=secret"}}),
+			),
+			CombineHandlers(
+				VerifyRequest("POST", "/api/sprockets"),
+				VerifyBody([]byte(`{"color":"blue"}`)),
+				RespondWith(http.StatusCreated, `{"id":1}`),
+			),
+			CombineHandlers(
+				VerifyRequest("POST", "/api/sprockets"),
+				VerifyBody([]byte(`{"color":"green"}`)),
+				RespondWith(http.StatusCreated, `{"id":2}`),
+			),
+			CombineHandlers(
+				VerifyRequest("GET", "/api/binary"),
+				RespondWith(http.StatusOK, []byte{0xff, 0x00, 0xfe}),
+			),
+		)
+
+		recorder := NewRecordingServer(upstream.URL()+"/api", cassettePath)
+		recorder.ScrubHeaders("X-Api-Key")
+		Expect(recorder.IsRecording()).To(BeTrue())
+
+		status, header, body := do("GET", recorder.URL()+"/sprockets?color=red", "", "Authorization", "Bearer secret", "X-Api-Key", "key")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(body).To(Equal(`[{"color":"red"}]`))
+
+		status, _, body = do("POST", recorder.URL()+"/sprockets", `{"color":"blue"}`)
+		Expect(status).To(Equal(http.StatusCreated))
+		Expect(body).To(Equal(`{"id":1}`))
+		status, _, body = do("POST", recorder.URL()+"/sprockets", `{"color":"green"}`)
+		Expect(status).To(Equal(http.StatusCreated))
+		Expect(body).To(Equal(`{"id":2}`))
+		_, _, body = do("GET", recorder.URL()+"/binary", "")
+		Expect([]byte(body)).To(Equal([]byte{0xff, 0x00, 0xfe}))
+
+		Expect(upstream.ReceivedRequests()).To(HaveLen(4))
+		Expect(recorder).To(HaveReceivedRequest(RequestTo("GET", "/sprockets"), HaveField("Handler", "cassette (recording)")))
+		recorder.Close()
+	}
+
+	Describe("recording", func() {
+		It("proxies requests to the upstream and saves the exchanges, scrubbing secrets", func() {
+			record()
+
+			cassette, err := LoadCassette(cassettePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cassette.Interactions).To(HaveLen(4))
+
+			first := cassette.Interactions[0]
+			Expect(first.Request.Method).To(Equal("GET"))
+			Expect(first.Request.Path).To(Equal("/sprockets"))
+			Expect(first.Request.RawQuery).To(Equal("color=red"))
+			Expect(first.Request.Header.Get("Authorization")).To(Equal(ScrubbedHeaderValue))
+			Expect(first.Request.Header.Get("X-Api-Key")).To(Equal(ScrubbedHeaderValue))
+			Expect(first.Response.StatusCode).To(Equal(http.StatusOK))
+			Expect(first.Response.Header.Get("Set-Cookie")).To(Equal(ScrubbedHeaderValue))
+			Expect(first.Response.Body).To(Equal(`[{"color":"red"}]`))
+
+			Expect(cassette.Interactions[1].Request.Body).To(Equal(`{"color":"blue"}`))
+
+			binary := cassette.Interactions[3].Response
+			Expect(binary.Body).To(BeEmpty())
+			Expect(binary.Base64Body).NotTo(BeEmpty())
+			Expect(binary.BodyBytes()).To(Equal([]byte{0xff, 0x00, 0xfe}))
+
+			data, err := os.ReadFile(cassettePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring("secret"))
+		})
+
+		It("saves YAML cassettes when the path has a YAML extension", func() {
+			cassettePath = strings.TrimSuffix(cassettePath, ".json") + ".yaml"
+			record()
+
+			data, err := os.ReadFile(cassettePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(HavePrefix("interactions:\n"))
+
+			cassette, err := LoadCassette(cassettePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cassette.Interactions).To(HaveLen(4))
+			Expect(cassette.Interactions[3].Response.BodyBytes()).To(Equal([]byte{0xff, 0x00, 0xfe}))
+		})
+
+		It("does not record requests served by routed or appended handlers", func() {
+			recorder := NewRecordingServer(upstream.URL(), cassettePath)
+			recorder.RouteToHandler("GET", "/local", RespondWith(http.StatusTeapot, "local"))
+			status, _, _ := do("GET", recorder.URL()+"/local", "")
+			Expect(status).To(Equal(http.StatusTeapot))
+			recorder.Close()
+
+			Expect(upstream.ReceivedRequests()).To(BeEmpty())
+			cassette, err := LoadCassette(cassettePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cassette.Interactions).To(BeEmpty())
+		})
+	})
+
+	Describe("replaying", func() {
+		var replayer *CassetteServer
+
+		BeforeEach(func() {
+			record()
+			upstream.Close()
+			replayer = NewReplayServer(cassettePath)
+		})
+
+		AfterEach(func() {
+			replayer.Close()
+		})
+
+		It("serves the recorded responses without touching the upstream", func() {
+			Expect(replayer.IsRecording()).To(BeFalse())
+			status, header, body := do("GET", replayer.URL()+"/sprockets?color=red", "")
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(header.Get("Content-Type")).To(Equal("application/json"))
+			Expect(body).To(Equal(`[{"color":"red"}]`))
+
+			_, _, body = do("GET", replayer.URL()+"/binary", "")
+			Expect([]byte(body)).To(Equal([]byte{0xff, 0x00, 0xfe}))
+
+			Expect(replayer).To(HaveReceivedRequest(RequestTo("GET", "/binary"), HaveField("Handler", "cassette (replaying)")))
+		})
+
+		It("plays matching interactions in order and then repeats the last one", func() {
+			_, _, body := do("POST", replayer.URL()+"/sprockets", `{"color":"green"}`)
+			Expect(body).To(Equal(`{"id":1}`))
+			_, _, body = do("POST", replayer.URL()+"/sprockets", `{"color":"green"}`)
+			Expect(body).To(Equal(`{"id":2}`))
+			_, _, body = do("POST", replayer.URL()+"/sprockets", `{"color":"green"}`)
+			Expect(body).To(Equal(`{"id":2}`))
+		})
+
+		It("can match on the request body", func() {
+			replayer.MatchRequestsOn(MatchMethod, MatchPath, MatchBody)
+			_, _, body := do("POST", replayer.URL()+"/sprockets", `{"color":"green"}`)
+			Expect(body).To(Equal(`{"id":2}`))
+			_, _, body = do("POST", replayer.URL()+"/sprockets", `{"color":"blue"}`)
+			Expect(body).To(Equal(`{"id":1}`))
+		})
+
+		It("reports the interactions that have not been played", func() {
+			do("GET", replayer.URL()+"/sprockets?color=red", "")
+			do("GET", replayer.URL()+"/binary", "")
+			unplayed := replayer.UnplayedInteractions()
+			Expect(unplayed).To(HaveLen(2))
+			Expect(unplayed[0].Request.Body).To(Equal(`{"color":"blue"}`))
+			Expect(unplayed[1].Request.Body).To(Equal(`{"color":"green"}`))
+		})
+
+		It("fails when a request matches no interaction", func() {
+			failures := InterceptGomegaFailures(func() {
+				status, _, _ := do("GET", replayer.URL()+"/sprockets?color=purple", "")
+				Expect(status).To(Equal(http.StatusInternalServerError))
+			})
+			Expect(failures).To(ContainElement(ContainSubstring("Received a request that matches no interaction in cassette")))
+		})
+
+		It("returns the unhandled status code for unmatched requests when unhandled requests are allowed", func() {
+			replayer.SetAllowUnhandledRequests(true)
+			replayer.SetUnhandledRequestStatusCode(http.StatusNotFound)
+			status, _, _ := do("GET", replayer.URL()+"/nope", "")
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	It("fails when the cassette can't be loaded", func() {
+		failures := InterceptGomegaFailures(func() {
+			server := NewReplayServer(filepath.Join(GinkgoT().TempDir(), "missing.json"))
+			server.Close()
+		})
+		Expect(failures).To(ConsistOf(ContainSubstring("Failed to load cassette")))
+	})
+})
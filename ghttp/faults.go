@@ -0,0 +1,160 @@
+package ghttp
+
+import (
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+)
+
+// Delay returns a handler that waits for duration (or until the client gives up on the request).  Combine it with other handlers to simulate a slow server:
+//
+//	server.AppendHandlers(ghttp.CombineHandlers(
+//		ghttp.Delay(2*time.Second),
+//		ghttp.RespondWith(http.StatusOK, "finally"),
+//	))
+func (g GHTTPWithGomega) Delay(duration time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-time.After(duration):
+		case <-req.Context().Done():
+		}
+	}
+}
+
+// RandomDelay is like Delay but waits for a random duration between min and max
+func (g GHTTPWithGomega) RandomDelay(min time.Duration, max time.Duration) http.HandlerFunc {
+	g.gomega.Expect(max).Should(BeNumerically(">=", min), "RandomDelay's max must not be less than its min")
+	return func(w http.ResponseWriter, req *http.Request) {
+		duration := min
+		if max > min {
+			duration += time.Duration(rand.Int63n(int64(max - min)))
+		}
+		g.Delay(duration)(w, req)
+	}
+}
+
+/*
+FailTimes returns a handler that fails the first n requests it receives and then passes all subsequent requests to handler.  By default failing requests receive
+an empty http.StatusServiceUnavailable response - pass in a failure handler to fail differently (e.g. with HijackAndClose).
+
+Since FailTimes handles several requests it is typically registered with RouteToHandler:
+
+	server.RouteToHandler("GET", "/sprockets", ghttp.FailTimes(2, ghttp.RespondWith(http.StatusOK, "sprockets")))
+*/
+func (g GHTTPWithGomega) FailTimes(n int, handler http.HandlerFunc, failure ...http.HandlerFunc) http.HandlerFunc {
+	fail := g.RespondWith(http.StatusServiceUnavailable, "")
+	if len(failure) == 1 {
+		fail = failure[0]
+	}
+	lock := &sync.Mutex{}
+	calls := 0
+	return func(w http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		calls++
+		failing := calls <= n
+		lock.Unlock()
+		if failing {
+			fail(w, req)
+		} else {
+			handler(w, req)
+		}
+	}
+}
+
+func (g GHTTPWithGomega) hijack(w http.ResponseWriter) net.Conn {
+	conn, buf, err := http.NewResponseController(w).Hijack()
+	g.gomega.Expect(err).ShouldNot(HaveOccurred(), "Failed to hijack the connection")
+	if buf != nil && buf.Writer.Buffered() > 0 {
+		buf.Flush()
+	}
+	return conn
+}
+
+// HijackAndClose returns a handler that closes the connection without sending a response.  Clients typically see an EOF.
+func (g GHTTPWithGomega) HijackAndClose() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		g.hijack(w).Close()
+	}
+}
+
+// ResetConnection returns a handler that resets the connection (by closing it with SO_LINGER set to 0) without sending a response.  Clients typically see "connection reset by peer".
+func (g GHTTPWithGomega) ResetConnection() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		conn := g.hijack(w)
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			tcpConn.SetLinger(0)
+		}
+		conn.Close()
+	}
+}
+
+// RespondWithRaw returns a handler that writes response to the connection verbatim and then closes it.  Use it to send malformed HTTP responses:
+//
+//	server.AppendHandlers(ghttp.RespondWithRaw("HTTP/1.1 two hundred OK\r\n\r\n"))
+func (g GHTTPWithGomega) RespondWithRaw(response string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		conn := g.hijack(w)
+		defer conn.Close()
+		conn.Write([]byte(response))
+	}
+}
+
+type droppingResponseWriter struct {
+	http.ResponseWriter
+	remaining int
+}
+
+func (w *droppingResponseWriter) Write(data []byte) (int, error) {
+	if len(data) <= w.remaining {
+		w.remaining -= len(data)
+		return w.ResponseWriter.Write(data)
+	}
+	w.ResponseWriter.Write(data[:w.remaining])
+	http.NewResponseController(w.ResponseWriter).Flush()
+	panic(http.ErrAbortHandler)
+}
+
+func (w *droppingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// DropConnectionAfter returns a handler that passes the request to handler but cuts the connection once handler has written n bytes of the response body.
+// The client receives the response headers and the first n bytes of the body and then sees the body end unexpectedly.
+func (g GHTTPWithGomega) DropConnectionAfter(n int, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		handler(&droppingResponseWriter{ResponseWriter: w, remaining: n}, req)
+	}
+}
+
+func Delay(duration time.Duration) http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).Delay(duration)
+}
+
+func RandomDelay(min time.Duration, max time.Duration) http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).RandomDelay(min, max)
+}
+
+func FailTimes(n int, handler http.HandlerFunc, failure ...http.HandlerFunc) http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).FailTimes(n, handler, failure...)
+}
+
+func HijackAndClose() http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).HijackAndClose()
+}
+
+func ResetConnection() http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).ResetConnection()
+}
+
+func RespondWithRaw(response string) http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).RespondWithRaw(response)
+}
+
+func DropConnectionAfter(n int, handler http.HandlerFunc) http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).DropConnectionAfter(n, handler)
+}
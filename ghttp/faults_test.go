@@ -0,0 +1,149 @@
+package ghttp_test
+
+import (
+	"io"
+	"net/http"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Fault injection", func() {
+	var s *Server
+	var client *http.Client
+
+	BeforeEach(func() {
+		s = NewServer()
+		//avoid the transport transparently retrying requests on reused connections
+		client = &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	})
+
+	AfterEach(func() {
+		s.Close()
+	})
+
+	get := func() (*http.Response, error) {
+		resp, err := client.Get(s.URL() + "/sprockets")
+		if err == nil {
+			DeferCleanup(resp.Body.Close)
+		}
+		return resp, err
+	}
+
+	Describe("Delay", func() {
+		It("delays the response", func() {
+			s.AppendHandlers(CombineHandlers(Delay(100*time.Millisecond), RespondWith(http.StatusOK, "slow")))
+			start := time.Now()
+			resp, err := get()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(HaveHTTPBody("slow"))
+			Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+		})
+
+		It("lets clients time out", func() {
+			s.AppendHandlers(CombineHandlers(Delay(time.Minute), RespondWith(http.StatusOK, "too slow")))
+			client.Timeout = 50 * time.Millisecond
+			start := time.Now()
+			_, err := get()
+			Expect(err).To(MatchError(ContainSubstring("Client.Timeout exceeded")))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+	})
+
+	Describe("RandomDelay", func() {
+		It("delays the response by a duration between min and max", func() {
+			s.AppendHandlers(CombineHandlers(RandomDelay(50*time.Millisecond, 100*time.Millisecond), RespondWith(http.StatusOK, "slow")))
+			start := time.Now()
+			_, err := get()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+		})
+
+		It("fails when max is less than min", func() {
+			failures := InterceptGomegaFailures(func() {
+				RandomDelay(time.Second, time.Millisecond)
+			})
+			Expect(failures).To(ConsistOf(ContainSubstring("RandomDelay's max must not be less than its min")))
+		})
+	})
+
+	Describe("FailTimes", func() {
+		It("fails the first n requests and then succeeds", func() {
+			s.RouteToHandler("GET", "/sprockets", FailTimes(2, RespondWith(http.StatusOK, "sprockets")))
+			for range 2 {
+				resp, err := get()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(HaveHTTPStatus(http.StatusServiceUnavailable))
+			}
+			for range 2 {
+				resp, err := get()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp).To(HaveHTTPBody("sprockets"))
+			}
+		})
+
+		It("can fail with a custom failure handler", func() {
+			s.RouteToHandler("GET", "/sprockets", FailTimes(1, RespondWith(http.StatusOK, "sprockets"), HijackAndClose()))
+			_, err := get()
+			Expect(err).To(HaveOccurred())
+			resp, err := get()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(HaveHTTPBody("sprockets"))
+		})
+	})
+
+	Describe("HijackAndClose", func() {
+		It("closes the connection without responding", func() {
+			s.AppendHandlers(CombineHandlers(VerifyRequest("GET", "/sprockets"), HijackAndClose()))
+			_, err := get()
+			Expect(err).To(MatchError(io.EOF))
+			Expect(s.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Describe("ResetConnection", func() {
+		It("resets the connection", func() {
+			s.AppendHandlers(ResetConnection())
+			_, err := get()
+			Expect(err).To(Or(MatchError(syscall.ECONNRESET), MatchError(io.EOF)))
+		})
+	})
+
+	Describe("RespondWithRaw", func() {
+		It("writes the response verbatim", func() {
+			s.AppendHandlers(RespondWithRaw("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nhi"))
+			resp, err := get()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(HaveHTTPBody("hi"))
+		})
+
+		It("can send malformed responses", func() {
+			s.AppendHandlers(RespondWithRaw("HTTP/1.1 two hundred OK\r\n\r\n"))
+			_, err := get()
+			Expect(err).To(MatchError(ContainSubstring("malformed HTTP status code")))
+		})
+	})
+
+	Describe("DropConnectionAfter", func() {
+		It("cuts the connection after n bytes of the body", func() {
+			s.AppendHandlers(DropConnectionAfter(5, RespondWith(http.StatusOK, "hello world", http.Header{"X-Sprocket": []string{"red"}})))
+			resp, err := get()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.Header.Get("X-Sprocket")).To(Equal("red"))
+			body, err := io.ReadAll(resp.Body)
+			Expect(string(body)).To(Equal("hello"))
+			Expect(err).To(MatchError(io.ErrUnexpectedEOF))
+		})
+
+		It("does nothing if the body is shorter than n bytes", func() {
+			s.AppendHandlers(DropConnectionAfter(100, RespondWith(http.StatusOK, "hello world")))
+			resp, err := get()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(HaveHTTPBody("hello world"))
+		})
+	})
+})
@@ -171,6 +171,23 @@ func (g GHTTPWithGomega) VerifyJSONRepresenting(object any) http.HandlerFunc {
 	)
 }
 
+// VerifyJSONSchema returns a handler that verifies that the body of the request is a JSON document
+// that is valid according to the passed in JSON Schema (inline or a path to a schema file).
+// It does this using Gomega's MatchJSONSchema method
+//
+// VerifyJSONSchema also verifies that the request's content type is application/json
+func (g GHTTPWithGomega) VerifyJSONSchema(schema any) http.HandlerFunc {
+	return CombineHandlers(
+		g.VerifyMimeType("application/json"),
+		func(w http.ResponseWriter, req *http.Request) {
+			body, err := gutil.ReadAll(req.Body)
+			req.Body.Close()
+			g.gomega.Expect(err).ShouldNot(HaveOccurred())
+			g.gomega.Expect(body).Should(MatchJSONSchema(schema), "JSON Schema Mismatch")
+		},
+	)
+}
+
 // VerifyForm returns a handler that verifies a request contains the specified form values.
 //
 // The request must contain *all* of the specified values, but it is allowed to have additional
@@ -391,6 +408,10 @@ func VerifyJSONRepresenting(object any) http.HandlerFunc {
 	return NewGHTTPWithGomega(gomega.Default).VerifyJSONRepresenting(object)
 }
 
+func VerifyJSONSchema(schema any) http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).VerifyJSONSchema(schema)
+}
+
 func VerifyForm(values url.Values) http.HandlerFunc {
 	return NewGHTTPWithGomega(gomega.Default).VerifyForm(values)
 }
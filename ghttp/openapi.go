@@ -0,0 +1,719 @@
+package ghttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/gomega"
+	"go.yaml.in/yaml/v3"
+)
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var openAPIPathParameterRegexp = regexp.MustCompile(`\{[^/{}]+\}`)
+
+type openAPIOperation struct {
+	id             string
+	method         string
+	path           string
+	pathRegexp     *regexp.Regexp
+	pathParameters []string
+	parameters     []map[string]any
+	requestBody    map[string]any
+	responses      map[string]any
+}
+
+func (o *openAPIOperation) String() string {
+	return fmt.Sprintf("%s (%s %s)", o.id, o.method, o.path)
+}
+
+/*
+OpenAPIServer is a ghttp.Server whose routes are generated from an OpenAPI 3 spec.  Construct one with NewServerFromOpenAPI.
+
+Every operation in the spec is registered with RouteToHandler.  When a request arrives for an operation the server validates it against the spec - its
+path parameters, query parameters, headers, and body - and fails the test with a description of the first violation it finds.  It then responds with the
+example payload the spec provides for the operation's first 2xx response (falling back to an example synthesized from the response's schema).  Use
+RespondToOperation to provide a different response.
+
+OpenAPIServer embeds *Server, so ReceivedRequests, RecordedRequests, AppendHandlers, and the rest of Server's API work as usual.  Requests for paths
+that are not in the spec are unhandled.
+*/
+type OpenAPIServer struct {
+	*Server
+
+	spec       map[string]any
+	basePath   string
+	operations map[string]*openAPIOperation
+
+	lock      *sync.Mutex
+	responses map[string]http.HandlerFunc
+}
+
+/*
+NewServerFromOpenAPI returns a started OpenAPIServer for the OpenAPI 3 spec (in YAML or JSON) at specPath.
+
+OpenAPI 3.1 schemas are draft 2020-12 JSON Schemas and are validated as such.  OpenAPI 3.0 schemas are converted first: nullable and boolean exclusiveMinimum/exclusiveMaximum are translated to their JSON Schema equivalents.
+If the spec's first server has a URL with a path (e.g. https://api.example.com/v1) routes are mounted under that path.
+*/
+func NewServerFromOpenAPI(specPath string) *OpenAPIServer {
+	s := &OpenAPIServer{
+		Server:     new(),
+		operations: map[string]*openAPIOperation{},
+		lock:       &sync.Mutex{},
+		responses:  map[string]http.HandlerFunc{},
+	}
+	spec, err := loadOpenAPISpec(specPath)
+	ExpectWithOffset(1, err).ShouldNot(HaveOccurred(), "Failed to load OpenAPI spec")
+	if spec != nil {
+		s.spec = spec
+		err = s.registerOperations()
+		ExpectWithOffset(1, err).ShouldNot(HaveOccurred(), "Invalid OpenAPI spec")
+	}
+	s.HTTPTestServer = httptest.NewServer(s.Server)
+	return s
+}
+
+func loadOpenAPISpec(specPath string) (map[string]any, error) {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, err
+	}
+	//YAML is a superset of JSON so this handles both
+	var decoded any
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", specPath, err)
+	}
+	spec, ok := normalizeYAML(decoded).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain an OpenAPI document", specPath)
+	}
+	version, _ := spec["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("%s is not an OpenAPI 3 document (openapi: %q)", specPath, version)
+	}
+	if strings.HasPrefix(version, "3.0") {
+		convertOpenAPI30Schemas(spec)
+	}
+	return spec, nil
+}
+
+// normalizeYAML converts the map[any]any values YAML produces for mappings with non-string keys (e.g. response status codes) to map[string]any
+func normalizeYAML(node any) any {
+	switch n := node.(type) {
+	case map[string]any:
+		for key, value := range n {
+			n[key] = normalizeYAML(value)
+		}
+		return n
+	case map[any]any:
+		converted := make(map[string]any, len(n))
+		for key, value := range n {
+			converted[fmt.Sprint(key)] = normalizeYAML(value)
+		}
+		return converted
+	case []any:
+		for i, value := range n {
+			n[i] = normalizeYAML(value)
+		}
+		return n
+	default:
+		return node
+	}
+}
+
+// convertOpenAPI30Schemas rewrites the OpenAPI 3.0 schema keywords that differ from JSON Schema 2020-12
+func convertOpenAPI30Schemas(node any) {
+	switch n := node.(type) {
+	case map[string]any:
+		if nullable, ok := n["nullable"].(bool); ok {
+			delete(n, "nullable")
+			if t, ok := n["type"].(string); ok && nullable {
+				n["type"] = []any{t, "null"}
+			}
+		}
+		for _, bound := range []string{"Minimum", "Maximum"} {
+			exclusive, ok := n["exclusive"+bound].(bool)
+			if !ok {
+				continue
+			}
+			delete(n, "exclusive"+bound)
+			if limit, ok := n[strings.ToLower(bound)]; ok && exclusive {
+				n["exclusive"+bound] = limit
+				delete(n, strings.ToLower(bound))
+			}
+		}
+		for _, value := range n {
+			convertOpenAPI30Schemas(value)
+		}
+	case []any:
+		for _, value := range n {
+			convertOpenAPI30Schemas(value)
+		}
+	}
+}
+
+// resolve follows local $refs (e.g. #/components/parameters/limit)
+func (s *OpenAPIServer) resolve(node any) (map[string]any, error) {
+	for range maxOpenAPIRefDepth {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an object, got %v", node)
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m, nil
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil, fmt.Errorf("only local $refs are supported, got %q", ref)
+		}
+		node = any(s.spec)
+		for _, token := range strings.Split(ref[2:], "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			container, ok := node.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("could not resolve $ref %q", ref)
+			}
+			if node, ok = container[token]; !ok {
+				return nil, fmt.Errorf("could not resolve $ref %q", ref)
+			}
+		}
+	}
+	return nil, fmt.Errorf("too many nested $refs")
+}
+
+const maxOpenAPIRefDepth = 32
+
+func (s *OpenAPIServer) registerOperations() error {
+	if servers, ok := s.spec["servers"].([]any); ok && len(servers) > 0 {
+		if server, ok := servers[0].(map[string]any); ok {
+			serverURL, _ := server["url"].(string)
+			if variables, ok := server["variables"].(map[string]any); ok {
+				for name, variable := range variables {
+					if v, ok := variable.(map[string]any); ok {
+						serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", fmt.Sprint(v["default"]))
+					}
+				}
+			}
+			if u, err := url.Parse(serverURL); err == nil {
+				s.basePath = strings.TrimSuffix(u.Path, "/")
+			}
+		}
+	}
+
+	paths, _ := s.spec["paths"].(map[string]any)
+	templates := make([]string, 0, len(paths))
+	for template := range paths {
+		templates = append(templates, template)
+	}
+	//register literal paths ahead of templated ones so that /pets/mine wins over /pets/{petId}
+	sort.Slice(templates, func(i, j int) bool {
+		pi, pj := len(openAPIPathParameterRegexp.FindAllString(templates[i], -1)), len(openAPIPathParameterRegexp.FindAllString(templates[j], -1))
+		if pi != pj {
+			return pi < pj
+		}
+		return templates[i] < templates[j]
+	})
+
+	for _, template := range templates {
+		pathItem, err := s.resolve(paths[template])
+		if err != nil {
+			return fmt.Errorf("path %s: %w", template, err)
+		}
+		pathParameters := []string{}
+		pattern := "^" + regexp.QuoteMeta(s.basePath)
+		last := 0
+		for _, loc := range openAPIPathParameterRegexp.FindAllStringIndex(template, -1) {
+			pattern += regexp.QuoteMeta(template[last:loc[0]]) + "([^/]+)"
+			pathParameters = append(pathParameters, template[loc[0]+1:loc[1]-1])
+			last = loc[1]
+		}
+		pattern += regexp.QuoteMeta(template[last:]) + "$"
+		pathRegexp := regexp.MustCompile(pattern)
+
+		for _, method := range openAPIMethods {
+			node, ok := pathItem[method]
+			if !ok {
+				continue
+			}
+			operation, err := s.newOperation(strings.ToUpper(method), template, pathItem, node)
+			if err != nil {
+				return err
+			}
+			operation.pathRegexp, operation.pathParameters = pathRegexp, pathParameters
+			s.operations[operation.id] = operation
+			s.RouteToHandler(operation.method, pathRegexp, s.handlerFor(operation))
+		}
+	}
+	return nil
+}
+
+func (s *OpenAPIServer) newOperation(method string, template string, pathItem map[string]any, node any) (*openAPIOperation, error) {
+	spec, err := s.resolve(node)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, template, err)
+	}
+	operation := &openAPIOperation{method: method, path: template}
+	operation.id, _ = spec["operationId"].(string)
+	if operation.id == "" {
+		operation.id = method + " " + template
+	}
+
+	//operation-level parameters override path-level parameters with the same name and location
+	parameters := map[string]map[string]any{}
+	order := []string{}
+	for _, list := range []any{pathItem["parameters"], spec["parameters"]} {
+		entries, _ := list.([]any)
+		for _, entry := range entries {
+			parameter, err := s.resolve(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", operation, err)
+			}
+			key := fmt.Sprint(parameter["in"]) + ":" + fmt.Sprint(parameter["name"])
+			if _, ok := parameters[key]; !ok {
+				order = append(order, key)
+			}
+			parameters[key] = parameter
+		}
+	}
+	for _, key := range order {
+		operation.parameters = append(operation.parameters, parameters[key])
+	}
+
+	if body, ok := spec["requestBody"]; ok {
+		if operation.requestBody, err = s.resolve(body); err != nil {
+			return nil, fmt.Errorf("%s: %w", operation, err)
+		}
+	}
+	operation.responses, _ = spec["responses"].(map[string]any)
+	return operation, nil
+}
+
+func (s *OpenAPIServer) handlerFor(operation *openAPIOperation) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		s.validateRequest(operation, req)
+
+		s.lock.Lock()
+		handler, ok := s.responses[operation.id]
+		s.lock.Unlock()
+		if !ok {
+			handler = s.ExampleResponse(operation.id, 0)
+		}
+		handler(w, req)
+	}
+}
+
+// RespondToOperation replaces the response for the operation with the passed-in operationID.  Requests are still validated against the spec before handler is called.
+// Operations without an operationId are identified as "METHOD /path/{template}", e.g. "GET /pets/{petId}".
+func (s *OpenAPIServer) RespondToOperation(operationID string, handler http.HandlerFunc) {
+	_, ok := s.operations[operationID]
+	ExpectWithOffset(1, ok).Should(BeTrue(), "The OpenAPI spec has no operation %q", operationID)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.responses[operationID] = handler
+}
+
+/*
+ExampleResponse returns a handler that responds with the example the spec provides for the passed-in operation and status code.  Pass in 0 to use the operation's
+first 2xx response (or its default response).  If a media type has several named examples, exampleName selects among them - otherwise the first (alphabetically) is used.
+When the spec provides no example one is synthesized from the response's schema.
+*/
+func (s *OpenAPIServer) ExampleResponse(operationID string, statusCode int, exampleName ...string) http.HandlerFunc {
+	operation, ok := s.operations[operationID]
+	ExpectWithOffset(1, ok).Should(BeTrue(), "The OpenAPI spec has no operation %q", operationID)
+	if !ok {
+		return func(w http.ResponseWriter, req *http.Request) {}
+	}
+	status, response, err := s.selectResponse(operation, statusCode)
+	ExpectWithOffset(1, err).ShouldNot(HaveOccurred())
+	contentType, body, err := s.exampleBody(response, exampleName...)
+	ExpectWithOffset(1, err).ShouldNot(HaveOccurred(), "Failed to build example response for %s", operation)
+	return func(w http.ResponseWriter, req *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		w.Write(body)
+	}
+}
+
+func (s *OpenAPIServer) selectResponse(operation *openAPIOperation, statusCode int) (int, map[string]any, error) {
+	if statusCode != 0 {
+		for _, key := range []string{strconv.Itoa(statusCode), strconv.Itoa(statusCode/100) + "XX", "default"} {
+			if node, ok := operation.responses[key]; ok {
+				response, err := s.resolve(node)
+				return statusCode, response, err
+			}
+		}
+		return 0, nil, fmt.Errorf("%s has no %d response", operation, statusCode)
+	}
+	keys := make([]string, 0, len(operation.responses))
+	for key := range operation.responses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if strings.HasPrefix(key, "2") {
+			status, err := strconv.Atoi(strings.ReplaceAll(key, "X", "0"))
+			if err != nil {
+				continue
+			}
+			response, err := s.resolve(operation.responses[key])
+			return status, response, err
+		}
+	}
+	if node, ok := operation.responses["default"]; ok {
+		response, err := s.resolve(node)
+		return http.StatusOK, response, err
+	}
+	return 0, nil, fmt.Errorf("%s has no 2xx or default response", operation)
+}
+
+func (s *OpenAPIServer) exampleBody(response map[string]any, exampleName ...string) (string, []byte, error) {
+	content, _ := response["content"].(map[string]any)
+	if len(content) == 0 {
+		return "", nil, nil
+	}
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Slice(mediaTypes, func(i, j int) bool {
+		ji, jj := isJSONMediaType(mediaTypes[i]), isJSONMediaType(mediaTypes[j])
+		if ji != jj {
+			return ji
+		}
+		return mediaTypes[i] < mediaTypes[j]
+	})
+	mediaType := mediaTypes[0]
+	media, err := s.resolve(content[mediaType])
+	if err != nil {
+		return "", nil, err
+	}
+
+	var example any
+	found := false
+	if value, ok := media["example"]; ok {
+		example, found = value, true
+	} else if examples, ok := media["examples"].(map[string]any); ok && len(examples) > 0 {
+		names := make([]string, 0, len(examples))
+		for name := range examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		name := names[0]
+		if len(exampleName) == 1 {
+			name = exampleName[0]
+		}
+		node, ok := examples[name]
+		if !ok {
+			return "", nil, fmt.Errorf("no example named %q", name)
+		}
+		resolved, err := s.resolve(node)
+		if err != nil {
+			return "", nil, err
+		}
+		example, found = resolved["value"], true
+	}
+	if !found {
+		if example, err = s.synthesizeExample(media["schema"], 0); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if text, ok := example.(string); ok && !isJSONMediaType(mediaType) {
+		return mediaType, []byte(text), nil
+	}
+	body, err := json.Marshal(example)
+	return mediaType, body, err
+}
+
+// synthesizeExample builds a minimal value that conforms to simple schemas - it prefers the schema's example, default, const, and enum values
+func (s *OpenAPIServer) synthesizeExample(node any, depth int) (any, error) {
+	if node == nil || depth > maxOpenAPIRefDepth {
+		return nil, nil
+	}
+	schema, err := s.resolve(node)
+	if err != nil {
+		return nil, err
+	}
+	for _, keyword := range []string{"example", "default", "const"} {
+		if value, ok := schema[keyword]; ok {
+			return value, nil
+		}
+	}
+	if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0], nil
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0], nil
+	}
+	for _, keyword := range []string{"allOf", "oneOf", "anyOf"} {
+		if subschemas, ok := schema[keyword].([]any); ok && len(subschemas) > 0 {
+			if keyword != "allOf" {
+				return s.synthesizeExample(subschemas[0], depth+1)
+			}
+			merged := map[string]any{}
+			for _, subschema := range subschemas {
+				value, err := s.synthesizeExample(subschema, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				if object, ok := value.(map[string]any); ok {
+					for k, v := range object {
+						merged[k] = v
+					}
+				} else if len(subschemas) == 1 {
+					return value, nil
+				}
+			}
+			return merged, nil
+		}
+	}
+	switch openAPISchemaType(schema) {
+	case "object":
+		object := map[string]any{}
+		properties, _ := schema["properties"].(map[string]any)
+		for name, property := range properties {
+			value, err := s.synthesizeExample(property, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			object[name] = value
+		}
+		return object, nil
+	case "array":
+		item, err := s.synthesizeExample(schema["items"], depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return []any{item}, nil
+	case "string":
+		return "string", nil
+	case "integer", "number":
+		return 0, nil
+	case "boolean":
+		return false, nil
+	default:
+		return nil, nil
+	}
+}
+
+func openAPISchemaType(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, candidate := range t {
+			if name, ok := candidate.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func isJSONMediaType(mediaType string) bool {
+	mediaType, _, _ = mime.ParseMediaType(mediaType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// schemaDocument wraps schema so that its $refs to #/components/... resolve against the spec
+func (s *OpenAPIServer) schemaDocument(schema any) map[string]any {
+	document := map[string]any{"allOf": []any{schema}}
+	if components, ok := s.spec["components"]; ok {
+		document["components"] = components
+	}
+	return document
+}
+
+// parameterDelimiter returns the delimiter that separates the items of an array parameter, or "" if each item is sent as a value of its own.
+// It follows the parameter's style and explode settings, which default to form style (exploded) for query and cookie parameters and simple
+// style (not exploded) for path and header parameters.
+func parameterDelimiter(parameter map[string]any, in string) string {
+	style, _ := parameter["style"].(string)
+	if style == "" {
+		style = "form"
+		if in == "path" || in == "header" {
+			style = "simple"
+		}
+	}
+	explode, ok := parameter["explode"].(bool)
+	if !ok {
+		explode = style == "form"
+	}
+
+	switch {
+	case explode && (style == "form" || style == "spaceDelimited" || style == "pipeDelimited"):
+		return ""
+	case style == "spaceDelimited":
+		return " "
+	case style == "pipeDelimited":
+		return "|"
+	default:
+		return ","
+	}
+}
+
+// coerceParameter converts the raw string values of a parameter to the JSON value its schema describes
+func (s *OpenAPIServer) coerceParameter(schema map[string]any, values []string, delimiter string) any {
+	if openAPISchemaType(schema) == "array" {
+		if delimiter != "" {
+			var items []string
+			for _, value := range values {
+				items = append(items, strings.Split(value, delimiter)...)
+			}
+			values = items
+		}
+		items, _ := s.resolve(schema["items"])
+		coerced := make([]any, len(values))
+		for i, value := range values {
+			coerced[i] = coerceScalar(items, value)
+		}
+		return coerced
+	}
+	return coerceScalar(schema, values[0])
+}
+
+func coerceScalar(schema map[string]any, value string) any {
+	if schema == nil {
+		return value
+	}
+	switch openAPISchemaType(schema) {
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+func (s *OpenAPIServer) validateRequest(operation *openAPIOperation, req *http.Request) {
+	description := fmt.Sprintf("OpenAPI operation %s", operation)
+
+	pathValues := map[string]string{}
+	if match := operation.pathRegexp.FindStringSubmatch(req.URL.Path); match != nil {
+		for i, name := range operation.pathParameters {
+			value, err := url.PathUnescape(match[i+1])
+			if err != nil {
+				value = match[i+1]
+			}
+			pathValues[name] = value
+		}
+	}
+
+	for _, parameter := range operation.parameters {
+		name, _ := parameter["name"].(string)
+		in, _ := parameter["in"].(string)
+		required, _ := parameter["required"].(bool)
+
+		var values []string
+		switch in {
+		case "path":
+			required = true
+			if value, ok := pathValues[name]; ok {
+				values = []string{value}
+			}
+		case "query":
+			values = req.URL.Query()[name]
+		case "header":
+			values = req.Header.Values(name)
+		case "cookie":
+			if cookie, err := req.Cookie(name); err == nil {
+				values = []string{cookie.Value}
+			}
+		default:
+			continue
+		}
+
+		if len(values) == 0 {
+			Expect(required).Should(BeFalse(), "Missing required %s parameter %q for %s", in, name, description)
+			continue
+		}
+		schema, err := s.resolve(parameter["schema"])
+		if err != nil || schema == nil {
+			continue
+		}
+		value, err := json.Marshal(s.coerceParameter(schema, values, parameterDelimiter(parameter, in)))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(value).Should(MatchJSONSchema(s.schemaDocument(schema)), "Invalid %s parameter %q for %s", in, name, description)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	Expect(err).ShouldNot(HaveOccurred())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	if operation.requestBody == nil {
+		return
+	}
+	if len(body) == 0 {
+		required, _ := operation.requestBody["required"].(bool)
+		Expect(required).Should(BeFalse(), "Missing required request body for %s", description)
+		return
+	}
+
+	content, _ := operation.requestBody["content"].(map[string]any)
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	media, mediaType := s.mediaTypeFor(content, contentType)
+	supported := make([]string, 0, len(content))
+	for mediaType := range content {
+		supported = append(supported, mediaType)
+	}
+	sort.Strings(supported)
+	Expect(media).ShouldNot(BeNil(), "Unsupported Content-Type %q for %s.  Expected one of: %s", req.Header.Get("Content-Type"), description, strings.Join(supported, ", "))
+	if media == nil || !isJSONMediaType(mediaType) {
+		return
+	}
+	if schema, ok := media["schema"]; ok {
+		Expect(body).Should(MatchJSONSchema(s.schemaDocument(schema)), "Invalid request body for %s", description)
+	}
+}
+
+// mediaTypeFor finds the media type object for contentType, honoring wildcards like application/* and */*
+func (s *OpenAPIServer) mediaTypeFor(content map[string]any, contentType string) (map[string]any, string) {
+	candidates := []string{contentType}
+	if i := strings.Index(contentType, "/"); i > 0 {
+		candidates = append(candidates, contentType[:i]+"/*")
+	}
+	candidates = append(candidates, "*/*")
+	for _, candidate := range candidates {
+		for mediaType, node := range content {
+			parsed, _, err := mime.ParseMediaType(mediaType)
+			if err != nil || parsed != candidate {
+				continue
+			}
+			media, err := s.resolve(node)
+			if err != nil {
+				return nil, ""
+			}
+			if candidate != contentType {
+				mediaType = contentType
+			}
+			return media, mediaType
+		}
+	}
+	return nil, ""
+}
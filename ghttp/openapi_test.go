@@ -0,0 +1,324 @@
+package ghttp_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+const petStoreSpec = `openapi: 3.0.3
+info:
+  title: Pet Store
+  version: 1.0.0
+servers:
+  - url: https://pets.example.com/{version}
+    variables:
+      version:
+        default: v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - $ref: "#/components/parameters/limit"
+        - name: X-Request-Id
+          in: header
+          required: true
+          schema:
+            type: string
+            pattern: "^[a-z0-9-]+$"
+      responses:
+        200:
+          description: the pets
+          content:
+            application/json:
+              example:
+                - id: 1
+                  name: Fido
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/NewPet"
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+        "400":
+          description: invalid
+          content:
+            application/json:
+              examples:
+                missingName:
+                  value: {error: name is required}
+                tooLong:
+                  value: {error: name is too long}
+  /pets/mine:
+    get:
+      operationId: listMyPets
+      responses:
+        "200":
+          description: my pets
+          content:
+            text/plain:
+              example: none
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: integer
+          minimum: 1
+    delete:
+      responses:
+        "204":
+          description: deleted
+  /items/{ids}:
+    get:
+      operationId: getItems
+      parameters:
+        - name: ids
+          in: path
+          required: true
+          schema:
+            type: array
+            items:
+              type: integer
+        - name: X-Tags
+          in: header
+          schema:
+            type: array
+            items:
+              type: integer
+        - name: size
+          in: query
+          schema:
+            type: array
+            items:
+              type: integer
+        - name: color
+          in: query
+          explode: false
+          schema:
+            type: array
+            items:
+              type: string
+              enum: [red, blue]
+      responses:
+        "204":
+          description: found
+components:
+  parameters:
+    limit:
+      name: limit
+      in: query
+      schema:
+        type: integer
+        maximum: 100
+        exclusiveMaximum: true
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        tag:
+          type: string
+          nullable: true
+    Pet:
+      allOf:
+        - $ref: "#/components/schemas/NewPet"
+        - type: object
+          properties:
+            id:
+              type: integer
+              example: 7
+`
+
+var _ = Describe("OpenAPI servers", func() {
+	var s *OpenAPIServer
+	var specPath string
+
+	BeforeEach(func() {
+		specPath = filepath.Join(GinkgoT().TempDir(), "petstore.yaml")
+		Expect(os.WriteFile(specPath, []byte(petStoreSpec), 0644)).To(Succeed())
+		s = NewServerFromOpenAPI(specPath)
+		DeferCleanup(s.Close)
+	})
+
+	do := func(method string, path string, body string, header ...string) *http.Response {
+		req, err := http.NewRequest(method, s.URL()+path, strings.NewReader(body))
+		if err != nil {
+			panic(err)
+		}
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			panic(err)
+		}
+		DeferCleanup(resp.Body.Close)
+		return resp
+	}
+
+	It("routes operations under the server's base path and responds with examples", func() {
+		resp := do("GET", "/v1/pets?limit=10", "", "X-Request-Id", "abc-123")
+		Expect(resp).To(HaveHTTPStatus(http.StatusOK))
+		Expect(resp).To(HaveHTTPHeaderWithValue("Content-Type", "application/json"))
+		Expect(resp).To(HaveHTTPBody(MatchJSON(`[{"id": 1, "name": "Fido"}]`)))
+
+		Expect(do("GET", "/v1/pets/mine", "")).To(HaveHTTPBody("none"))
+		Expect(do("DELETE", "/v1/pets/3", "")).To(HaveHTTPStatus(http.StatusNoContent))
+
+		Expect(s).To(HaveReceivedRequestsInOrder(RequestTo("GET", "/v1/pets"), RequestTo("GET", "/v1/pets/mine"), RequestTo("DELETE", "/v1/pets/3")))
+		Expect(s.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("synthesizes examples from the response schema", func() {
+		resp := do("POST", "/v1/pets", `{"name": "Rex", "tag": null}`, "Content-Type", "application/json")
+		Expect(resp).To(HaveHTTPStatus(http.StatusCreated))
+		Expect(resp).To(HaveHTTPBody(MatchJSON(`{"id": 7, "name": "string", "tag": "string"}`)))
+	})
+
+	It("can respond with a different response", func() {
+		s.RespondToOperation("createPet", s.ExampleResponse("createPet", http.StatusBadRequest, "tooLong"))
+		Expect(do("POST", "/v1/pets", `{"name": "Rex"}`, "Content-Type", "application/json")).To(HaveHTTPBody(MatchJSON(`{"error": "name is too long"}`)))
+		s.RespondToOperation("createPet", s.ExampleResponse("createPet", http.StatusBadRequest))
+		Expect(do("POST", "/v1/pets", `{"name": "Rex"}`, "Content-Type", "application/json")).To(HaveHTTPBody(MatchJSON(`{"error": "name is required"}`)))
+		s.RespondToOperation("DELETE /pets/{petId}", RespondWith(http.StatusGone, "gone"))
+		Expect(do("DELETE", "/v1/pets/3", "")).To(HaveHTTPStatus(http.StatusGone))
+	})
+
+	It("fails on unknown operations", func() {
+		failures := InterceptGomegaFailures(func() {
+			s.RespondToOperation("feedPet", RespondWith(http.StatusOK, ""))
+		})
+		Expect(failures).To(ConsistOf(ContainSubstring(`The OpenAPI spec has no operation "feedPet"`)))
+	})
+
+	Describe("validating requests", func() {
+		It("validates path parameters", func() {
+			failures := InterceptGomegaFailures(func() {
+				do("DELETE", "/v1/pets/0", "")
+			})
+			Expect(failures).To(ConsistOf(And(
+				ContainSubstring(`Invalid path parameter "petId" for OpenAPI operation DELETE /pets/{petId} (DELETE /pets/{petId})`),
+				ContainSubstring("minimum"),
+			)))
+		})
+
+		It("validates query parameters", func() {
+			failures := InterceptGomegaFailures(func() {
+				do("GET", "/v1/pets?limit=100", "", "X-Request-Id", "abc")
+			})
+			Expect(failures).To(ConsistOf(And(
+				ContainSubstring(`Invalid query parameter "limit" for OpenAPI operation listPets (GET /pets)`),
+				ContainSubstring("exclusiveMaximum"),
+			)))
+		})
+
+		It("validates headers", func() {
+			failures := InterceptGomegaFailures(func() {
+				do("GET", "/v1/pets", "")
+				do("GET", "/v1/pets", "", "X-Request-Id", "ABC")
+			})
+			Expect(failures).To(ConsistOf(
+				ContainSubstring(`Missing required header parameter "X-Request-Id" for OpenAPI operation listPets (GET /pets)`),
+				And(ContainSubstring(`Invalid header parameter "X-Request-Id"`), ContainSubstring("pattern")),
+			))
+		})
+
+		It("splits array parameters the way their style serializes them", func() {
+			Expect(do("GET", "/v1/items/1,2,3?size=6&size=7&color=red,blue", "", "X-Tags", "4,5")).To(HaveHTTPStatus(http.StatusNoContent))
+			Expect(do("GET", "/v1/items/1", "", "X-Tags", "4")).To(HaveHTTPStatus(http.StatusNoContent))
+
+			failures := InterceptGomegaFailures(func() {
+				do("GET", "/v1/items/1,x", "")
+				do("GET", "/v1/items/1", "", "X-Tags", "4,y")
+				do("GET", "/v1/items/1?size=6,7", "")
+				do("GET", "/v1/items/1?color=red,green", "")
+			})
+			Expect(failures).To(ConsistOf(
+				ContainSubstring(`Invalid path parameter "ids" for OpenAPI operation getItems (GET /items/{ids})`),
+				ContainSubstring(`Invalid header parameter "X-Tags" for OpenAPI operation getItems (GET /items/{ids})`),
+				ContainSubstring(`Invalid query parameter "size" for OpenAPI operation getItems (GET /items/{ids})`),
+				ContainSubstring(`Invalid query parameter "color" for OpenAPI operation getItems (GET /items/{ids})`),
+			))
+		})
+
+		It("validates request bodies", func() {
+			failures := InterceptGomegaFailures(func() {
+				do("POST", "/v1/pets", "", "Content-Type", "application/json")
+				do("POST", "/v1/pets", `{"tag": "good"}`, "Content-Type", "application/json")
+				do("POST", "/v1/pets", `name=Rex`, "Content-Type", "application/x-www-form-urlencoded")
+			})
+			Expect(failures).To(ConsistOf(
+				ContainSubstring(`Missing required request body for OpenAPI operation createPet (POST /pets)`),
+				And(ContainSubstring(`Invalid request body for OpenAPI operation createPet (POST /pets)`), ContainSubstring("required")),
+				ContainSubstring(`Unsupported Content-Type "application/x-www-form-urlencoded" for OpenAPI operation createPet (POST /pets).  Expected one of: application/json`),
+			))
+		})
+
+		It("treats requests for paths that are not in the spec as unhandled", func() {
+			failures := InterceptGomegaFailures(func() {
+				do("GET", "/v1/owners", "")
+			})
+			Expect(failures).To(ConsistOf(ContainSubstring("Received Unhandled Request")))
+		})
+	})
+
+	Describe("loading specs", func() {
+		It("supports JSON OpenAPI 3.1 specs", func() {
+			jsonSpecPath := filepath.Join(GinkgoT().TempDir(), "spec.json")
+			Expect(os.WriteFile(jsonSpecPath, []byte(`{
+				"openapi": "3.1.0",
+				"info": {"title": "t", "version": "1"},
+				"paths": {"/things/{id}": {"get": {
+					"operationId": "getThing",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string", "format": "uuid", "maxLength": 3}}],
+					"responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"type": ["object", "null"], "properties": {"id": {"const": "abc"}}}}}}}
+				}}}
+			}`), 0644)).To(Succeed())
+			server := NewServerFromOpenAPI(jsonSpecPath)
+			DeferCleanup(server.Close)
+
+			resp, err := http.Get(server.URL() + "/things/abc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(HaveHTTPBody(MatchJSON(`{"id": "abc"}`)))
+			failures := InterceptGomegaFailures(func() {
+				http.Get(server.URL() + "/things/abcd")
+			})
+			Expect(failures).To(ConsistOf(ContainSubstring(`Invalid path parameter "id" for OpenAPI operation getThing`)))
+		})
+
+		It("fails when the spec can't be loaded", func() {
+			failures := InterceptGomegaFailures(func() {
+				server := NewServerFromOpenAPI(filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+				server.Close()
+			})
+			Expect(failures).To(ConsistOf(ContainSubstring("Failed to load OpenAPI spec")))
+
+			notOpenAPI := filepath.Join(GinkgoT().TempDir(), "swagger.yaml")
+			Expect(os.WriteFile(notOpenAPI, []byte("swagger: '2.0'\n"), 0644)).To(Succeed())
+			failures = InterceptGomegaFailures(func() {
+				server := NewServerFromOpenAPI(notOpenAPI)
+				server.Close()
+			})
+			Expect(failures).To(ConsistOf(ContainSubstring("is not an OpenAPI 3 document")))
+		})
+	})
+})
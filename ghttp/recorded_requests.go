@@ -0,0 +1,326 @@
+package ghttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// RecordedRequest is a snapshot of a request received by a ghttp.Server
+type RecordedRequest struct {
+	Method string
+	URL    *url.URL
+	Host   string
+	Header http.Header
+	// Body holds the complete request body, regardless of whether or not the handler read it.  It is filled in once the handler returns.
+	Body       []byte
+	ReceivedAt time.Time
+	// Handler describes the handler that served the request.  It is one of:
+	//   "RouteToHandler(METHOD, PATH)" for routed requests,
+	//   "AppendHandlers[N]" for requests served by the Nth (zero-indexed) appended handler,
+	//   "cassette (recording)" or "cassette (replaying)" for requests served by a CassetteServer's cassette,
+	//   "unhandled" for requests that no handler was registered for
+	Handler string
+}
+
+func newRecordedRequest(req *http.Request, receivedAt time.Time) *RecordedRequest {
+	u := *req.URL
+	return &RecordedRequest{
+		Method:     req.Method,
+		URL:        &u,
+		Host:       req.Host,
+		Header:     req.Header.Clone(),
+		ReceivedAt: receivedAt,
+	}
+}
+
+// String returns a one-line summary of the request, e.g. "POST /sprockets?color=red (AppendHandlers[0])"
+func (r RecordedRequest) String() string {
+	return fmt.Sprintf("%s %s (%s)", r.Method, r.URL.RequestURI(), r.Handler)
+}
+
+// recordingBody wraps a request's body and records it as the handler reads it
+type recordingBody struct {
+	body     io.ReadCloser
+	lock     sync.Mutex
+	recorded bytes.Buffer
+	drained  bool
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	n, err := b.body.Read(p)
+	b.recorded.Write(p[:n])
+	return n, err
+}
+
+// Close reads the rest of the body before closing it, as the server can't read it for the snapshot once it's closed
+func (b *recordingBody) Close() error {
+	b.drain()
+	return b.body.Close()
+}
+
+// drain reads whatever is left of the body and returns the complete body
+func (b *recordingBody) drain() []byte {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if !b.drained {
+		b.drained = true
+		io.Copy(&b.recorded, b.body)
+	}
+	return bytes.Clone(b.recorded.Bytes())
+}
+
+// RequestTo returns a matcher that succeeds if a RecordedRequest used the specified method to connect to the specified path.
+// It mirrors VerifyRequest: path may be a string or a matcher and the optional rawQuery is compared against the request's parsed query.
+func RequestTo(method string, path any, rawQuery ...string) types.GomegaMatcher {
+	pathMatcher, ok := path.(types.GomegaMatcher)
+	if !ok {
+		pathMatcher = Equal(path)
+	}
+	matchers := []types.GomegaMatcher{
+		HaveField("Method", Equal(method)),
+		WithTransform(func(r RecordedRequest) string { return r.URL.Path }, pathMatcher),
+	}
+	if len(rawQuery) > 0 {
+		values, err := url.ParseQuery(rawQuery[0])
+		if err != nil {
+			panic(fmt.Sprintf("RequestTo: expected RawQuery is malformed: %s", err.Error()))
+		}
+		matchers = append(matchers, WithTransform(func(r RecordedRequest) url.Values { return r.URL.Query() }, Equal(values)))
+	}
+	return And(matchers...)
+}
+
+// RequestWithHeader returns a matcher that succeeds if one of the values of the RecordedRequest's key header satisfies value.
+// value may be a string or a matcher.
+func RequestWithHeader(key string, value any) types.GomegaMatcher {
+	return HaveField("Header", HaveKeyWithValue(http.CanonicalHeaderKey(key), ContainElement(value)))
+}
+
+// RequestWithBody returns a matcher that succeeds if the RecordedRequest's body satisfies expected.
+// It mirrors VerifyBody and HaveHTTPBody: expected may be a string, a []byte, or a matcher that is applied to the body as a string.
+func RequestWithBody(expected any) types.GomegaMatcher {
+	switch e := expected.(type) {
+	case string:
+		return WithTransform(func(r RecordedRequest) string { return string(r.Body) }, Equal(e))
+	case []byte:
+		return WithTransform(func(r RecordedRequest) []byte { return r.Body }, Equal(e))
+	case types.GomegaMatcher:
+		return WithTransform(func(r RecordedRequest) string { return string(r.Body) }, e)
+	default:
+		panic(fmt.Sprintf("RequestWithBody expects string, []byte, or GomegaMatcher.  Got:\n%s", format.Object(expected, 1)))
+	}
+}
+
+// RequestWithJSON returns a matcher that succeeds if the RecordedRequest's body is JSON equivalent to expected.
+func RequestWithJSON(expected string) types.GomegaMatcher {
+	return RequestWithBody(MatchJSON(expected))
+}
+
+// RequestWithJSONRepresenting is like RequestWithJSON but takes an object that is JSON-encoded and compared with the request's body.
+func RequestWithJSONRepresenting(object any) types.GomegaMatcher {
+	data, err := json.Marshal(object)
+	if err != nil {
+		panic(fmt.Sprintf("RequestWithJSONRepresenting: failed to marshal object: %s", err.Error()))
+	}
+	return RequestWithJSON(string(data))
+}
+
+/*
+HaveReceivedRequest succeeds if the server has received at least one request that satisfies every one of the passed-in matchers.
+Each matcher is handed a RecordedRequest, so you can use RequestTo, RequestWithHeader, RequestWithBody, and friends - or any matcher
+that operates on a RecordedRequest (e.g. HaveField("Handler", "unhandled")).
+
+ACTUAL must be a *ghttp.Server (or a *ghttp.CassetteServer) or a []RecordedRequest.  Since the set of recorded requests grows as the server receives requests,
+HaveReceivedRequest works with Eventually:
+
+	Eventually(server).Should(ghttp.HaveReceivedRequest(
+		ghttp.RequestTo("POST", "/sprockets"),
+		ghttp.RequestWithJSON(`{"color":"red"}`),
+	))
+*/
+func HaveReceivedRequest(matchers ...types.GomegaMatcher) types.GomegaMatcher {
+	return &haveReceivedRequestMatcher{matchers: matchers}
+}
+
+/*
+HaveReceivedRequestsInOrder succeeds if the server has received a sequence of requests that satisfy each of the passed-in criteria in order.
+Other requests are allowed to come before, between, and after the matching requests.  Each criterion is a matcher that is handed a RecordedRequest -
+use And to combine several matchers into one criterion:
+
+	Eventually(server).Should(ghttp.HaveReceivedRequestsInOrder(
+		ghttp.RequestTo("POST", "/login"),
+		And(ghttp.RequestTo("GET", "/sprockets"), ghttp.RequestWithHeader("Authorization", "Bearer token")),
+	))
+
+ACTUAL must be a *ghttp.Server (or a *ghttp.CassetteServer) or a []RecordedRequest.
+*/
+func HaveReceivedRequestsInOrder(criteria ...types.GomegaMatcher) types.GomegaMatcher {
+	return &haveReceivedRequestsInOrderMatcher{criteria: criteria}
+}
+
+// requestRecorder is satisfied by *Server and by types that embed it, like *CassetteServer
+type requestRecorder interface {
+	RecordedRequests() []RecordedRequest
+}
+
+func recordedRequestsFor(matcherName string, actual any) ([]RecordedRequest, error) {
+	switch a := actual.(type) {
+	case requestRecorder:
+		return a.RecordedRequests(), nil
+	case []RecordedRequest:
+		return a, nil
+	default:
+		return nil, fmt.Errorf("%s matcher expects a *ghttp.Server or []ghttp.RecordedRequest.  Got:\n%s", matcherName, format.Object(actual, 1))
+	}
+}
+
+// matchRecordedRequest returns "" if request satisfies every matcher, and the failure message of the first matcher it fails otherwise
+func matchRecordedRequest(request RecordedRequest, matchers []types.GomegaMatcher) (string, error) {
+	for _, matcher := range matchers {
+		success, err := matcher.Match(request)
+		if err != nil {
+			return "", err
+		}
+		if !success {
+			return matcher.FailureMessage(request), nil
+		}
+	}
+	return "", nil
+}
+
+func summarizeRecordedRequests(requests []RecordedRequest) string {
+	if len(requests) == 0 {
+		return "The server has not received any requests."
+	}
+	lines := make([]string, len(requests))
+	for i, request := range requests {
+		lines[i] = fmt.Sprintf("#%d %s", i+1, request)
+	}
+	return fmt.Sprintf("The server received %d requests:\n%s", len(requests), format.IndentString(strings.Join(lines, "\n"), 1))
+}
+
+type haveReceivedRequestMatcher struct {
+	matchers []types.GomegaMatcher
+
+	requests   []RecordedRequest
+	mismatches []string
+	matched    int
+}
+
+func (m *haveReceivedRequestMatcher) Match(actual any) (bool, error) {
+	requests, err := recordedRequestsFor("HaveReceivedRequest", actual)
+	if err != nil {
+		return false, err
+	}
+	m.requests, m.mismatches, m.matched = requests, make([]string, len(requests)), -1
+	for i, request := range requests {
+		mismatch, err := matchRecordedRequest(request, m.matchers)
+		if err != nil {
+			return false, fmt.Errorf("HaveReceivedRequest matcher failed on request #%d (%s):\n%s", i+1, request, format.IndentString(err.Error(), 1))
+		}
+		if mismatch == "" {
+			m.matched = i
+			return true, nil
+		}
+		m.mismatches[i] = mismatch
+	}
+	return false, nil
+}
+
+func (m *haveReceivedRequestMatcher) FailureMessage(actual any) string {
+	if len(m.requests) == 0 {
+		return "Expected the server to have received a matching request.  " + summarizeRecordedRequests(m.requests)
+	}
+	var message strings.Builder
+	fmt.Fprintf(&message, "Expected the server to have received a matching request.  None of the %d requests it received matched:", len(m.requests))
+	for i, request := range m.requests {
+		fmt.Fprintf(&message, "\n#%d %s\n%s", i+1, request, format.IndentString(m.mismatches[i], 1))
+	}
+	return message.String()
+}
+
+func (m *haveReceivedRequestMatcher) NegatedFailureMessage(actual any) string {
+	return fmt.Sprintf("Expected the server not to have received a matching request.  Request #%d matched: %s", m.matched+1, m.requests[m.matched])
+}
+
+func (m *haveReceivedRequestMatcher) MatchMayChangeInTheFuture(actual any) bool {
+	_, isServer := actual.(requestRecorder)
+	return isServer
+}
+
+type haveReceivedRequestsInOrderMatcher struct {
+	criteria []types.GomegaMatcher
+
+	requests []RecordedRequest
+	// matchedAt[i] is the index of the request that satisfied criteria[i]
+	matchedAt []int
+}
+
+func (m *haveReceivedRequestsInOrderMatcher) Match(actual any) (bool, error) {
+	requests, err := recordedRequestsFor("HaveReceivedRequestsInOrder", actual)
+	if err != nil {
+		return false, err
+	}
+	m.requests, m.matchedAt = requests, nil
+	next := 0
+	for _, criterion := range m.criteria {
+		for next < len(requests) {
+			request := requests[next]
+			next++
+			success, err := criterion.Match(request)
+			if err != nil {
+				return false, fmt.Errorf("HaveReceivedRequestsInOrder matcher failed on request #%d (%s):\n%s", next, request, format.IndentString(err.Error(), 1))
+			}
+			if success {
+				m.matchedAt = append(m.matchedAt, next-1)
+				break
+			}
+		}
+	}
+	return len(m.matchedAt) == len(m.criteria), nil
+}
+
+func (m *haveReceivedRequestsInOrderMatcher) FailureMessage(actual any) string {
+	var message strings.Builder
+	fmt.Fprintf(&message, "Expected the server to have received requests satisfying %d criteria in order.", len(m.criteria))
+	for i := range m.criteria {
+		switch {
+		case i < len(m.matchedAt):
+			fmt.Fprintf(&message, "\n✓ criterion #%d was satisfied by request #%d", i+1, m.matchedAt[i]+1)
+		case i == len(m.matchedAt) && i > 0:
+			fmt.Fprintf(&message, "\n✗ criterion #%d was not satisfied by any request after #%d", i+1, m.matchedAt[i-1]+1)
+		case i == len(m.matchedAt):
+			fmt.Fprintf(&message, "\n✗ criterion #%d was not satisfied by any request", i+1)
+		default:
+			fmt.Fprintf(&message, "\n✗ criterion #%d was not checked", i+1)
+		}
+	}
+	message.WriteString("\n" + summarizeRecordedRequests(m.requests))
+	return message.String()
+}
+
+func (m *haveReceivedRequestsInOrderMatcher) NegatedFailureMessage(actual any) string {
+	matched := make([]string, len(m.matchedAt))
+	for i, index := range m.matchedAt {
+		matched[i] = fmt.Sprintf("#%d %s", index+1, m.requests[index])
+	}
+	return fmt.Sprintf("Expected the server not to have received requests satisfying the criteria in order.  These requests did:\n%s", format.IndentString(strings.Join(matched, "\n"), 1))
+}
+
+func (m *haveReceivedRequestsInOrderMatcher) MatchMayChangeInTheFuture(actual any) bool {
+	_, isServer := actual.(requestRecorder)
+	return isServer
+}
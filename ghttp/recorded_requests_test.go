@@ -0,0 +1,229 @@
+package ghttp_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Recorded requests", func() {
+	var s *Server
+
+	BeforeEach(func() {
+		s = NewServer()
+		s.SetAllowUnhandledRequests(true)
+	})
+
+	AfterEach(func() {
+		s.Close()
+	})
+
+	post := func(path string, body string) {
+		req, err := http.NewRequest("POST", s.URL()+path, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Request-Id", "abc-123")
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+	}
+
+	get := func(path string) {
+		resp, err := http.Get(s.URL() + path)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+	}
+
+	Describe("RecordedRequests", func() {
+		It("records a snapshot of every request, along with the handler that served it", func() {
+			var bodySeenByHandler string
+			s.RouteToHandler("GET", "/routed", func(w http.ResponseWriter, req *http.Request) {})
+			s.AppendHandlers(func(w http.ResponseWriter, req *http.Request) {
+				data, _ := io.ReadAll(req.Body)
+				bodySeenByHandler = string(data)
+			})
+
+			before := time.Now()
+			post("/sprockets?color=red", `{"size":3}`)
+			get("/routed")
+			get("/nothing-here")
+
+			recorded := s.RecordedRequests()
+			Expect(recorded).To(HaveLen(3))
+			Expect(bodySeenByHandler).To(Equal(`{"size":3}`))
+
+			Expect(recorded[0].Method).To(Equal("POST"))
+			Expect(recorded[0].URL.Path).To(Equal("/sprockets"))
+			Expect(recorded[0].URL.RawQuery).To(Equal("color=red"))
+			Expect(recorded[0].Host).To(Equal(s.Addr()))
+			Expect(recorded[0].Header.Get("X-Request-Id")).To(Equal("abc-123"))
+			Expect(recorded[0].Body).To(Equal([]byte(`{"size":3}`)))
+			Expect(recorded[0].ReceivedAt).To(BeTemporally(">=", before))
+			Expect(recorded[0].Handler).To(Equal("AppendHandlers[0]"))
+			Expect(recorded[0].String()).To(Equal("POST /sprockets?color=red (AppendHandlers[0])"))
+
+			Expect(recorded[1].Handler).To(Equal("RouteToHandler(GET, /routed)"))
+			Expect(recorded[2].Handler).To(Equal("unhandled"))
+		})
+
+		It("records the body as the handler reads it, and the rest once the handler returns", func() {
+			firstChunk := make(chan string, 1)
+			s.AppendHandlers(func(w http.ResponseWriter, req *http.Request) {
+				data := make([]byte, 5)
+				_, err := io.ReadFull(req.Body, data)
+				Expect(err).NotTo(HaveOccurred())
+				firstChunk <- string(data)
+			})
+
+			bodyReader, bodyWriter := io.Pipe()
+			done := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+				resp, err := http.Post(s.URL()+"/stream", "text/plain", bodyReader)
+				Expect(err).NotTo(HaveOccurred())
+				resp.Body.Close()
+			}()
+
+			bodyWriter.Write([]byte("hello"))
+			Eventually(firstChunk).Should(Receive(Equal("hello")))
+			bodyWriter.Write([]byte(" world"))
+			bodyWriter.Close()
+			Eventually(done).Should(BeClosed())
+
+			Expect(s.RecordedRequests()[0].Body).To(Equal([]byte("hello world")))
+		})
+
+		It("is cleared by Reset", func() {
+			get("/foo")
+			Expect(s.RecordedRequests()).To(HaveLen(1))
+			s.Reset()
+			Expect(s.RecordedRequests()).To(BeEmpty())
+		})
+	})
+
+	Describe("HaveReceivedRequest", func() {
+		BeforeEach(func() {
+			post("/sprockets", `{"color":"red"}`)
+			get("/widgets?page=2")
+		})
+
+		It("succeeds if any recorded request satisfies all the matchers", func() {
+			Expect(s).To(HaveReceivedRequest(RequestTo("POST", "/sprockets")))
+			Expect(s).To(HaveReceivedRequest(RequestTo("GET", "/widgets", "page=2")))
+			Expect(s).To(HaveReceivedRequest(RequestTo("GET", HavePrefix("/wid"))))
+			Expect(s).To(HaveReceivedRequest(
+				RequestTo("POST", "/sprockets"),
+				RequestWithHeader("x-request-id", "abc-123"),
+				RequestWithJSON(`{"color": "red"}`),
+			))
+			Expect(s).To(HaveReceivedRequest(RequestWithJSONRepresenting(map[string]string{"color": "red"})))
+			Expect(s).To(HaveReceivedRequest(RequestWithBody([]byte(`{"color":"red"}`))))
+			Expect(s).To(HaveReceivedRequest(RequestWithBody(ContainSubstring("red"))))
+			Expect(s).To(HaveReceivedRequest(HaveField("Handler", "unhandled")))
+
+			Expect(s).NotTo(HaveReceivedRequest(RequestTo("GET", "/sprockets")))
+			Expect(s).NotTo(HaveReceivedRequest(RequestTo("GET", "/widgets", "page=3")))
+			Expect(s).NotTo(HaveReceivedRequest(RequestTo("POST", "/sprockets"), RequestWithBody("nope")))
+		})
+
+		It("accepts a slice of RecordedRequests", func() {
+			Expect(s.RecordedRequests()).To(HaveReceivedRequest(RequestTo("POST", "/sprockets")))
+		})
+
+		It("works with Eventually", func() {
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				resp, err := http.Get(s.URL() + "/later")
+				if err == nil {
+					resp.Body.Close()
+				}
+			}()
+			Eventually(s).Should(HaveReceivedRequest(RequestTo("GET", "/later")))
+		})
+
+		It("errors when handed something other than a server", func() {
+			success, err := HaveReceivedRequest().Match("foo")
+			Expect(success).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("HaveReceivedRequest matcher expects a *ghttp.Server or []ghttp.RecordedRequest")))
+		})
+
+		It("explains why each request failed to match", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(s).To(HaveReceivedRequest(RequestTo("GET", "/sprockets")))
+			})
+			Expect(failures).To(HaveLen(1))
+			Expect(failures[0]).To(HavePrefix("Expected the server to have received a matching request.  None of the 2 requests it received matched:\n#1 POST /sprockets (unhandled)\n"))
+			Expect(failures[0]).To(ContainSubstring("<string>: POST"))
+			Expect(failures[0]).To(ContainSubstring("#2 GET /widgets?page=2 (unhandled)\n"))
+			Expect(failures[0]).To(ContainSubstring("<string>: /widgets"))
+
+			failures = InterceptGomegaFailures(func() {
+				Expect([]RecordedRequest{}).To(HaveReceivedRequest(RequestTo("GET", "/sprockets")))
+			})
+			Expect(failures).To(ConsistOf("Expected the server to have received a matching request.  The server has not received any requests."))
+		})
+
+		It("reports the matching request when negated", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(s).NotTo(HaveReceivedRequest(RequestTo("GET", "/widgets")))
+			})
+			Expect(failures).To(ConsistOf("Expected the server not to have received a matching request.  Request #2 matched: GET /widgets?page=2 (unhandled)"))
+		})
+	})
+
+	Describe("HaveReceivedRequestsInOrder", func() {
+		BeforeEach(func() {
+			post("/login", "")
+			get("/a")
+			get("/b")
+			get("/c")
+		})
+
+		It("succeeds if the criteria are satisfied by an ordered subsequence of the requests", func() {
+			Expect(s).To(HaveReceivedRequestsInOrder(RequestTo("POST", "/login"), RequestTo("GET", "/b")))
+			Expect(s).To(HaveReceivedRequestsInOrder(RequestTo("GET", "/a"), RequestTo("GET", "/b"), RequestTo("GET", "/c")))
+			Expect(s).To(HaveReceivedRequestsInOrder())
+			Expect(s).NotTo(HaveReceivedRequestsInOrder(RequestTo("GET", "/b"), RequestTo("GET", "/a")))
+			Expect(s).NotTo(HaveReceivedRequestsInOrder(RequestTo("GET", "/a"), RequestTo("GET", "/a")))
+		})
+
+		It("works with Eventually", func() {
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				resp, err := http.Get(s.URL() + "/d")
+				if err == nil {
+					resp.Body.Close()
+				}
+			}()
+			Eventually(s).Should(HaveReceivedRequestsInOrder(RequestTo("GET", "/c"), RequestTo("GET", "/d")))
+		})
+
+		It("reports which criteria were satisfied", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(s).To(HaveReceivedRequestsInOrder(RequestTo("GET", "/b"), RequestTo("GET", "/a"), RequestTo("GET", "/c")))
+			})
+			Expect(failures).To(ConsistOf(`Expected the server to have received requests satisfying 3 criteria in order.
+✓ criterion #1 was satisfied by request #3
+✗ criterion #2 was not satisfied by any request after #3
+✗ criterion #3 was not checked
+The server received 4 requests:
+    #1 POST /login (unhandled)
+    #2 GET /a (unhandled)
+    #3 GET /b (unhandled)
+    #4 GET /c (unhandled)`))
+		})
+
+		It("reports the matching requests when negated", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(s).NotTo(HaveReceivedRequestsInOrder(RequestTo("GET", "/a"), RequestTo("GET", "/c")))
+			})
+			Expect(failures).To(ConsistOf("Expected the server not to have received requests satisfying the criteria in order.  These requests did:\n    #2 GET /a (unhandled)\n    #4 GET /c (unhandled)"))
+		})
+	})
+})
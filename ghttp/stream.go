@@ -0,0 +1,280 @@
+package ghttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+)
+
+// SSEEvent is a Server-Sent Event.  Data may span several lines - each line is sent as its own data field.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  string
+	// Retry, if non-zero, tells the client how long to wait before reconnecting
+	Retry time.Duration
+}
+
+// String returns the event in the text/event-stream wire format, including the trailing blank line
+func (e SSEEvent) String() string {
+	var out strings.Builder
+	if e.ID != "" {
+		fmt.Fprintf(&out, "id: %s\n", e.ID)
+	}
+	if e.Event != "" {
+		fmt.Fprintf(&out, "event: %s\n", e.Event)
+	}
+	if e.Retry > 0 {
+		fmt.Fprintf(&out, "retry: %d\n", e.Retry.Milliseconds())
+	}
+	for _, line := range strings.Split(e.Data, "\n") {
+		fmt.Fprintf(&out, "data: %s\n", line)
+	}
+	out.WriteString("\n")
+	return out.String()
+}
+
+func writeAndFlush(w http.ResponseWriter, chunk []byte) error {
+	if _, err := w.Write(chunk); err != nil {
+		return err
+	}
+	return http.NewResponseController(w).Flush()
+}
+
+// RespondWithStream returns a handler that responds with statusCode and then writes each chunk separately, flushing after each one and
+// waiting interval between chunks.  Since the response is flushed before it is complete it is sent using chunked transfer encoding (unless you provide a Content-Length header).
+// Use it to test clients that consume streaming bodies - pass single-byte chunks and a long interval to simulate a slowly trickling body.
+//
+// Also, RespondWithStream can be given an optional http.Header.  The headers defined therein will be added to the response headers.
+func (g GHTTPWithGomega) RespondWithStream(statusCode int, chunks []string, interval time.Duration, optionalHeader ...http.Header) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if len(optionalHeader) == 1 {
+			copyHeader(optionalHeader[0], w.Header())
+		}
+		w.WriteHeader(statusCode)
+		g.gomega.Expect(http.NewResponseController(w).Flush()).Should(Succeed(), "Failed to flush response headers")
+		for i, chunk := range chunks {
+			if i > 0 && interval > 0 {
+				select {
+				case <-time.After(interval):
+				case <-req.Context().Done():
+					return
+				}
+			}
+			if writeAndFlush(w, []byte(chunk)) != nil {
+				return
+			}
+		}
+	}
+}
+
+// RespondWithSSE returns a handler that responds with a text/event-stream of the passed-in events, flushing after each event.
+func (g GHTTPWithGomega) RespondWithSSE(events ...SSEEvent) http.HandlerFunc {
+	chunks := make([]string, len(events))
+	for i, event := range events {
+		chunks[i] = event.String()
+	}
+	return g.RespondWithStream(http.StatusOK, chunks, 0, sseHeader())
+}
+
+// RespondWithNDJSON returns a handler that responds with a stream of newline-delimited JSON - one line per JSON-encoded object - flushing after each line.
+func (g GHTTPWithGomega) RespondWithNDJSON(objects ...any) http.HandlerFunc {
+	chunks := make([]string, len(objects))
+	for i, object := range objects {
+		data, err := json.Marshal(object)
+		g.gomega.Expect(err).ShouldNot(HaveOccurred())
+		chunks[i] = string(data) + "\n"
+	}
+	return g.RespondWithStream(http.StatusOK, chunks, 0, http.Header{"Content-Type": []string{"application/x-ndjson"}})
+}
+
+func sseHeader() http.Header {
+	return http.Header{
+		"Content-Type":  []string{"text/event-stream"},
+		"Cache-Control": []string{"no-cache"},
+	}
+}
+
+var errStreamClosed = errors.New("the stream has been closed")
+
+/*
+Stream is a streaming response that you control from your spec.  Register its Handler with the server and then push chunks to the client with Send, SendSSE, and SendJSON:
+
+	stream := ghttp.NewStream()
+	server.AppendHandlers(ghttp.CombineHandlers(
+		ghttp.VerifyRequest("GET", "/events"),
+		stream.SSEHandler(),
+	))
+
+	client.Subscribe(server.URL() + "/events")
+	Eventually(stream.Connected).Should(BeTrue())
+	stream.SendSSE(ghttp.SSEEvent{Event: "sprocket", Data: `{"color": "red"}`})
+	Eventually(client.Sprockets).Should(HaveLen(1))
+	stream.Close()
+
+Chunks sent before the client connects are queued and written as soon as it does.  Close ends the response normally and Abort cuts the connection
+mid-stream (as does Server.CloseClientConnections).  A Stream serves a single response - subsequent requests to its handler receive an empty response.
+
+Server.Close waits for in-flight requests to complete, so make sure to Close (or Abort) the stream before closing the server.
+*/
+type Stream struct {
+	lock      *sync.Mutex
+	pending   [][]byte
+	notify    chan struct{}
+	closed    bool
+	aborted   bool
+	connected bool
+	served    bool
+}
+
+// NewStream returns a new Stream
+func NewStream() *Stream {
+	return &Stream{
+		lock:   &sync.Mutex{},
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// Handler returns a handler that responds with statusCode and then writes the chunks sent to the stream, flushing after each one, until the stream is closed or aborted.
+//
+// Also, Handler can be given an optional http.Header.  The headers defined therein will be added to the response headers.
+func (s *Stream) Handler(statusCode int, optionalHeader ...http.Header) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		s.lock.Lock()
+		if s.served {
+			s.lock.Unlock()
+			w.WriteHeader(statusCode)
+			return
+		}
+		s.served, s.connected = true, true
+		s.lock.Unlock()
+		defer func() {
+			s.lock.Lock()
+			s.connected = false
+			s.lock.Unlock()
+		}()
+
+		if len(optionalHeader) == 1 {
+			copyHeader(optionalHeader[0], w.Header())
+		}
+		w.WriteHeader(statusCode)
+		if http.NewResponseController(w).Flush() != nil {
+			return
+		}
+		for {
+			s.lock.Lock()
+			pending, closed, aborted := s.pending, s.closed, s.aborted
+			s.pending = nil
+			s.lock.Unlock()
+
+			if aborted {
+				panic(http.ErrAbortHandler)
+			}
+			for _, chunk := range pending {
+				if writeAndFlush(w, chunk) != nil {
+					return
+				}
+			}
+			if closed {
+				return
+			}
+			select {
+			case <-s.notify:
+			case <-req.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+// SSEHandler returns a handler that responds with a text/event-stream and then writes the chunks and events sent to the stream
+func (s *Stream) SSEHandler() http.HandlerFunc {
+	return s.Handler(http.StatusOK, sseHeader())
+}
+
+// Connected returns true while the stream's handler is serving a response
+func (s *Stream) Connected() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.connected
+}
+
+func (s *Stream) enqueue(chunk []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed || s.aborted {
+		return errStreamClosed
+	}
+	s.pending = append(s.pending, chunk)
+	s.wake()
+	return nil
+}
+
+// wake must be called with the lock held
+func (s *Stream) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Send writes chunk (a string or []byte) to the client
+func (s *Stream) Send(chunk any) error {
+	switch x := chunk.(type) {
+	case string:
+		return s.enqueue([]byte(x))
+	case []byte:
+		return s.enqueue(append([]byte{}, x...))
+	default:
+		return fmt.Errorf("Stream.Send expects a string or []byte.  Got %T", chunk)
+	}
+}
+
+// SendSSE writes event to the client in the text/event-stream wire format
+func (s *Stream) SendSSE(event SSEEvent) error {
+	return s.enqueue([]byte(event.String()))
+}
+
+// SendJSON writes object to the client as a line of newline-delimited JSON
+func (s *Stream) SendJSON(object any) error {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	return s.enqueue(append(data, '\n'))
+}
+
+// Close ends the response once any pending chunks have been written
+func (s *Stream) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+	s.wake()
+}
+
+// Abort cuts the connection without completing the response - the client sees the body end unexpectedly.  Pending chunks are discarded.
+func (s *Stream) Abort() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.aborted, s.pending = true, nil
+	s.wake()
+}
+
+func RespondWithStream(statusCode int, chunks []string, interval time.Duration, optionalHeader ...http.Header) http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).RespondWithStream(statusCode, chunks, interval, optionalHeader...)
+}
+
+func RespondWithSSE(events ...SSEEvent) http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).RespondWithSSE(events...)
+}
+
+func RespondWithNDJSON(objects ...any) http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).RespondWithNDJSON(objects...)
+}
@@ -0,0 +1,165 @@
+package ghttp_test
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Streaming responses", func() {
+	var s *Server
+
+	BeforeEach(func() {
+		s = NewServer()
+	})
+
+	AfterEach(func() {
+		s.Close()
+	})
+
+	get := func() *http.Response {
+		resp, err := http.Get(s.URL() + "/stream")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(resp.Body.Close)
+		return resp
+	}
+
+	readLine := func(reader *bufio.Reader) string {
+		line, err := reader.ReadString('\n')
+		Expect(err).NotTo(HaveOccurred())
+		return line
+	}
+
+	Describe("SSEEvent", func() {
+		It("renders the event in the text/event-stream format", func() {
+			Expect(SSEEvent{Data: "hello"}.String()).To(Equal("data: hello\n\n"))
+			Expect(SSEEvent{ID: "7", Event: "sprocket", Data: "line 1\nline 2", Retry: 3 * time.Second}.String()).To(Equal("id: 7\nevent: sprocket\nretry: 3000\ndata: line 1\ndata: line 2\n\n"))
+		})
+	})
+
+	Describe("RespondWithStream", func() {
+		It("writes and flushes each chunk separately, waiting between them", func() {
+			s.AppendHandlers(RespondWithStream(http.StatusAccepted, []string{"one\n", "two\n", "three\n"}, 50*time.Millisecond, http.Header{"X-Sprocket": []string{"red"}}))
+			start := time.Now()
+			resp := get()
+			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+			Expect(resp.Header.Get("X-Sprocket")).To(Equal("red"))
+			Expect(resp.TransferEncoding).To(Equal([]string{"chunked"}))
+
+			reader := bufio.NewReader(resp.Body)
+			Expect(readLine(reader)).To(Equal("one\n"))
+			Expect(time.Since(start)).To(BeNumerically("<", 50*time.Millisecond))
+			Expect(readLine(reader)).To(Equal("two\n"))
+			Expect(readLine(reader)).To(Equal("three\n"))
+			Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+			_, err := reader.ReadByte()
+			Expect(err).To(Equal(io.EOF))
+		})
+	})
+
+	Describe("RespondWithSSE", func() {
+		It("responds with an event stream", func() {
+			s.AppendHandlers(RespondWithSSE(SSEEvent{Event: "a", Data: "1"}, SSEEvent{Data: "2"}))
+			resp := get()
+			Expect(resp.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+			Expect(resp).To(HaveHTTPBody("event: a\ndata: 1\n\ndata: 2\n\n"))
+		})
+	})
+
+	Describe("RespondWithNDJSON", func() {
+		It("responds with newline-delimited JSON", func() {
+			s.AppendHandlers(RespondWithNDJSON(map[string]int{"a": 1}, []int{2}))
+			resp := get()
+			Expect(resp.Header.Get("Content-Type")).To(Equal("application/x-ndjson"))
+			Expect(resp).To(HaveHTTPBody("{\"a\":1}\n[2]\n"))
+		})
+	})
+
+	Describe("Stream", func() {
+		var stream *Stream
+
+		BeforeEach(func() {
+			stream = NewStream()
+		})
+
+		AfterEach(func() {
+			stream.Close()
+		})
+
+		It("writes the chunks sent from the spec until the stream is closed", func() {
+			s.AppendHandlers(stream.Handler(http.StatusOK, http.Header{"Content-Type": []string{"text/plain"}}))
+			Expect(stream.Send("queued\n")).To(Succeed())
+			Expect(stream.Connected()).To(BeFalse())
+
+			resp := get()
+			Expect(resp.Header.Get("Content-Type")).To(Equal("text/plain"))
+			Eventually(stream.Connected).Should(BeTrue())
+			reader := bufio.NewReader(resp.Body)
+			Expect(readLine(reader)).To(Equal("queued\n"))
+
+			Expect(stream.Send([]byte("bytes\n"))).To(Succeed())
+			Expect(readLine(reader)).To(Equal("bytes\n"))
+			Expect(stream.SendJSON(map[string]string{"color": "red"})).To(Succeed())
+			Expect(readLine(reader)).To(Equal("{\"color\":\"red\"}\n"))
+			Expect(stream.Send(3)).To(MatchError("Stream.Send expects a string or []byte.  Got int"))
+
+			Expect(stream.Send("last\n")).To(Succeed())
+			stream.Close()
+			Expect(readLine(reader)).To(Equal("last\n"))
+			_, err := reader.ReadByte()
+			Expect(err).To(Equal(io.EOF))
+			Eventually(stream.Connected).Should(BeFalse())
+			Expect(stream.Send("too late")).To(MatchError("the stream has been closed"))
+		})
+
+		It("can stream server-sent events", func() {
+			s.AppendHandlers(stream.SSEHandler())
+			resp := get()
+			Expect(resp.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+			reader := bufio.NewReader(resp.Body)
+			Expect(stream.SendSSE(SSEEvent{Event: "sprocket", Data: "red"})).To(Succeed())
+			Expect(readLine(reader)).To(Equal("event: sprocket\n"))
+			Expect(readLine(reader)).To(Equal("data: red\n"))
+			Expect(readLine(reader)).To(Equal("\n"))
+		})
+
+		It("can abort the response mid-stream", func() {
+			s.AppendHandlers(stream.Handler(http.StatusOK))
+			resp := get()
+			reader := bufio.NewReader(resp.Body)
+			Expect(stream.Send("partial\n")).To(Succeed())
+			Expect(readLine(reader)).To(Equal("partial\n"))
+
+			stream.Abort()
+			_, err := reader.ReadByte()
+			Expect(err).To(MatchError(io.ErrUnexpectedEOF))
+			Eventually(stream.Connected).Should(BeFalse())
+			Expect(stream.Send("too late")).To(MatchError("the stream has been closed"))
+		})
+
+		It("stops when the server closes client connections", func() {
+			s.AppendHandlers(stream.Handler(http.StatusOK))
+			resp := get()
+			reader := bufio.NewReader(resp.Body)
+			Expect(stream.Send("partial\n")).To(Succeed())
+			Expect(readLine(reader)).To(Equal("partial\n"))
+
+			s.CloseClientConnections()
+			_, err := reader.ReadByte()
+			Expect(err).To(MatchError(io.ErrUnexpectedEOF))
+			Eventually(stream.Connected).Should(BeFalse())
+		})
+
+		It("serves a single response", func() {
+			s.RouteToHandler("GET", "/stream", stream.Handler(http.StatusOK))
+			stream.Close()
+			Expect(get()).To(HaveHTTPBody(""))
+			Expect(get()).To(HaveHTTPStatus(http.StatusOK))
+		})
+	})
+})
@@ -118,6 +118,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/internal/gutil"
@@ -179,9 +180,14 @@ type Server struct {
 	Writer io.Writer
 
 	receivedRequests []*http.Request
+	recordedRequests []*RecordedRequest
 	requestHandlers  []http.HandlerFunc
 	routedHandlers   []routedHandler
 
+	//serves requests that no routed or appended handler is available for (e.g. a CassetteServer's recording or replay handler)
+	fallbackHandler     http.HandlerFunc
+	fallbackHandlerName string
+
 	rwMutex *sync.RWMutex
 	calls   int
 }
@@ -222,13 +228,25 @@ func (s *Server) Close() {
 //
 //  1. If the request matches a handler registered with RouteToHandler, that handler is called.
 //  2. Otherwise, if there are handlers registered via AppendHandlers, those handlers are called in order.
-//  3. If all registered handlers have been called then:
+//  3. Otherwise, if the server has a fallback handler (e.g. a CassetteServer's recording or replay handler), that handler is called.
+//  4. If none of the above apply then:
 //     a) If AllowUnhandledRequests is set to true, the request will be handled with response code of UnhandledRequestStatusCode
 //     b) If AllowUnhandledRequests is false, the request will not be handled and the current test will be marked as failed.
+//
+// Every request (handled or not) is added to ReceivedRequests and snapshotted in RecordedRequests before it is dispatched.  The snapshot's
+// body is recorded as the handler reads it; once the handler returns, the server reads whatever the handler left of the body to complete it.
 func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	receivedAt := time.Now()
+	body := &recordingBody{body: req.Body}
+	req.Body = body
+
 	s.rwMutex.Lock()
 	defer func() {
 		e := recover()
+		//http.ErrAbortHandler is how handlers deliberately cut a connection - let net/http handle it
+		if e == http.ErrAbortHandler {
+			panic(e)
+		}
 		if e != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -258,15 +276,27 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 
 	s.receivedRequests = append(s.receivedRequests, req)
-	if routedHandler, ok := s.handlerForRoute(req.Method, req.URL.Path); ok {
+	recorded := newRecordedRequest(req, receivedAt)
+	s.recordedRequests = append(s.recordedRequests, recorded)
+	defer s.finishRecording(recorded, body)
+
+	if routedHandler, route, ok := s.handlerForRoute(req.Method, req.URL.Path); ok {
+		recorded.Handler = "RouteToHandler(" + req.Method + ", " + route + ")"
 		s.rwMutex.Unlock()
 		routedHandler(w, req)
 	} else if s.calls < len(s.requestHandlers) {
 		h := s.requestHandlers[s.calls]
+		recorded.Handler = fmt.Sprintf("AppendHandlers[%d]", s.calls)
 		s.calls++
 		s.rwMutex.Unlock()
 		h(w, req)
+	} else if s.fallbackHandler != nil {
+		h := s.fallbackHandler
+		recorded.Handler = s.fallbackHandlerName
+		s.rwMutex.Unlock()
+		h(w, req)
 	} else {
+		recorded.Handler = "unhandled"
 		s.rwMutex.Unlock()
 		if s.GetAllowUnhandledRequests() {
 			gutil.ReadAll(req.Body)
@@ -280,6 +310,14 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// finishRecording completes the request's snapshot once its handler has returned
+func (s *Server) finishRecording(recorded *RecordedRequest, body *recordingBody) {
+	recordedBody := body.drain()
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	recorded.Body = recordedBody
+}
+
 // ReceivedRequests is an array containing all requests received by the server (both handled and unhandled requests)
 func (s *Server) ReceivedRequests() []*http.Request {
 	s.rwMutex.RLock()
@@ -288,6 +326,21 @@ func (s *Server) ReceivedRequests() []*http.Request {
 	return s.receivedRequests
 }
 
+// RecordedRequests returns snapshots of all requests received by the server (both handled and unhandled requests).
+// Unlike ReceivedRequests, the snapshots include the full request body, even after handlers have consumed it.
+//
+// You'll typically use the HaveReceivedRequest and HaveReceivedRequestsInOrder matchers instead of inspecting the snapshots directly.
+func (s *Server) RecordedRequests() []RecordedRequest {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	recorded := make([]RecordedRequest, len(s.recordedRequests))
+	for i, r := range s.recordedRequests {
+		recorded[i] = *r
+	}
+	return recorded
+}
+
 // RouteToHandler can be used to register handlers that will always handle requests that match
 // the passed in method and path.
 //
@@ -321,20 +374,20 @@ func (s *Server) RouteToHandler(method string, path any, handler http.HandlerFun
 	s.routedHandlers = append(s.routedHandlers, rh)
 }
 
-func (s *Server) handlerForRoute(method string, path string) (http.HandlerFunc, bool) {
+func (s *Server) handlerForRoute(method string, path string) (http.HandlerFunc, string, bool) {
 	for _, rh := range s.routedHandlers {
 		if rh.method == method {
 			if rh.pathRegexp != nil {
 				if rh.pathRegexp.Match([]byte(path)) {
-					return rh.handler, true
+					return rh.handler, rh.pathRegexp.String(), true
 				}
 			} else if rh.path == path {
-				return rh.handler, true
+				return rh.handler, rh.path, true
 			}
 		}
 	}
 
-	return nil, false
+	return nil, "", false
 }
 
 // AppendHandlers will appends http.HandlerFuncs to the server's list of registered handlers.  The first incoming request is handled by the first handler, the second by the second, etc...
@@ -370,6 +423,7 @@ func (s *Server) Reset() {
 	s.HTTPTestServer.CloseClientConnections()
 	s.calls = 0
 	s.receivedRequests = nil
+	s.recordedRequests = nil
 	s.requestHandlers = nil
 	s.routedHandlers = nil
 }
@@ -657,6 +657,35 @@ var _ = Describe("TestServer", func() {
 			})
 		})
 
+		Describe("VerifyJSONSchema", func() {
+			BeforeEach(func() {
+				s.AppendHandlers(CombineHandlers(
+					VerifyRequest("POST", "/foo"),
+					VerifyJSONSchema(`{"type": "object", "required": ["a"], "properties": {"a": {"type": "integer"}}}`),
+				))
+			})
+
+			It("should verify the json body and the content type", func() {
+				resp, err = http.Post(s.URL()+"/foo", "application/json", bytes.NewReader([]byte(`{"a":3, "b":2}`)))
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+
+			It("should verify the json body and the content type", func() {
+				failures := InterceptGomegaFailures(func() {
+					http.Post(s.URL()+"/foo", "application/json", bytes.NewReader([]byte(`{"a":"3"}`)))
+				})
+				Expect(failures).Should(HaveLen(1))
+				Expect(failures[0]).Should(ContainSubstring("$.a: type: expected integer, got string"))
+			})
+
+			It("should verify the json body and the content type", func() {
+				failures := InterceptGomegaFailures(func() {
+					http.Post(s.URL()+"/foo", "application/not-json", bytes.NewReader([]byte(`{"a":3}`)))
+				})
+				Expect(failures).Should(HaveLen(1))
+			})
+		})
+
 		Describe("VerifyForm", func() {
 			var formValues url.Values
 
@@ -0,0 +1,282 @@
+package ghttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	"golang.org/x/net/websocket"
+)
+
+// WebSocketMessage is a message received over a WebSocket connection
+type WebSocketMessage struct {
+	Data []byte
+	// Binary is true for binary messages and false for text messages
+	Binary bool
+}
+
+// String returns the message payload as a string
+func (m WebSocketMessage) String() string {
+	return string(m.Data)
+}
+
+/*
+WebSocketStep is one step of the script played by a WebSocket once a client connects.  ghttp provides ExpectWebSocketMessage, ExpectWebSocketJSON,
+SendWebSocketMessage, SendWebSocketJSON, and CloseWebSocket.  You can write your own steps using WebSocket's methods and ws.Gomega() to make assertions.
+*/
+type WebSocketStep func(ws *WebSocket)
+
+/*
+WebSocket upgrades requests to WebSocket connections and plays a script against each connection.  Use NewWebSocket to construct one and register
+its Handler with the server (or simply use UpgradeToWebSocket if you don't need access to the live connection):
+
+	ws := ghttp.NewWebSocket(
+		ghttp.ExpectWebSocketJSON(`{"subscribe": "sprockets"}`),
+		ghttp.SendWebSocketJSON(map[string]string{"color": "red"}),
+	)
+	server.AppendHandlers(ghttp.CombineHandlers(
+		ghttp.VerifyRequest("GET", "/stream"),
+		ws.Handler(),
+	))
+
+Once the script is done the connection stays open - messages sent by the client are still recorded - until the client disconnects or you call ws.Close().
+Use the live connection to make further assertions:
+
+	Eventually(ws.Received).Should(ContainElement(HaveField("Data", MatchJSON(`{"ack": true}`))))
+	Expect(ws.Send("bye")).To(Succeed())
+
+A WebSocket serves one connection at a time - each new connection replaces the previous one and replays the script from the start.
+*/
+type WebSocket struct {
+	g     Gomega
+	steps []WebSocketStep
+
+	lock      *sync.Mutex
+	conn      *websocket.Conn
+	received  []WebSocketMessage
+	consumed  int
+	connected bool
+}
+
+var errNoWebSocketConnection = errors.New("the WebSocket does not have a live connection")
+
+// NewWebSocket returns a WebSocket that plays steps against each connection
+func (g GHTTPWithGomega) NewWebSocket(steps ...WebSocketStep) *WebSocket {
+	return &WebSocket{
+		g:     g.gomega,
+		steps: steps,
+		lock:  &sync.Mutex{},
+	}
+}
+
+// UpgradeToWebSocket returns a handler that upgrades the request to a WebSocket connection and plays steps against it.
+// It is shorthand for NewWebSocket(steps...).Handler()
+func (g GHTTPWithGomega) UpgradeToWebSocket(steps ...WebSocketStep) http.HandlerFunc {
+	return g.NewWebSocket(steps...).Handler()
+}
+
+// Handler returns a handler that upgrades the request to a WebSocket connection and plays the WebSocket's script against it.
+// The handler accepts connections from any origin.
+func (ws *WebSocket) Handler() http.HandlerFunc {
+	server := websocket.Server{
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler:   ws.serve,
+	}
+	return server.ServeHTTP
+}
+
+// Gomega returns the Gomega instance the WebSocket makes assertions with - use it when writing your own WebSocketSteps
+func (ws *WebSocket) Gomega() Gomega {
+	return ws.g
+}
+
+// Connected returns true while the WebSocket has a live connection
+func (ws *WebSocket) Connected() bool {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	return ws.connected
+}
+
+// Received returns all the messages received on the current (or most recent) connection, including those consumed by the script
+func (ws *WebSocket) Received() []WebSocketMessage {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	return append([]WebSocketMessage{}, ws.received...)
+}
+
+// Send sends message to the client.  Strings are sent as text messages and []bytes as binary messages.
+func (ws *WebSocket) Send(message any) error {
+	switch message.(type) {
+	case string, []byte:
+	default:
+		return fmt.Errorf("WebSocket.Send expects a string or []byte.  Got:\n%s", format.Object(message, 1))
+	}
+	ws.lock.Lock()
+	conn, connected := ws.conn, ws.connected
+	ws.lock.Unlock()
+	if !connected {
+		return errNoWebSocketConnection
+	}
+	return websocket.Message.Send(conn, message)
+}
+
+// Close closes the live connection, if there is one
+func (ws *WebSocket) Close() error {
+	ws.lock.Lock()
+	conn, connected := ws.conn, ws.connected
+	ws.lock.Unlock()
+	if !connected {
+		return nil
+	}
+	return conn.Close()
+}
+
+// NextMessage waits for the next message the script has not consumed yet.  It fails (using the WebSocket's Gomega) if no message arrives within
+// the default Eventually timeout or if the client disconnects first.
+func (ws *WebSocket) NextMessage() WebSocketMessage {
+	var message WebSocketMessage
+	ws.g.Eventually(func() (bool, error) {
+		ws.lock.Lock()
+		defer ws.lock.Unlock()
+		if ws.consumed < len(ws.received) {
+			message = ws.received[ws.consumed]
+			ws.consumed++
+			return true, nil
+		}
+		if !ws.connected {
+			return false, StopTrying("The WebSocket connection closed")
+		}
+		return false, nil
+	}).Should(BeTrue(), "Timed out waiting for WebSocket message #%d", ws.consumedCount()+1)
+	return message
+}
+
+func (ws *WebSocket) consumedCount() int {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	return ws.consumed
+}
+
+var webSocketCodec = websocket.Codec{
+	Unmarshal: func(data []byte, payloadType byte, v any) error {
+		*(v.(*WebSocketMessage)) = WebSocketMessage{Data: data, Binary: payloadType == websocket.BinaryFrame}
+		return nil
+	},
+}
+
+func (ws *WebSocket) serve(conn *websocket.Conn) {
+	ws.lock.Lock()
+	ws.conn, ws.connected, ws.received, ws.consumed = conn, true, nil, 0
+	ws.lock.Unlock()
+
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			var message WebSocketMessage
+			err := webSocketCodec.Receive(conn, &message)
+			ws.lock.Lock()
+			//once a new connection has replaced this one, this connection's messages (and its closing) no longer concern the WebSocket
+			if ws.conn == conn {
+				if err != nil {
+					ws.connected = false
+				} else {
+					ws.received = append(ws.received, message)
+				}
+			}
+			ws.lock.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	defer func() {
+		conn.Close()
+		<-readerDone
+	}()
+
+	for _, step := range ws.steps {
+		step(ws)
+	}
+	<-readerDone
+}
+
+// ExpectWebSocketMessage returns a step that waits for the next message from the client and asserts that it satisfies expected.
+// expected may be a string, a []byte, or a matcher - matchers are handed the message payload as a string.
+func (g GHTTPWithGomega) ExpectWebSocketMessage(expected any) WebSocketStep {
+	var matcher types.GomegaMatcher
+	switch e := expected.(type) {
+	case string:
+		matcher = Equal(e)
+	case []byte:
+		matcher = Equal(string(e))
+	case types.GomegaMatcher:
+		matcher = e
+	default:
+		g.gomega.Expect(expected).Should(BeNil(), "Invalid type for expected message.  Should be string, []byte, or GomegaMatcher.")
+	}
+	return func(ws *WebSocket) {
+		message := ws.NextMessage()
+		ws.g.Expect(message.String()).To(matcher, "WebSocket message mismatch")
+	}
+}
+
+// ExpectWebSocketJSON returns a step that waits for the next message from the client and asserts that it is JSON equivalent to expected
+func (g GHTTPWithGomega) ExpectWebSocketJSON(expected string) WebSocketStep {
+	return g.ExpectWebSocketMessage(MatchJSON(expected))
+}
+
+// SendWebSocketMessage returns a step that sends message to the client.  Strings are sent as text messages and []bytes as binary messages.
+func (g GHTTPWithGomega) SendWebSocketMessage(message any) WebSocketStep {
+	return func(ws *WebSocket) {
+		ws.g.Expect(ws.Send(message)).To(Succeed(), "Failed to send WebSocket message")
+	}
+}
+
+// SendWebSocketJSON returns a step that JSON-encodes object and sends it to the client as a text message
+func (g GHTTPWithGomega) SendWebSocketJSON(object any) WebSocketStep {
+	data, err := json.Marshal(object)
+	g.gomega.Expect(err).ShouldNot(HaveOccurred())
+	return g.SendWebSocketMessage(string(data))
+}
+
+// CloseWebSocket returns a step that closes the connection
+func (g GHTTPWithGomega) CloseWebSocket() WebSocketStep {
+	return func(ws *WebSocket) {
+		ws.Close()
+	}
+}
+
+func NewWebSocket(steps ...WebSocketStep) *WebSocket {
+	return NewGHTTPWithGomega(gomega.Default).NewWebSocket(steps...)
+}
+
+func UpgradeToWebSocket(steps ...WebSocketStep) http.HandlerFunc {
+	return NewGHTTPWithGomega(gomega.Default).UpgradeToWebSocket(steps...)
+}
+
+func ExpectWebSocketMessage(expected any) WebSocketStep {
+	return NewGHTTPWithGomega(gomega.Default).ExpectWebSocketMessage(expected)
+}
+
+func ExpectWebSocketJSON(expected string) WebSocketStep {
+	return NewGHTTPWithGomega(gomega.Default).ExpectWebSocketJSON(expected)
+}
+
+func SendWebSocketMessage(message any) WebSocketStep {
+	return NewGHTTPWithGomega(gomega.Default).SendWebSocketMessage(message)
+}
+
+func SendWebSocketJSON(object any) WebSocketStep {
+	return NewGHTTPWithGomega(gomega.Default).SendWebSocketJSON(object)
+}
+
+func CloseWebSocket() WebSocketStep {
+	return NewGHTTPWithGomega(gomega.Default).CloseWebSocket()
+}
@@ -0,0 +1,180 @@
+package ghttp_test
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/ghttp"
+	"golang.org/x/net/websocket"
+)
+
+var _ = Describe("WebSockets", func() {
+	var s *Server
+
+	BeforeEach(func() {
+		s = NewServer()
+	})
+
+	AfterEach(func() {
+		s.Close()
+	})
+
+	dial := func(path string) *websocket.Conn {
+		conn, err := websocket.Dial("ws"+strings.TrimPrefix(s.URL(), "http")+path, "", s.URL())
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(func() { conn.Close() })
+		return conn
+	}
+
+	receive := func(conn *websocket.Conn) string {
+		var message string
+		Expect(websocket.Message.Receive(conn, &message)).To(Succeed())
+		return message
+	}
+
+	It("plays the script against the connection", func() {
+		s.AppendHandlers(CombineHandlers(
+			VerifyRequest("GET", "/stream"),
+			UpgradeToWebSocket(
+				ExpectWebSocketJSON(`{"subscribe": "sprockets"}`),
+				SendWebSocketJSON(map[string]string{"color": "red"}),
+				ExpectWebSocketMessage(HavePrefix("ack")),
+				SendWebSocketMessage([]byte{0x01, 0x02}),
+				CloseWebSocket(),
+			),
+		))
+
+		conn := dial("/stream")
+		Expect(websocket.Message.Send(conn, `{"subscribe":"sprockets"}`)).To(Succeed())
+		Expect(receive(conn)).To(MatchJSON(`{"color":"red"}`))
+		Expect(websocket.Message.Send(conn, "ack 1")).To(Succeed())
+
+		var binary []byte
+		Expect(websocket.Message.Receive(conn, &binary)).To(Succeed())
+		Expect(binary).To(Equal([]byte{0x01, 0x02}))
+
+		var message string
+		Expect(websocket.Message.Receive(conn, &message)).NotTo(Succeed())
+		Expect(s).To(HaveReceivedRequest(RequestTo("GET", "/stream")))
+	})
+
+	It("gives access to the live connection", func() {
+		ws := NewWebSocket(SendWebSocketMessage("hello"))
+		s.RouteToHandler("GET", "/stream", ws.Handler())
+		Expect(ws.Connected()).To(BeFalse())
+		Expect(ws.Send("too early")).To(MatchError("the WebSocket does not have a live connection"))
+
+		conn := dial("/stream")
+		Expect(receive(conn)).To(Equal("hello"))
+		Eventually(ws.Connected).Should(BeTrue())
+
+		Expect(websocket.Message.Send(conn, "one")).To(Succeed())
+		Expect(websocket.Message.Send(conn, []byte("two"))).To(Succeed())
+		Eventually(ws.Received).Should(HaveLen(2))
+		Expect(ws.Received()[0]).To(Equal(WebSocketMessage{Data: []byte("one"), Binary: false}))
+		Expect(ws.Received()[1]).To(Equal(WebSocketMessage{Data: []byte("two"), Binary: true}))
+		Expect(ws.Received()[0].String()).To(Equal("one"))
+
+		Expect(ws.Send("from the spec")).To(Succeed())
+		Expect(receive(conn)).To(Equal("from the spec"))
+		Expect(ws.Send(3)).To(MatchError(ContainSubstring("WebSocket.Send expects a string or []byte")))
+
+		Expect(ws.Close()).To(Succeed())
+		Eventually(ws.Connected).Should(BeFalse())
+		var message string
+		Expect(websocket.Message.Receive(conn, &message)).NotTo(Succeed())
+	})
+
+	It("notices when the client disconnects", func() {
+		ws := NewWebSocket()
+		s.AppendHandlers(ws.Handler())
+		conn := dial("/")
+		Eventually(ws.Connected).Should(BeTrue())
+		conn.Close()
+		Eventually(ws.Connected).Should(BeFalse())
+	})
+
+	It("tracks only the newest connection when a client reconnects before the previous connection closes", func() {
+		ws := NewWebSocket()
+		s.RouteToHandler("GET", "/stream", ws.Handler())
+		first := dial("/stream")
+		Expect(websocket.Message.Send(first, "one")).To(Succeed())
+		Eventually(ws.Received).Should(HaveLen(1))
+
+		second := dial("/stream")
+		Eventually(ws.Received).Should(BeEmpty())
+		Expect(websocket.Message.Send(second, "two")).To(Succeed())
+		Eventually(ws.Received).Should(HaveLen(1))
+
+		Expect(websocket.Message.Send(first, "stale")).To(Succeed())
+		first.Close()
+		Consistently(ws.Received).Should(ConsistOf(WebSocketMessage{Data: []byte("two")}))
+		Expect(ws.Connected()).To(BeTrue())
+
+		Expect(ws.Send("hello")).To(Succeed())
+		Expect(receive(second)).To(Equal("hello"))
+	})
+
+	It("supports custom steps", func() {
+		ws := NewWebSocket(func(ws *WebSocket) {
+			message := ws.NextMessage()
+			ws.Gomega().Expect(ws.Send(strings.ToUpper(message.String()))).To(Succeed())
+		})
+		s.AppendHandlers(ws.Handler())
+		conn := dial("/")
+		Expect(websocket.Message.Send(conn, "shout")).To(Succeed())
+		Expect(receive(conn)).To(Equal("SHOUT"))
+	})
+
+	Describe("failures", func() {
+		var lock *sync.Mutex
+		var failures []string
+		var g *GHTTPWithGomega
+
+		BeforeEach(func() {
+			lock = &sync.Mutex{}
+			failures = []string{}
+			g = NewGHTTPWithGomega(NewGomega(func(message string, _ ...int) {
+				lock.Lock()
+				defer lock.Unlock()
+				failures = append(failures, message)
+			}))
+		})
+
+		getFailures := func() []string {
+			lock.Lock()
+			defer lock.Unlock()
+			return append([]string{}, failures...)
+		}
+
+		It("fails when a message does not match", func() {
+			s.AppendHandlers(g.UpgradeToWebSocket(g.ExpectWebSocketMessage("hello")))
+			conn := dial("/")
+			Expect(websocket.Message.Send(conn, "goodbye")).To(Succeed())
+			Eventually(getFailures).Should(ConsistOf(ContainSubstring("WebSocket message mismatch")))
+		})
+
+		It("fails when the client disconnects before sending the expected message", func() {
+			s.AppendHandlers(g.UpgradeToWebSocket(g.ExpectWebSocketMessage("hello")))
+			conn := dial("/")
+			conn.Close()
+			Eventually(getFailures).Should(ContainElement(ContainSubstring("The WebSocket connection closed")))
+		})
+
+		It("fails when handed an invalid expected message", func() {
+			g.ExpectWebSocketMessage(3)
+			Expect(getFailures()).To(ConsistOf(ContainSubstring("Invalid type for expected message")))
+		})
+	})
+
+	It("rejects requests that are not WebSocket upgrades", func() {
+		s.AppendHandlers(UpgradeToWebSocket())
+		resp, err := http.Get(s.URL())
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
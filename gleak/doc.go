@@ -85,6 +85,19 @@ will show full path names for each source file:
 	    main.foo.func1() at /home/go/foo/test.go:6
 	    created by main.foo at home/go/foo/test.go:5
 
+# Snapshots
+
+Alternatively, take a Snapshot before a test and check its Leaked method
+afterwards:
+
+	snapshot := Snapshot()
+	...
+	Eventually(snapshot.Leaked).Should(BeEmpty())
+
+Leaked groups the leaked goroutines by creation site and backtrace, so that
+failure messages report each group only once, marking the first frame in user
+code.
+
 # Acknowledgement
 
 gleak has been heavily inspired by the Goroutine leak detector
@@ -113,6 +113,8 @@ var standardFilters = []types.GomegaMatcher{
 //	DoSomething()
 //	Eventually(Goroutines).ShouldNot(HaveLeaked(IgnoringGoroutines(snapshot)))
 //
+// A *GoroutineSnapshot returned by Snapshot is accepted likewise.
+//
 // Finally, HaveLeaked accepts any GomegaMatcher and will repeatedly pass it a
 // Goroutine object: if the matcher succeeds, the Goroutine object in question
 // is considered to be non-leaked and thus filtered out. While the following
@@ -132,6 +134,8 @@ func HaveLeaked(ignoring ...any) types.GomegaMatcher {
 			m.filters = append(m.filters, IgnoringTopFunction(ign))
 		case []Goroutine:
 			m.filters = append(m.filters, IgnoringGoroutines(ign))
+		case *GoroutineSnapshot:
+			m.filters = append(m.filters, IgnoringGoroutines(ign.goroutines))
 		case types.GomegaMatcher:
 			m.filters = append(m.filters, ign)
 		default:
@@ -0,0 +1,288 @@
+package gleak
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// GoroutineSnapshot is the set of goroutines that existed at a particular
+// moment, as returned by Snapshot. Its Leaked method diffs the goroutines at
+// the time of the call against the snapshot.
+type GoroutineSnapshot struct {
+	goroutines []Goroutine
+}
+
+// Snapshot takes a snapshot of the current goroutines. Take the snapshot before
+// a test and then check for goroutines that the test leaked afterwards:
+//
+//	var snapshot *GoroutineSnapshot
+//
+//	BeforeEach(func() {
+//	    snapshot = Snapshot()
+//	})
+//
+//	AfterEach(func() {
+//	    // Note: it's "snapshot.Leaked", but not "snapshot.Leaked()", when using with Eventually!
+//	    Eventually(snapshot.Leaked).Should(BeEmpty())
+//	})
+//
+// This is equivalent to passing Goroutines() to HaveLeaked, but the leaks are
+// reported grouped by where they were created (see Leaks).
+func Snapshot() *GoroutineSnapshot {
+	return &GoroutineSnapshot{goroutines: Goroutines()}
+}
+
+// Goroutines returns the goroutines in the snapshot.
+func (s *GoroutineSnapshot) Goroutines() []Goroutine {
+	return append([]Goroutine{}, s.goroutines...)
+}
+
+// Leaked returns the goroutines that exist now but didn't exist when the
+// snapshot was taken, grouped by creation site and backtrace.
+//
+// As with HaveLeaked, well-known runtime and testing goroutines are always
+// filtered out and you can pass in additional non-leaky goroutine filters in
+// any form accepted by HaveLeaked. Use WithArguments to pass them when polling
+// with Eventually:
+//
+//	Eventually(snapshot.Leaked).WithArguments("foo.bar").Should(BeEmpty())
+//
+// Leaked returns an error if any of the filters fails to match.
+func (s *GoroutineSnapshot) Leaked(ignoring ...any) (Leaks, error) {
+	matcher := HaveLeaked(append([]any{s.goroutines}, ignoring...)...).(*HaveLeakedMatcher)
+	leaked, err := matcher.filter(Goroutines(), matcher.filters)
+	if err != nil {
+		return nil, err
+	}
+	return groupLeaks(leaked), nil
+}
+
+// LeakGroup is a group of leaked goroutines that were created at the same
+// location and have the same backtrace signature, that is, the same sequence
+// of functions on their stacks.
+type LeakGroup struct {
+	Goroutines      []Goroutine // the leaked goroutines, in order of their IDs
+	CreatorFunction string      // name of the function that created the goroutines, if any
+	BornAt          string      // location the goroutines were created at, if any; format "file-path:line-number"
+	Signature       []string    // the functions on the goroutines' stacks, topmost function first
+
+	// UserFrame is the topmost frame that isn't in the Go runtime or standard
+	// library, in the form "function at file-path:line-number". It falls back
+	// to the creation site if the goroutines' stacks consist of standard
+	// library frames only.
+	UserFrame string
+
+	frames []frame // the (representative) backtrace of the first goroutine
+}
+
+// Leaks is a list of leaked goroutines, grouped by creation site and backtrace
+// signature, as returned by GoroutineSnapshot.Leaked. The groups with the most
+// goroutines come first.
+//
+// When a Leaks value appears in a failure message the groups are listed with
+// their goroutine counts and backtraces, highlighting the first frame in user
+// code - typically that's where the goroutine got stuck.
+type Leaks []LeakGroup
+
+// Count returns the total number of leaked goroutines.
+func (l Leaks) Count() int {
+	count := 0
+	for _, group := range l {
+		count += len(group.Goroutines)
+	}
+	return count
+}
+
+// Goroutines returns all leaked goroutines.
+func (l Leaks) Goroutines() []Goroutine {
+	gs := []Goroutine{}
+	for _, group := range l {
+		gs = append(gs, group.Goroutines...)
+	}
+	return gs
+}
+
+// GomegaString returns the grouped leak report used in failure messages.
+func (l Leaks) GomegaString() string {
+	if len(l) == 0 {
+		return "no leaked goroutines"
+	}
+	var buff strings.Builder
+	fmt.Fprintf(&buff, "%s in %s:", pluralize(l.Count(), "leaked goroutine"), pluralize(len(l), "group"))
+	for _, group := range l {
+		buff.WriteString("\n\n")
+		buff.WriteString(group.report())
+	}
+	return buff.String()
+}
+
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(count) + " " + noun + "s"
+}
+
+// report renders the group: a summary line, the user frame and creation site,
+// and the backtrace of the group's first goroutine with the user frame marked.
+func (g LeakGroup) report() string {
+	var buff strings.Builder
+	fmt.Fprintf(&buff, "%s (%s)\n", pluralize(len(g.Goroutines), "goroutine"), g.goroutineList())
+	if g.UserFrame != "" {
+		fmt.Fprintf(&buff, "    leaked in %s\n", g.UserFrame)
+	}
+	if g.CreatorFunction != "" {
+		fmt.Fprintf(&buff, "    created by %s at %s\n", g.CreatorFunction, formatLocation(g.BornAt))
+	}
+	buff.WriteString("    backtrace:")
+	marked := false
+	for _, f := range g.frames {
+		marker := "  "
+		if !marked && f.String() == g.UserFrame {
+			marker, marked = "> ", true
+		}
+		fmt.Fprintf(&buff, "\n      %s%s", marker, f)
+	}
+	return buff.String()
+}
+
+func (g LeakGroup) goroutineList() string {
+	entries := make([]string, len(g.Goroutines))
+	for i, gr := range g.Goroutines {
+		entries[i] = fmt.Sprintf("%d [%s]", gr.ID, gr.State)
+	}
+	return strings.Join(entries, ", ")
+}
+
+// frame is a single call in a goroutine backtrace.
+type frame struct {
+	function string // function name, without arguments
+	location string // "file-path:line-number", without the program counter offset
+}
+
+func (f frame) String() string {
+	return f.function + " at " + formatLocation(f.location)
+}
+
+// formatLocation applies formatFilename to the file path of a
+// "file-path:line-number" location.
+func formatLocation(location string) string {
+	if idx := strings.LastIndex(location, ":"); idx >= 0 {
+		return formatFilename(location[:idx]) + location[idx:]
+	}
+	return formatFilename(location)
+}
+
+// parseFrames splits a goroutine backtrace into its frames, excluding the
+// "created by" frame.
+func parseFrames(backtrace string) []frame {
+	frames := []frame{}
+	lines := strings.Split(strings.TrimRight(backtrace, "\n"), "\n")
+	for i := 0; i+1 < len(lines); i += 2 {
+		function := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(function, backtraceCreatorPrefix) {
+			break
+		}
+		if strings.HasPrefix(function, "...") {
+			// "...additional frames elided..."
+			i--
+			continue
+		}
+		if idx := strings.LastIndex(function, "("); idx > 0 {
+			function = function[:idx]
+		}
+		location := strings.TrimSpace(lines[i+1])
+		if idx := strings.LastIndex(location, " +0x"); idx >= 0 {
+			location = location[:idx]
+		}
+		frames = append(frames, frame{function: function, location: location})
+	}
+	return frames
+}
+
+const backtraceCreatorPrefix = "created by "
+
+// goroot returns the directory containing the standard library sources, as
+// recorded in this binary's debug information (and thus also correct for
+// binaries built with -trimpath).
+var goroot = sync.OnceValue(func() string {
+	fn := runtime.FuncForPC(reflect.ValueOf(strconv.Itoa).Pointer())
+	if fn == nil {
+		return ""
+	}
+	file, _ := fn.FileLine(fn.Entry())
+	if !strings.HasSuffix(file, "/strconv/itoa.go") {
+		return ""
+	}
+	return strings.TrimSuffix(file, "strconv/itoa.go")
+})
+
+// isStdlib returns true if the frame belongs to the Go runtime or standard
+// library.
+func isStdlib(f frame) bool {
+	if root := goroot(); root != "" {
+		return strings.HasPrefix(f.location, root)
+	}
+	// fall back to the package path: standard library packages don't have a
+	// dot in their first path element (but then neither does "main").
+	first, _, _ := strings.Cut(packagePath(f.function), "/")
+	return first != "main" && !strings.Contains(first, ".")
+}
+
+// packagePath returns the import path of the package a (fully qualified)
+// function belongs to, such as "net/http" for "net/http.(*conn).serve".
+func packagePath(function string) string {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.IndexRune(function[slash+1:], '.')
+	if dot < 0 {
+		return function
+	}
+	return function[:slash+1+dot]
+}
+
+// groupLeaks groups the leaked goroutines by creation site and backtrace
+// signature.
+func groupLeaks(leaked []Goroutine) Leaks {
+	sort.Slice(leaked, func(i, j int) bool { return leaked[i].ID < leaked[j].ID })
+	groups := Leaks{}
+	index := map[string]int{}
+	for _, g := range leaked {
+		frames := parseFrames(g.Backtrace)
+		signature := make([]string, len(frames))
+		for i, f := range frames {
+			signature[i] = f.function
+		}
+		key := g.CreatorFunction + "\x00" + g.BornAt + "\x00" + strings.Join(signature, "\x00")
+		if idx, ok := index[key]; ok {
+			groups[idx].Goroutines = append(groups[idx].Goroutines, g)
+			continue
+		}
+		group := LeakGroup{
+			Goroutines:      []Goroutine{g},
+			CreatorFunction: g.CreatorFunction,
+			BornAt:          g.BornAt,
+			Signature:       signature,
+			frames:          frames,
+		}
+		for _, f := range frames {
+			if !isStdlib(f) {
+				group.UserFrame = f.String()
+				break
+			}
+		}
+		if group.UserFrame == "" && g.CreatorFunction != "" {
+			group.UserFrame = frame{function: g.CreatorFunction, location: g.BornAt}.String()
+		}
+		index[key] = len(groups)
+		groups = append(groups, group)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].Goroutines) > len(groups[j].Goroutines)
+	})
+	return groups
+}
@@ -0,0 +1,151 @@
+package gleak
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func blockOn(done chan struct{}) {
+	<-done
+}
+
+func startBlockedGoroutines(n int, done chan struct{}) {
+	for range n {
+		go blockOn(done)
+	}
+}
+
+var _ = Describe("Snapshot", func() {
+
+	It("finds no leaks when there aren't any", func() {
+		snapshot := Snapshot()
+		Expect(snapshot.Goroutines()).NotTo(BeEmpty())
+		Eventually(snapshot.Leaked).Should(BeEmpty())
+	})
+
+	It("groups leaked goroutines by creation site and backtrace", func() {
+		snapshot := Snapshot()
+		done := make(chan struct{})
+		startBlockedGoroutines(3, done)
+		go func() {
+			select {
+			case <-done:
+			case <-time.After(time.Hour):
+			}
+		}()
+		DeferCleanup(func() {
+			close(done)
+		})
+
+		var leaks Leaks
+		Eventually(func(g Gomega) {
+			var err error
+			leaks, err = snapshot.Leaked()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(leaks.Goroutines()).To(HaveLen(4))
+			g.Expect(leaks.Goroutines()).To(HaveEach(HaveField("State", Or(Equal("chan receive"), Equal("select")))))
+		}).Should(Succeed())
+
+		Expect(leaks).To(HaveLen(2))
+		Expect(leaks.Count()).To(Equal(4))
+
+		Expect(leaks[0].Goroutines).To(HaveLen(3))
+		Expect(leaks[0].CreatorFunction).To(Equal("github.com/onsi/gomega/gleak.startBlockedGoroutines"))
+		Expect(leaks[0].Signature[0]).To(Equal("github.com/onsi/gomega/gleak.blockOn"))
+		Expect(leaks[0].UserFrame).To(MatchRegexp(`^github\.com/onsi/gomega/gleak\.blockOn at gleak/snapshot_test\.go:\d+$`))
+
+		Expect(leaks[1].Goroutines).To(HaveLen(1))
+		Expect(leaks[1].Goroutines[0].State).To(Equal("select"))
+		Expect(leaks[1].UserFrame).To(HavePrefix("github.com/onsi/gomega/gleak.init.func"))
+
+		report := leaks.GomegaString()
+		Expect(report).To(HavePrefix("4 leaked goroutines in 2 groups:\n\n3 goroutines ("))
+		Expect(report).To(MatchRegexp(`\n    leaked in github\.com/onsi/gomega/gleak\.blockOn at gleak/snapshot_test\.go:\d+\n`))
+		Expect(report).To(MatchRegexp(`\n    created by github\.com/onsi/gomega/gleak\.startBlockedGoroutines at gleak/snapshot_test\.go:\d+\n`))
+		Expect(report).To(MatchRegexp(`\n      > github\.com/onsi/gomega/gleak\.blockOn at gleak/snapshot_test\.go:\d+`))
+		Expect(report).To(ContainSubstring("\n\n1 goroutine ("))
+	})
+
+	It("accepts non-leaky goroutine filters", func() {
+		snapshot := Snapshot()
+		done := make(chan struct{})
+		DeferCleanup(func() {
+			close(done)
+		})
+		startBlockedGoroutines(2, done)
+		Eventually(snapshot.Leaked).ShouldNot(BeEmpty())
+		Eventually(snapshot.Leaked).WithArguments(IgnoringInBacktrace("github.com/onsi/gomega/gleak.blockOn")).Should(BeEmpty())
+		Eventually(Goroutines).ShouldNot(HaveLeaked(snapshot, IgnoringCreator("github.com/onsi/gomega/gleak.startBlockedGoroutines")))
+
+		_, err := snapshot.Leaked(HaveLen(1))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("renders leaks in failure messages", func() {
+		Expect(Leaks{}.GomegaString()).To(Equal("no leaked goroutines"))
+
+		leaks := groupLeaks([]Goroutine{
+			{
+				ID:              43,
+				State:           "chan receive",
+				CreatorFunction: "main.foo",
+				BornAt:          "/home/foo/test.go:5",
+				Backtrace: `runtime.gopark(0x0?, 0x0?, 0x0?, 0x0?, 0x0?)
+	` + goroot() + `runtime/proc.go:435 +0xce
+main.foo.func1()
+	/home/foo/test.go:6 +0x28
+created by main.foo in goroutine 1
+	/home/foo/test.go:5 +0x64
+`,
+			},
+			{
+				ID:              42,
+				State:           "chan receive",
+				CreatorFunction: "main.foo",
+				BornAt:          "/home/foo/test.go:5",
+				Backtrace: `runtime.gopark(0x0?, 0x0?, 0x0?, 0x0?, 0x0?)
+	` + goroot() + `runtime/proc.go:435 +0xce
+main.foo.func1()
+	/home/foo/test.go:6 +0x28
+created by main.foo in goroutine 1
+	/home/foo/test.go:5 +0x64
+`,
+			},
+		})
+		Expect(leaks).To(HaveLen(1))
+		Expect(leaks[0].Signature).To(Equal([]string{"runtime.gopark", "main.foo.func1"}))
+		Expect(leaks.GomegaString()).To(Equal(`2 leaked goroutines in 1 group:
+
+2 goroutines (42 [chan receive], 43 [chan receive])
+    leaked in main.foo.func1 at foo/test.go:6
+    created by main.foo at foo/test.go:5
+    backtrace:
+        runtime.gopark at runtime/proc.go:435
+      > main.foo.func1 at foo/test.go:6`))
+	})
+
+	It("falls back to the creation site when there are no user frames", func() {
+		leaks := groupLeaks([]Goroutine{
+			{
+				ID:              42,
+				State:           "IO wait",
+				CreatorFunction: "main.serve",
+				BornAt:          "/home/foo/serve.go:12",
+				Backtrace: `internal/poll.runtime_pollWait(0x7f, 0x72)
+	` + goroot() + `runtime/netpoll.go:351 +0x85
+created by main.serve in goroutine 1
+	/home/foo/serve.go:12 +0x64
+`,
+			},
+		})
+		Expect(leaks[0].UserFrame).To(Equal("main.serve at foo/serve.go:12"))
+	})
+
+	It("determines the package paths of functions", func() {
+		Expect(packagePath("net/http.(*conn).serve")).To(Equal("net/http"))
+		Expect(packagePath("github.com/onsi/gomega/gleak.blockOn")).To(Equal("github.com/onsi/gomega/gleak"))
+		Expect(packagePath("runtime.gopark")).To(Equal("runtime"))
+	})
+})
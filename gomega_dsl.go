@@ -513,6 +513,66 @@ func DisableDefaultTimeoutsWhenUsingContext() {
 	Default.DisableDefaultTimeoutsWhenUsingContext()
 }
 
+// EnableAsyncAssertionTracing makes every `Eventually` and `Consistently` record a trace of its attempts and include it in its failure message,
+// as if `WithTrace()` had been called.  You can also set the `GOMEGA_TRACE_ASYNC_ASSERTIONS` environment variable to enable tracing.
+func EnableAsyncAssertionTracing() {
+	Default.EnableAsyncAssertionTracing()
+}
+
+// SetDefaultEventuallyPollingStrategy sets the default polling strategy for Eventually.  When set, it takes precedence over the default polling interval.
+// Pass in nil to go back to polling at the default polling interval.
+//
+//	SetDefaultEventuallyPollingStrategy(ExponentialBackoff(10*time.Millisecond, 2, time.Second))
+func SetDefaultEventuallyPollingStrategy(strategy PollingStrategy) {
+	Default.SetDefaultEventuallyPollingStrategy(strategy)
+}
+
+// SetDefaultConsistentlyPollingStrategy sets the default polling strategy for Consistently.  When set, it takes precedence over the default polling interval.
+// Pass in nil to go back to polling at the default polling interval.
+func SetDefaultConsistentlyPollingStrategy(strategy PollingStrategy) {
+	Default.SetDefaultConsistentlyPollingStrategy(strategy)
+}
+
+// PollingStrategy determines how long Eventually and Consistently wait between attempts.  Pass one to AsyncAssertion.WithPollingStrategy()
+// or set a default with SetDefaultEventuallyPollingStrategy() and SetDefaultConsistentlyPollingStrategy().
+//
+// Gomega provides ExponentialBackoff, LinearRamp and WithJitter.  You can implement your own, or use PollingStrategyFunc.
+type PollingStrategy = types.PollingStrategy
+
+// PollingStrategyFunc allows an ordinary function to be used as a PollingStrategy:
+//
+//	Eventually(f).WithPollingStrategy(PollingStrategyFunc(func(attempt int) time.Duration {
+//		return time.Duration(attempt) * time.Second
+//	})).Should(Succeed())
+type PollingStrategyFunc = types.PollingStrategyFunc
+
+// ExponentialBackoff returns a PollingStrategy that waits initial after the first attempt and multiplies the interval by factor
+// after every subsequent attempt, up to max.  Pass in zero for max to leave the interval uncapped.
+//
+//	Eventually(client.FetchCount).WithPollingStrategy(ExponentialBackoff(10*time.Millisecond, 2, time.Second)).Should(BeNumerically(">=", 17))
+func ExponentialBackoff(initial time.Duration, factor float64, max time.Duration) PollingStrategy {
+	return internal.NewExponentialBackoff(initial, factor, max)
+}
+
+// LinearRamp returns a PollingStrategy that waits initial after the first attempt and adds step to the interval
+// after every subsequent attempt, up to max.  Pass in zero for max to leave the interval uncapped.
+func LinearRamp(initial time.Duration, step time.Duration, max time.Duration) PollingStrategy {
+	return internal.NewLinearRamp(initial, step, max)
+}
+
+// WithJitter wraps a PollingStrategy and randomly spreads the intervals it computes by up to ±fraction of their duration.
+// This helps avoid many clients polling a shared service in lockstep:
+//
+//	Eventually(client.FetchCount).WithPollingStrategy(WithJitter(ExponentialBackoff(10*time.Millisecond, 2, time.Second), 0.2)).Should(BeNumerically(">=", 17))
+func WithJitter(strategy PollingStrategy, fraction float64) PollingStrategy {
+	return internal.NewJitter(strategy, fraction)
+}
+
+// DisableAsyncAssertionTracing disables the tracing enabled by `EnableAsyncAssertionTracing()`.  Individual assertions can still opt in with `WithTrace()`.
+func DisableAsyncAssertionTracing() {
+	Default.DisableAsyncAssertionTracing()
+}
+
 // AsyncAssertion is returned by Eventually and Consistently and polls the actual value passed into Eventually against
 // the matcher passed to the Should and ShouldNot methods.
 //
@@ -0,0 +1,289 @@
+/*
+Package gsnapshot provides snapshot testing for Gomega.
+
+A snapshot is a serialized representation of a value that is stored on disk the first time a test runs and compared against on subsequent runs:
+
+	Expect(renderInvoice(order)).To(gsnapshot.MatchSnapshot())
+
+Snapshots are stored in a __snapshots__ directory (relative to the test's working directory - typically the package under test) and are keyed by the name of the current test and the order in which MatchSnapshot is called within that run of the test:
+
+	__snapshots__/<test name>/1.snap
+	__snapshots__/<test name>/2.snap
+
+Test names are made filesystem-safe; when that changes the name a short hash of the original name is appended so that tests with similar names don't share snapshots.
+
+With Ginkgo the package-level MatchSnapshot keys snapshots by the full text of the currently running spec.  With the standard testing package,
+pass your *testing.T to ForT and use the Snapshotter it returns:
+
+	func TestInvoice(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(renderInvoice(order)).To(gsnapshot.ForT(t).MatchSnapshot())
+	}
+
+Use SetTestNameProvider if you need something else.
+
+When a snapshot does not exist yet, MatchSnapshot writes it and fails - asking you to review and commit the new snapshot.  To rewrite existing snapshots run your tests in update mode by setting GOMEGA_UPDATE_SNAPSHOTS=true (or by passing -update if your test binary defines that flag).
+*/
+package gsnapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/onsi/ginkgo/v2"
+	ginkgotypes "github.com/onsi/ginkgo/v2/types"
+	"github.com/onsi/gomega/types"
+)
+
+// UpdateEnvVar is the environment variable that puts gsnapshot in update mode when set to a true value (e.g. "true" or "1")
+const UpdateEnvVar = "GOMEGA_UPDATE_SNAPSHOTS"
+
+// UpdateFlag is the name of the (optional) command-line flag that puts gsnapshot in update mode.  gsnapshot does not define this flag - it only honors it if your test binary does.
+const UpdateFlag = "update"
+
+// DefaultDirectory is the directory, relative to the current working directory, that snapshots are stored in
+const DefaultDirectory = "__snapshots__"
+
+// SnapshotExtension is the file extension used for snapshot files
+const SnapshotExtension = ".snap"
+
+/*
+UpdateMode returns true if snapshots should be rewritten instead of compared against.
+
+This is the case when the GOMEGA_UPDATE_SNAPSHOTS environment variable is set to a true value or when the test binary defines an `-update` flag that is set to true.
+*/
+func UpdateMode() bool {
+	if update, err := strconv.ParseBool(os.Getenv(UpdateEnvVar)); err == nil && update {
+		return true
+	}
+	if f := flag.Lookup(UpdateFlag); f != nil {
+		if update, err := strconv.ParseBool(f.Value.String()); err == nil && update {
+			return true
+		}
+	}
+	return false
+}
+
+// TestingT is the subset of *testing.T that ForT relies on
+type TestingT interface {
+	Name() string
+	Cleanup(func())
+	Logf(format string, args ...any)
+}
+
+/*
+A Snapshotter hands out MatchSnapshot matchers and keeps track of the snapshots that have been used.
+
+Most users will either use the package-level MatchSnapshot (backed by the Default Snapshotter) or a Snapshotter returned by ForT.
+*/
+type Snapshotter struct {
+	// Directory is the directory snapshots are read from and written to.  Defaults to DefaultDirectory.
+	Directory string
+	// Serializer turns actual values into the text that is stored in snapshots.  Defaults to SerializeJSON.
+	Serializer Serializer
+
+	t        TestingT
+	testName func() string
+	lock     *sync.Mutex
+	counts   map[any]int       // the number of snapshots claimed by each test run
+	lastRun  any               // the last test run that isn't identified by a TestingT, see currentTest
+	owners   map[string]string // the location of the Ginkgo spec using each snapshot directory
+	used     map[string]bool
+}
+
+/*
+New returns a Snapshotter that uses testName to determine the name of the current test.
+
+Pass in nil to identify the current test the way the package-level MatchSnapshot does: via the currently running Ginkgo spec.
+
+Snapshot indices start over for each run of a test - including reruns caused by FlakeAttempts, MustPassRepeatedly, --repeat or go test -count.  A custom testName function can't tell reruns apart, so in that case indices are only reset when the name it returns changes - which is why such Snapshotters must not be shared by tests running in parallel in the same process.
+*/
+func New(testName func() string) *Snapshotter {
+	return &Snapshotter{
+		Directory:  DefaultDirectory,
+		Serializer: SerializeJSON,
+		testName:   testName,
+		lock:       &sync.Mutex{},
+		counts:     map[any]int{},
+		owners:     map[string]string{},
+		used:       map[string]bool{},
+	}
+}
+
+/*
+ForT returns a Snapshotter keyed by t.Name().  Call it once per test (and per run of the test): its snapshot indices start at 1.
+
+When the test completes ForT reports snapshots belonging to the test that were not matched (e.g. because an assertion was removed) via t.Logf.  In update mode these obsolete snapshots are deleted.
+*/
+func ForT(t TestingT) *Snapshotter {
+	s := New(nil)
+	s.t = t
+	t.Cleanup(func() {
+		obsolete, err := s.obsoleteSnapshots(filepath.Join(s.Directory, directoryName(t.Name())))
+		if err != nil || len(obsolete) == 0 {
+			return
+		}
+		if UpdateMode() {
+			for _, path := range obsolete {
+				os.Remove(path)
+			}
+			t.Logf("gsnapshot removed obsolete snapshots:\n  %s", strings.Join(obsolete, "\n  "))
+			return
+		}
+		t.Logf("gsnapshot found obsolete snapshots:\n  %s\nRun your tests with %s=true to remove them.", strings.Join(obsolete, "\n  "), UpdateEnvVar)
+	})
+	return s
+}
+
+/*
+Default is the Snapshotter used by the package-level MatchSnapshot.  It identifies the current test via Ginkgo, see New.
+*/
+var Default = New(nil)
+
+// SetTestNameProvider configures the Default Snapshotter to use testName to determine the name of the current test.  Pass in nil to go back to identifying the current test via Ginkgo.
+func SetTestNameProvider(testName func() string) {
+	Default.lock.Lock()
+	defer Default.lock.Unlock()
+	Default.testName = testName
+}
+
+// MatchSnapshot returns a matcher backed by the Default Snapshotter.  See Snapshotter.MatchSnapshot.
+func MatchSnapshot() types.GomegaMatcher {
+	return Default.MatchSnapshot()
+}
+
+/*
+MatchSnapshot succeeds if the serialized actual value matches the next snapshot for the current test.
+
+The snapshot's index is claimed when MatchSnapshot is called, so it is safe to use the returned matcher with Eventually - each poll compares against the same snapshot.
+*/
+func (s *Snapshotter) MatchSnapshot() types.GomegaMatcher {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	matcher := &snapshotMatcher{serializer: s.Serializer}
+	test, err := s.currentTest()
+	if err != nil {
+		matcher.err = err
+		return matcher
+	}
+	dir := directoryName(test.name)
+	if owner, ok := s.owners[dir]; ok && owner != test.owner {
+		matcher.err = fmt.Errorf("gsnapshot can't tell the specs at %s and %s apart: they are both named %q.  Give them unique names so that they don't overwrite each other's snapshots.", owner, test.owner, test.name)
+		return matcher
+	}
+	s.owners[dir] = test.owner
+
+	if _, isName := test.run.(string); isName && s.lastRun != test.run {
+		delete(s.counts, s.lastRun)
+		s.lastRun = test.run
+	}
+	s.counts[test.run] += 1
+	matcher.path = filepath.Join(s.Directory, dir, fmt.Sprintf("%d%s", s.counts[test.run], SnapshotExtension))
+	s.used[filepath.Clean(matcher.path)] = true
+	return matcher
+}
+
+// runningTest identifies a single run of a test
+type runningTest struct {
+	name  string
+	run   any    // the test's TestingT, or a string that changes whenever a new run starts
+	owner string // the location of a Ginkgo spec, to tell apart specs with the same name
+}
+
+// currentTest must be called with s.lock held
+func (s *Snapshotter) currentTest() (runningTest, error) {
+	var test runningTest
+	switch {
+	case s.t != nil:
+		test = runningTest{name: s.t.Name(), run: s.t}
+	case s.testName != nil:
+		name := s.testName()
+		test = runningTest{name: name, run: name}
+	default:
+		if report := ginkgo.CurrentSpecReport(); report.LeafNodeType.Is(ginkgotypes.NodeTypeIt) {
+			test = runningTest{
+				name:  report.FullText(),
+				run:   fmt.Sprintf("%s@%d#%d", report.LeafNodeLocation, report.StartTime.UnixNano(), report.NumAttempts),
+				owner: report.LeafNodeLocation.String(),
+			}
+		} else {
+			return test, errors.New("gsnapshot could not determine the name of the current test.  Run MatchSnapshot in a Ginkgo spec, or use gsnapshot.ForT(t) or gsnapshot.SetTestNameProvider(...).")
+		}
+	}
+	if test.name == "" {
+		return test, errors.New("gsnapshot could not determine the name of the current test: the test name provider returned an empty name.")
+	}
+	return test, nil
+}
+
+/*
+ObsoleteSnapshots returns the snapshot files in the Snapshotter's Directory that it has not handed out a matcher for.
+
+This is only meaningful after a full run of the test suite - when tests are filtered or skipped their snapshots will be reported as obsolete.  With Ginkgo you can call ObsoleteSnapshots in a SynchronizedAfterSuite/AfterSuite (when running serially).
+*/
+func (s *Snapshotter) ObsoleteSnapshots() ([]string, error) {
+	return s.obsoleteSnapshots(s.Directory)
+}
+
+// RemoveObsoleteSnapshots deletes the files returned by ObsoleteSnapshots and returns their paths
+func (s *Snapshotter) RemoveObsoleteSnapshots() ([]string, error) {
+	obsolete, err := s.ObsoleteSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range obsolete {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	return obsolete, nil
+}
+
+func (s *Snapshotter) obsoleteSnapshots(dir string) ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	obsolete := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != SnapshotExtension {
+			return nil
+		}
+		if !s.used[filepath.Clean(path)] {
+			obsolete = append(obsolete, path)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{}, nil
+	}
+	sort.Strings(obsolete)
+	return obsolete, err
+}
+
+var unsafePathCharacters = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// directoryName turns a test name into a directory name.  Names that aren't filesystem-safe are sanitized and
+// suffixed with a hash of the original name so that, say, "a/b" and "a b" don't end up sharing a directory.
+func directoryName(name string) string {
+	sanitized := strings.Trim(unsafePathCharacters.ReplaceAllString(name, "_"), "._")
+	if sanitized == name {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	return sanitized + "-" + hex.EncodeToString(sum[:4])
+}
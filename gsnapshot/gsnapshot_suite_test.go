@@ -0,0 +1,13 @@
+package gsnapshot_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGsnapshot(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gsnapshot Suite")
+}
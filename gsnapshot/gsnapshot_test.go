@@ -0,0 +1,249 @@
+package gsnapshot_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gsnapshot"
+)
+
+type fakeT struct {
+	name     string
+	cleanups []func()
+	logs     []string
+}
+
+func (t *fakeT) Name() string            { return t.name }
+func (t *fakeT) Cleanup(f func())        { t.cleanups = append(t.cleanups, f) }
+func (t *fakeT) Logf(f string, a ...any) { t.logs = append(t.logs, fmt.Sprintf(f, a...)) }
+func (t *fakeT) finish() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+type Book struct {
+	Title  string
+	Author string
+	Pages  int
+}
+
+var _ = Describe("gsnapshot", func() {
+	var dir string
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	snapshotter := func(name string) *gsnapshot.Snapshotter {
+		s := gsnapshot.New(func() string { return name })
+		s.Directory = dir
+		return s
+	}
+
+	Describe("MatchSnapshot", func() {
+		It("writes missing snapshots and fails", func() {
+			success, err := snapshotter("TestBooks").MatchSnapshot().Match(Book{Title: "Les Miserables", Author: "Victor Hugo", Pages: 2783})
+			Expect(success).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("did not exist and has been written")))
+			Expect(os.ReadFile(filepath.Join(dir, "TestBooks", "1.snap"))).To(MatchJSON(`{"Title":"Les Miserables","Author":"Victor Hugo","Pages":2783}`))
+		})
+
+		It("keys snapshots by test name and call order", func() {
+			Expect(os.MkdirAll(filepath.Join(dir, "TestBooks"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "TestBooks", "1.snap"), []byte("first"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "TestBooks", "2.snap"), []byte("second"), 0644)).To(Succeed())
+
+			s := snapshotter("TestBooks")
+			Expect("first").To(s.MatchSnapshot())
+			Expect("second").To(s.MatchSnapshot())
+
+			s = snapshotter("TestBooks")
+			Expect("first").To(s.MatchSnapshot())
+			Expect("first").NotTo(s.MatchSnapshot())
+		})
+
+		It("gives tests whose names have to be sanitized distinct directories", func() {
+			snapshotter("TestBooks/with subtest").MatchSnapshot().Match("slash")
+			snapshotter("TestBooks/with_subtest").MatchSnapshot().Match("underscore")
+			snapshotter("TestBooks with subtest").MatchSnapshot().Match("space")
+			Expect(filepath.Glob(filepath.Join(dir, "TestBooks_with_subtest*", "1.snap"))).To(HaveLen(3))
+
+			Expect("slash").To(snapshotter("TestBooks/with subtest").MatchSnapshot())
+			Expect("underscore").To(snapshotter("TestBooks/with_subtest").MatchSnapshot())
+			Expect("space").To(snapshotter("TestBooks with subtest").MatchSnapshot())
+		})
+
+		It("resets the call order when the test changes", func() {
+			name := "A"
+			s := gsnapshot.New(func() string { return name })
+			s.Directory = dir
+			s.MatchSnapshot().Match("a1")
+			s.MatchSnapshot().Match("a2")
+			name = "B"
+			s.MatchSnapshot().Match("b1")
+			Expect(filepath.Join(dir, "A", "2.snap")).To(BeARegularFile())
+			Expect(filepath.Join(dir, "B", "1.snap")).To(BeARegularFile())
+			Expect(filepath.Join(dir, "B", "3.snap")).NotTo(BeAnExistingFile())
+		})
+
+		It("claims the snapshot when the matcher is created so that it can be polled", func() {
+			Expect(os.MkdirAll(filepath.Join(dir, "TestPolling"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "TestPolling", "1.snap"), []byte("3"), 0644)).To(Succeed())
+			counter := 0
+			Eventually(func() string {
+				counter += 1
+				return fmt.Sprint(counter)
+			}).WithPolling(0).Should(snapshotter("TestPolling").MatchSnapshot())
+		})
+
+		It("renders a diff when the snapshot does not match", func() {
+			s := snapshotter("TestDiff")
+			s.MatchSnapshot().Match("a\nb\nc")
+
+			matcher := snapshotter("TestDiff").MatchSnapshot()
+			Expect(matcher.Match("a\nB\nc")).To(BeFalse())
+			message := matcher.FailureMessage(nil)
+			Expect(message).To(HavePrefix("Snapshot " + filepath.Join(dir, "TestDiff", "1.snap") + " does not match.\nRun your tests with GOMEGA_UPDATE_SNAPSHOTS=true to update it."))
+			Expect(message).To(ContainSubstring("    -B\n    +b"))
+		})
+
+		It("supports custom serializers", func() {
+			s := snapshotter("TestSerializer")
+			s.Serializer = func(actual any) (string, error) {
+				return fmt.Sprintf("%v", actual), nil
+			}
+			s.MatchSnapshot().Match(Book{Title: "Dune"})
+			Expect(os.ReadFile(filepath.Join(dir, "TestSerializer", "1.snap"))).To(Equal([]byte("{Dune  0}")))
+		})
+
+		It("errors if the test name provider returns an empty name", func() {
+			_, err := snapshotter("").MatchSnapshot().Match("a")
+			Expect(err).To(MatchError(ContainSubstring("could not determine the name of the current test")))
+		})
+
+		It("keys snapshots by the name of the test passed to ForT", func() {
+			t := &fakeT{name: "TestForT"}
+			s := gsnapshot.ForT(t)
+			s.Directory = dir
+			Expect(s.MatchSnapshot().Match("a")).Error().To(MatchError(ContainSubstring("has been written")))
+			Expect(s.MatchSnapshot().Match("b")).Error().To(MatchError(ContainSubstring("has been written")))
+			Expect(filepath.Join(dir, "TestForT", "2.snap")).To(BeARegularFile())
+			t.finish()
+
+			By("starting over when the test runs again")
+			t = &fakeT{name: "TestForT"}
+			s = gsnapshot.ForT(t)
+			s.Directory = dir
+			Expect("a").To(s.MatchSnapshot())
+			Expect("b").To(s.MatchSnapshot())
+			t.finish()
+		})
+
+		Describe("without a test name provider", func() {
+			var s *gsnapshot.Snapshotter
+			BeforeEach(func() {
+				s = gsnapshot.New(nil)
+				s.Directory = dir
+			})
+
+			It("uses the name of the running Ginkgo spec", func() {
+				s.MatchSnapshot().Match("a")
+				Expect(filepath.Glob(filepath.Join(dir, "gsnapshot_MatchSnapshot_without_a_test_name_provider_uses_the_name_of_the_running_Ginkgo_spec-*", "1.snap"))).To(HaveLen(1))
+			})
+		})
+
+		Context("in update mode", func() {
+			BeforeEach(func() {
+				os.Setenv(gsnapshot.UpdateEnvVar, "true")
+				DeferCleanup(os.Unsetenv, gsnapshot.UpdateEnvVar)
+			})
+
+			It("writes snapshots and succeeds", func() {
+				Expect("original").To(snapshotter("TestUpdate").MatchSnapshot())
+				Expect("updated").To(snapshotter("TestUpdate").MatchSnapshot())
+				Expect(os.ReadFile(filepath.Join(dir, "TestUpdate", "1.snap"))).To(Equal([]byte("updated")))
+			})
+		})
+	})
+
+	Describe("obsolete snapshots", func() {
+		BeforeEach(func() {
+			for _, path := range []string{"TestA/1.snap", "TestA/2.snap", "TestB/1.snap"} {
+				Expect(os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(dir, path), []byte("x"), 0644)).To(Succeed())
+			}
+		})
+
+		It("reports snapshots that were not matched", func() {
+			name := "TestA"
+			s := gsnapshot.New(func() string { return name })
+			s.Directory = dir
+			Expect("x").To(s.MatchSnapshot())
+
+			Expect(s.ObsoleteSnapshots()).To(Equal([]string{filepath.Join(dir, "TestA", "2.snap"), filepath.Join(dir, "TestB", "1.snap")}))
+			Expect(s.RemoveObsoleteSnapshots()).To(HaveLen(2))
+			Expect(filepath.Join(dir, "TestB", "1.snap")).NotTo(BeAnExistingFile())
+			Expect(s.ObsoleteSnapshots()).To(BeEmpty())
+		})
+
+		It("returns nothing if the directory does not exist", func() {
+			s := snapshotter("TestA")
+			s.Directory = filepath.Join(dir, "nope")
+			Expect(s.ObsoleteSnapshots()).To(BeEmpty())
+		})
+
+		Describe("with ForT", func() {
+			It("logs the test's own obsolete snapshots when the test finishes", func() {
+				t := &fakeT{name: "TestA"}
+				s := gsnapshot.ForT(t)
+				s.Directory = dir
+				Expect("x").To(s.MatchSnapshot())
+				t.finish()
+				Expect(t.logs).To(HaveLen(1))
+				Expect(t.logs[0]).To(ContainSubstring(filepath.Join(dir, "TestA", "2.snap")))
+				Expect(t.logs[0]).NotTo(ContainSubstring("TestB"))
+			})
+
+			It("removes them in update mode", func() {
+				os.Setenv(gsnapshot.UpdateEnvVar, "true")
+				DeferCleanup(os.Unsetenv, gsnapshot.UpdateEnvVar)
+				t := &fakeT{name: "TestA"}
+				s := gsnapshot.ForT(t)
+				s.Directory = dir
+				Expect("x").To(s.MatchSnapshot())
+				t.finish()
+				Expect(filepath.Join(dir, "TestA", "2.snap")).NotTo(BeAnExistingFile())
+				Expect(filepath.Join(dir, "TestB", "1.snap")).To(BeAnExistingFile())
+			})
+		})
+	})
+
+	Describe("the Default snapshotter", func() {
+		BeforeEach(func() {
+			gsnapshot.Default.Directory = dir
+			gsnapshot.SetTestNameProvider(func() string { return CurrentSpecReport().FullText() })
+			DeferCleanup(func() {
+				gsnapshot.Default.Directory = gsnapshot.DefaultDirectory
+				gsnapshot.SetTestNameProvider(nil)
+			})
+		})
+
+		It("uses the registered test name provider", func() {
+			gsnapshot.MatchSnapshot().Match("hello")
+			Expect(filepath.Glob(filepath.Join(dir, "gsnapshot_the_Default_snapshotter_uses_the_registered_test_name_provider-*", "1.snap"))).To(HaveLen(1))
+		})
+	})
+})
+
+var rerunSnapshotter = gsnapshot.New(nil)
+
+var _ = Describe("rerunning a spec", func() {
+	It("starts over with the first snapshot", MustPassRepeatedly(2), func() {
+		rerunSnapshotter.Directory = GinkgoT().TempDir()
+		rerunSnapshotter.MatchSnapshot().Match("a")
+		Expect(filepath.Glob(filepath.Join(rerunSnapshotter.Directory, "*", "1.snap"))).To(HaveLen(1))
+	})
+})
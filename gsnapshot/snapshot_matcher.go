@@ -0,0 +1,96 @@
+package gsnapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/gomega/format"
+)
+
+// A Serializer turns an actual value into the text that is stored in (and compared against) a snapshot
+type Serializer func(actual any) (string, error)
+
+/*
+SerializeJSON is the default Serializer.  Strings and []byte are stored verbatim, everything else is encoded as indented JSON (which sorts map keys and is therefore stable from run to run).
+
+Note that JSON only captures exported fields - provide your own Serializer if you need more.
+*/
+func SerializeJSON(actual any) (string, error) {
+	switch x := actual.(type) {
+	case string:
+		return x, nil
+	case []byte:
+		return string(x), nil
+	}
+	encoded, err := json.MarshalIndent(actual, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("gsnapshot failed to serialize:\n%s\n%w", format.Object(actual, 1), err)
+	}
+	return string(encoded) + "\n", nil
+}
+
+type snapshotMatcher struct {
+	path       string
+	serializer Serializer
+	err        error
+
+	actual   string
+	expected string
+}
+
+func (matcher *snapshotMatcher) Match(actual any) (success bool, err error) {
+	if matcher.err != nil {
+		return false, matcher.err
+	}
+	matcher.actual, err = matcher.serializer(actual)
+	if err != nil {
+		return false, err
+	}
+
+	snapshot, err := os.ReadFile(matcher.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := matcher.write(); err != nil {
+			return false, err
+		}
+		if UpdateMode() {
+			return true, nil
+		}
+		return false, fmt.Errorf("Snapshot %s did not exist and has been written.  Review it and re-run your tests.", matcher.path)
+	} else if err != nil {
+		return false, fmt.Errorf("gsnapshot failed to read snapshot %s:\n%w", matcher.path, err)
+	}
+
+	if UpdateMode() {
+		if string(snapshot) != matcher.actual {
+			if err := matcher.write(); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	matcher.expected = string(snapshot)
+	return matcher.actual == matcher.expected, nil
+}
+
+func (matcher *snapshotMatcher) write() error {
+	if err := os.MkdirAll(filepath.Dir(matcher.path), 0755); err != nil {
+		return fmt.Errorf("gsnapshot failed to create the directory for snapshot %s:\n%w", matcher.path, err)
+	}
+	if err := os.WriteFile(matcher.path, []byte(matcher.actual), 0644); err != nil {
+		return fmt.Errorf("gsnapshot failed to write snapshot %s:\n%w", matcher.path, err)
+	}
+	return nil
+}
+
+func (matcher *snapshotMatcher) FailureMessage(_ any) (message string) {
+	return fmt.Sprintf("Snapshot %s does not match.\nRun your tests with %s=true to update it.\n\n%s", matcher.path, UpdateEnvVar, format.MessageWithDiff(matcher.actual, "to match snapshot", matcher.expected))
+}
+
+func (matcher *snapshotMatcher) NegatedFailureMessage(_ any) (message string) {
+	return fmt.Sprintf("Expected not to match snapshot %s.\n\n%s", matcher.path, format.Message(matcher.actual, "not to match snapshot", matcher.expected))
+}
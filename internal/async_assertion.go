@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -74,9 +75,11 @@ type AsyncAssertion struct {
 
 	timeoutInterval    time.Duration
 	pollingInterval    time.Duration
+	pollingStrategy    types.PollingStrategy
 	mustPassRepeatedly int
 	ctx                context.Context
 	offset             int
+	trace              bool
 	g                  *Gomega
 }
 
@@ -88,6 +91,7 @@ func NewAsyncAssertion(asyncType AsyncAssertionType, actualInput any, g *Gomega,
 		mustPassRepeatedly: mustPassRepeatedly,
 		offset:             offset,
 		ctx:                ctx,
+		trace:              g.DurationBundle.TraceAsyncAssertions,
 		g:                  g,
 	}
 
@@ -111,6 +115,7 @@ func (assertion *AsyncAssertion) WithTimeout(interval time.Duration) types.Async
 
 func (assertion *AsyncAssertion) WithPolling(interval time.Duration) types.AsyncAssertion {
 	assertion.pollingInterval = interval
+	assertion.pollingStrategy = nil
 	return assertion
 }
 
@@ -121,6 +126,12 @@ func (assertion *AsyncAssertion) Within(timeout time.Duration) types.AsyncAssert
 
 func (assertion *AsyncAssertion) ProbeEvery(interval time.Duration) types.AsyncAssertion {
 	assertion.pollingInterval = interval
+	assertion.pollingStrategy = nil
+	return assertion
+}
+
+func (assertion *AsyncAssertion) WithPollingStrategy(strategy types.PollingStrategy) types.AsyncAssertion {
+	assertion.pollingStrategy = strategy
 	return assertion
 }
 
@@ -139,6 +150,11 @@ func (assertion *AsyncAssertion) MustPassRepeatedly(count int) types.AsyncAssert
 	return assertion
 }
 
+func (assertion *AsyncAssertion) WithTrace() types.AsyncAssertion {
+	assertion.trace = true
+	return assertion
+}
+
 func (assertion *AsyncAssertion) Should(matcher types.GomegaMatcher, optionalDescription ...any) bool {
 	assertion.g.THelper()
 	vetOptionalDescription("Asynchronous assertion", optionalDescription...)
@@ -355,13 +371,22 @@ func (assertion *AsyncAssertion) afterTimeout() <-chan time.Time {
 	}
 }
 
-func (assertion *AsyncAssertion) afterPolling() <-chan time.Time {
+func (assertion *AsyncAssertion) afterPolling(attempt int) <-chan time.Time {
+	if assertion.pollingStrategy != nil {
+		return time.After(assertion.pollingStrategy.NextInterval(attempt))
+	}
 	if assertion.pollingInterval >= 0 {
 		return time.After(assertion.pollingInterval)
 	}
 	if assertion.asyncType == AsyncAssertionTypeConsistently {
+		if assertion.g.DurationBundle.ConsistentlyPollingStrategy != nil {
+			return time.After(assertion.g.DurationBundle.ConsistentlyPollingStrategy.NextInterval(attempt))
+		}
 		return time.After(assertion.g.DurationBundle.ConsistentlyPollingInterval)
 	} else {
+		if assertion.g.DurationBundle.EventuallyPollingStrategy != nil {
+			return time.After(assertion.g.DurationBundle.EventuallyPollingStrategy.NextInterval(attempt))
+		}
 		return time.After(assertion.g.DurationBundle.EventuallyPollingInterval)
 	}
 }
@@ -407,6 +432,12 @@ func (assertion *AsyncAssertion) match(matcher types.GomegaMatcher, desiredMatch
 		return false
 	}
 
+	var trace *asyncTrace
+	if assertion.trace {
+		trace = newAsyncTrace(timer)
+	}
+
+	pollStart := time.Now()
 	actual, actualErr = pollActual()
 	if actualErr == nil {
 		lastValidActual = actual
@@ -414,6 +445,9 @@ func (assertion *AsyncAssertion) match(matcher types.GomegaMatcher, desiredMatch
 		oracleMatcherSaysStop = assertion.matcherSaysStopTrying(matcher, actual)
 		matches, matcherErr = assertion.pollMatcher(matcher, actual)
 	}
+	if trace != nil {
+		trace.record(pollStart, actual, actualErr, matches, matcherErr)
+	}
 
 	renderError := func(preamble string, err error) string {
 		message := ""
@@ -481,6 +515,10 @@ func (assertion *AsyncAssertion) match(matcher types.GomegaMatcher, desiredMatch
 			}
 		}
 
+		if trace != nil {
+			message = strings.TrimSuffix(message, "\n") + "\n\n" + trace.String()
+		}
+
 		description := assertion.buildDescription(optionalDescription...)
 		return fmt.Sprintf("%s%s", description, message)
 	}
@@ -501,6 +539,8 @@ func (assertion *AsyncAssertion) match(matcher types.GomegaMatcher, desiredMatch
 
 	// Used to count the number of times in a row a step passed
 	passedRepeatedlyCount := 0
+	// Used to count the number of times the actual has been polled, for the polling strategy
+	attempts := 1
 	for {
 		var nextPoll <-chan time.Time = nil
 		var isTryAgainAfterError = false
@@ -552,11 +592,13 @@ func (assertion *AsyncAssertion) match(matcher types.GomegaMatcher, desiredMatch
 		}
 
 		if nextPoll == nil {
-			nextPoll = assertion.afterPolling()
+			nextPoll = assertion.afterPolling(attempts)
 		}
 
 		select {
 		case <-nextPoll:
+			attempts += 1
+			pollStart := time.Now()
 			a, e := pollActual()
 			lock.Lock()
 			actual, actualErr = a, e
@@ -572,6 +614,11 @@ func (assertion *AsyncAssertion) match(matcher types.GomegaMatcher, desiredMatch
 				matches, matcherErr = m, e
 				lock.Unlock()
 			}
+			if trace != nil {
+				lock.Lock()
+				trace.record(pollStart, actual, actualErr, matches, matcherErr)
+				lock.Unlock()
+			}
 		case <-contextDone:
 			err := context.Cause(assertion.ctx)
 			if err != nil && err != context.Canceled {
@@ -11,6 +11,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
 )
 
 type quickMatcher struct {
@@ -541,6 +542,165 @@ var _ = Describe("Asynchronous Assertions", func() {
 		})
 	})
 
+	Describe("polling strategies", func() {
+		var attempts []int
+		var strategy PollingStrategy
+
+		BeforeEach(func() {
+			attempts = []int{}
+			strategy = PollingStrategyFunc(func(attempt int) time.Duration {
+				attempts = append(attempts, attempt)
+				return time.Millisecond
+			})
+		})
+
+		It("asks the strategy how long to wait after each attempt", func() {
+			counter := 0
+			ig.G.Eventually(func() int {
+				counter += 1
+				return counter
+			}).WithTimeout(time.Second).WithPollingStrategy(strategy).Should(Equal(4))
+			Ω(ig.FailureMessage).Should(BeZero())
+			Ω(attempts).Should(Equal([]int{1, 2, 3}))
+		})
+
+		It("is honored by Consistently", func() {
+			ig.G.Consistently(true).WithTimeout(50 * time.Millisecond).WithPollingStrategy(PollingStrategyFunc(func(attempt int) time.Duration {
+				attempts = append(attempts, attempt)
+				return 20 * time.Millisecond
+			})).Should(BeTrue())
+			Ω(ig.FailureMessage).Should(BeZero())
+			Ω(len(attempts)).Should(BeNumerically(">=", 2))
+			Ω(attempts[:2]).Should(Equal([]int{1, 2}))
+		})
+
+		It("uses whichever of WithPolling and WithPollingStrategy was called last", func() {
+			ig.G.Eventually(false).WithTimeout(100 * time.Millisecond).WithPolling(time.Hour).WithPollingStrategy(strategy).Should(BeTrue())
+			Ω(len(attempts)).Should(BeNumerically(">", 5))
+
+			attempts = []int{}
+			ig.G.Eventually(false).WithTimeout(100 * time.Millisecond).WithPollingStrategy(strategy).WithPolling(10 * time.Millisecond).Should(BeTrue())
+			Ω(attempts).Should(BeEmpty())
+		})
+
+		It("yields to TryAgainAfter", func() {
+			counter := 0
+			ig.G.Eventually(func() error {
+				counter += 1
+				if counter == 1 {
+					return TryAgainAfter(time.Millisecond)
+				}
+				if counter < 3 {
+					return errors.New("not yet")
+				}
+				return nil
+			}).WithTimeout(time.Second).WithPollingStrategy(strategy).Should(Succeed())
+			Ω(attempts).Should(Equal([]int{2}))
+		})
+
+		Context("when a default strategy is set", func() {
+			It("is used by Eventually, in preference to the default polling interval", func() {
+				ig.G.SetDefaultEventuallyPollingInterval(time.Hour)
+				ig.G.SetDefaultEventuallyPollingStrategy(strategy)
+				counter := 0
+				ig.G.Eventually(func() int {
+					counter += 1
+					return counter
+				}).WithTimeout(time.Second).Should(Equal(3))
+				Ω(attempts).Should(Equal([]int{1, 2}))
+			})
+
+			It("is used by Consistently, in preference to the default polling interval", func() {
+				ig.G.SetDefaultConsistentlyPollingInterval(time.Hour)
+				ig.G.SetDefaultConsistentlyPollingStrategy(strategy)
+				ig.G.Consistently(true).WithTimeout(50 * time.Millisecond).Should(BeTrue())
+				Ω(len(attempts)).Should(BeNumerically(">", 5))
+			})
+
+			It("yields to an explicit polling interval", func() {
+				ig.G.SetDefaultEventuallyPollingStrategy(strategy)
+				ig.G.Eventually(false).WithTimeout(50 * time.Millisecond).WithPolling(10 * time.Millisecond).Should(BeTrue())
+				Ω(attempts).Should(BeEmpty())
+			})
+		})
+	})
+
+	Describe("tracing attempts", func() {
+		It("does not include a trace by default", func() {
+			ig.G.Eventually(func() int { return 1 }).WithTimeout(50 * time.Millisecond).WithPolling(10 * time.Millisecond).Should(Equal(2))
+			Ω(ig.FailureMessage).ShouldNot(ContainSubstring("Polling trace"))
+		})
+
+		It("includes a timeline of every attempt when WithTrace() is used", func() {
+			counter := 0
+			ig.G.Eventually(func() (int, error) {
+				counter++
+				if counter == 2 {
+					return 0, errors.New("boom\nwith details")
+				}
+				return counter, nil
+			}).WithTimeout(100 * time.Millisecond).WithPolling(20 * time.Millisecond).WithTrace().Should(BeNumerically(">", 100))
+			Ω(ig.FailureMessage).Should(HavePrefix("Timed out after"))
+			Ω(ig.FailureMessage).Should(ContainSubstring("to be >\n    <int>: 100\n\nPolling trace (%d attempts):\n", counter))
+			lines := strings.Split(ig.FailureMessage[strings.Index(ig.FailureMessage, "Polling trace"):], "\n")[1:]
+			Ω(lines).Should(HaveLen(counter))
+			Ω(lines[0]).Should(MatchRegexp(`^    #1 at 0\.000s \(took [^)]+\): did not match <int>: 1$`))
+			Ω(lines[1]).Should(MatchRegexp(`^    #2 at 0\.0\d\ds \(took [^)]+\): function returned an error: boom with details$`))
+			Ω(lines[2]).Should(MatchRegexp(`^    #3 at 0\.0\d\ds \(took [^)]+\): did not match <int>: 3$`))
+		})
+
+		It("records matches, matcher errors and long values", func() {
+			counter := 0
+			ig.G.Eventually(func() string {
+				counter++
+				switch counter {
+				case 1:
+					return MATCH
+				case 2:
+					return ERR_MATCH
+				}
+				return strings.Repeat("a", 200)
+			}).WithTimeout(50 * time.Millisecond).WithPolling(10 * time.Millisecond).WithTrace().ShouldNot(QuickMatcher(func(actual any) (bool, error) {
+				if actual == ERR_MATCH {
+					return false, TEST_MATCHER_ERR
+				}
+				return true, nil
+			}))
+			Ω(ig.FailureMessage).Should(HavePrefix("Timed out after"))
+			Ω(ig.FailureMessage).Should(ContainSubstring("Polling trace (%d attempts):", counter))
+			Ω(ig.FailureMessage).Should(ContainSubstring("#1 at 0.000s"))
+			Ω(ig.FailureMessage).Should(ContainSubstring("): matched <string>: match\n"))
+			Ω(ig.FailureMessage).Should(ContainSubstring("): matcher returned an error: spec matcher error for <string>: err match\n"))
+			Ω(ig.FailureMessage).Should(HaveSuffix("): matched <string>: " + strings.Repeat("a", 70) + "..."))
+		})
+
+		It("can be enabled for all assertions", func() {
+			ig.G.EnableAsyncAssertionTracing()
+			ig.G.Eventually(func() int { return 1 }).WithTimeout(50 * time.Millisecond).WithPolling(10 * time.Millisecond).Should(Equal(2))
+			Ω(ig.FailureMessage).Should(ContainSubstring("Polling trace"))
+
+			ig.G.DisableAsyncAssertionTracing()
+			ig.G.Eventually(func() int { return 1 }).WithTimeout(50 * time.Millisecond).WithPolling(10 * time.Millisecond).Should(Equal(2))
+			Ω(ig.FailureMessage).ShouldNot(ContainSubstring("Polling trace"))
+		})
+
+		It("elides attempts to respect format.MaxLength", func() {
+			DeferCleanup(func(maxLength int) { format.MaxLength = maxLength }, format.MaxLength)
+			format.MaxLength = 500
+			counter := 0
+			ig.G.Eventually(func() int {
+				counter++
+				return counter
+			}).WithTimeout(200 * time.Millisecond).WithPolling(time.Millisecond).WithTrace().Should(BeZero())
+			Ω(counter).Should(BeNumerically(">", 20))
+			trace := ig.FailureMessage[strings.Index(ig.FailureMessage, "Polling trace"):]
+			Ω(trace).Should(HavePrefix(fmt.Sprintf("Polling trace (%d attempts):\n    #1 at 0.000s", counter)))
+			Ω(trace).Should(MatchRegexp(`\n    \.\.\. \d+ attempts elided \(see format\.MaxLength\) \.\.\.\n`))
+			Ω(trace).Should(ContainSubstring(fmt.Sprintf("#%d at", counter)))
+			Ω(len(trace)).Should(BeNumerically("<", 700))
+		})
+	})
+
 	Describe("the passed-in actual", func() {
 		type Foo struct{ Bar string }
 
@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/gomega/format"
+)
+
+// maxTracedValueLength bounds the length of the value summary rendered for each attempt
+const maxTracedValueLength = 80
+
+type asyncAttempt struct {
+	at       time.Duration
+	duration time.Duration
+	outcome  string
+}
+
+// asyncTrace records every attempt made by an Eventually or Consistently so that the failure message can include a timeline
+type asyncTrace struct {
+	start    time.Time
+	attempts []asyncAttempt
+}
+
+func newAsyncTrace(start time.Time) *asyncTrace {
+	return &asyncTrace{start: start}
+}
+
+func (t *asyncTrace) record(pollStart time.Time, actual any, actualErr error, matches bool, matcherErr error) {
+	var outcome string
+	switch {
+	case actualErr != nil:
+		outcome = "function returned an error: " + summarizeTracedError(actualErr)
+	case matcherErr != nil:
+		outcome = "matcher returned an error: " + summarizeTracedError(matcherErr) + " for " + summarizeTracedValue(actual)
+	case matches:
+		outcome = "matched " + summarizeTracedValue(actual)
+	default:
+		outcome = "did not match " + summarizeTracedValue(actual)
+	}
+	t.attempts = append(t.attempts, asyncAttempt{
+		at:       pollStart.Sub(t.start),
+		duration: time.Since(pollStart),
+		outcome:  outcome,
+	})
+}
+
+/*
+String renders the trace as a timeline:
+
+	Polling trace (3 attempts):
+	    #1 at 0.000s (took 12µs): did not match <int>: 1
+	    #2 at 0.010s (took 9µs): did not match <int>: 2
+	    #3 at 0.021s (took 15µs): function returned an error: boom
+
+If the timeline is longer than format.MaxLength, attempts from the middle of the timeline are elided.
+*/
+func (t *asyncTrace) String() string {
+	lines := make([]string, len(t.attempts))
+	for i, attempt := range t.attempts {
+		lines[i] = fmt.Sprintf("#%d at %.3fs (took %s): %s", i+1, attempt.at.Seconds(), attempt.duration.Round(time.Microsecond), attempt.outcome)
+	}
+	return fmt.Sprintf("Polling trace (%d attempts):\n%s", len(t.attempts), format.IndentString(strings.Join(elideTracedAttempts(lines), "\n"), 1))
+}
+
+// elideTracedAttempts drops attempts from the middle of the timeline until it fits within format.MaxLength
+func elideTracedAttempts(lines []string) []string {
+	length := func(lines []string) int {
+		total := 0
+		for _, line := range lines {
+			total += len(line) + 1
+		}
+		return total
+	}
+	if format.MaxLength == 0 || length(lines) <= format.MaxLength {
+		return lines
+	}
+	head, tail := lines[:len(lines)/2], lines[len(lines)/2:]
+	for len(head)+len(tail) > 2 && length(head)+length(tail) > format.MaxLength {
+		if len(head) >= len(tail) {
+			head = head[:len(head)-1]
+		} else {
+			tail = tail[1:]
+		}
+	}
+	elided := fmt.Sprintf("... %d attempts elided (see format.MaxLength) ...", len(lines)-len(head)-len(tail))
+	return append(append(append([]string{}, head...), elided), tail...)
+}
+
+func summarizeTracedValue(value any) string {
+	return summarizeTracedString(format.Object(value, 0))
+}
+
+func summarizeTracedError(err error) string {
+	return summarizeTracedString(err.Error())
+}
+
+// summarizeTracedString collapses s onto a single line and truncates it
+func summarizeTracedString(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > maxTracedValueLength {
+		s = string(runes[:maxTracedValueLength]) + "..."
+	}
+	return s
+}
@@ -24,6 +24,13 @@ func setGlobalDurationBundle(bundle internal.DurationBundle) {
 	} else {
 		DisableDefaultTimeoutsWhenUsingContext()
 	}
+	if bundle.TraceAsyncAssertions {
+		EnableAsyncAssertionTracing()
+	} else {
+		DisableAsyncAssertionTracing()
+	}
+	SetDefaultEventuallyPollingStrategy(bundle.EventuallyPollingStrategy)
+	SetDefaultConsistentlyPollingStrategy(bundle.ConsistentlyPollingStrategy)
 }
 
 var _ = Describe("Gomega DSL", func() {
@@ -61,6 +68,7 @@ var _ = Describe("Gomega DSL", func() {
 				ConsistentlyDuration:                    3 * time.Minute,
 				ConsistentlyPollingInterval:             4 * time.Minute,
 				EnforceDefaultTimeoutsWhenUsingContexts: true,
+				TraceAsyncAssertions:                    true,
 			}
 			setGlobalDurationBundle(bundle)
 
@@ -85,6 +93,7 @@ var _ = Describe("Gomega DSL", func() {
 				ConsistentlyDuration:                    3 * time.Minute,
 				ConsistentlyPollingInterval:             4 * time.Minute,
 				EnforceDefaultTimeoutsWhenUsingContexts: true,
+				TraceAsyncAssertions:                    true,
 			}
 			setGlobalDurationBundle(bundle)
 
@@ -203,6 +212,9 @@ var _ = Describe("Gomega DSL", func() {
 				ConsistentlyDuration:                    3 * time.Minute,
 				ConsistentlyPollingInterval:             4 * time.Minute,
 				EnforceDefaultTimeoutsWhenUsingContexts: true,
+				TraceAsyncAssertions:                    true,
+				EventuallyPollingStrategy:               ExponentialBackoff(time.Millisecond, 2, time.Second),
+				ConsistentlyPollingStrategy:             LinearRamp(time.Millisecond, time.Millisecond, time.Second),
 			}
 
 			SetDefaultEventuallyTimeout(bundle.EventuallyTimeout)
@@ -210,6 +222,9 @@ var _ = Describe("Gomega DSL", func() {
 			SetDefaultConsistentlyDuration(bundle.ConsistentlyDuration)
 			SetDefaultConsistentlyPollingInterval(bundle.ConsistentlyPollingInterval)
 			EnforceDefaultTimeoutsWhenUsingContexts()
+			EnableAsyncAssertionTracing()
+			SetDefaultEventuallyPollingStrategy(bundle.EventuallyPollingStrategy)
+			SetDefaultConsistentlyPollingStrategy(bundle.ConsistentlyPollingStrategy)
 
 			Ω(Default.(*internal.Gomega).DurationBundle).Should(Equal(bundle))
 		})
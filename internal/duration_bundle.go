@@ -5,6 +5,8 @@ import (
 	"os"
 	"reflect"
 	"time"
+
+	"github.com/onsi/gomega/types"
 )
 
 type DurationBundle struct {
@@ -13,6 +15,11 @@ type DurationBundle struct {
 	ConsistentlyDuration                    time.Duration
 	ConsistentlyPollingInterval             time.Duration
 	EnforceDefaultTimeoutsWhenUsingContexts bool
+	TraceAsyncAssertions                    bool
+
+	// EventuallyPollingStrategy and ConsistentlyPollingStrategy take precedence over the corresponding polling intervals, when set
+	EventuallyPollingStrategy   types.PollingStrategy
+	ConsistentlyPollingStrategy types.PollingStrategy
 }
 
 const (
@@ -23,10 +30,13 @@ const (
 	ConsistentlyPollingIntervalEnvVarName = "GOMEGA_DEFAULT_CONSISTENTLY_POLLING_INTERVAL"
 
 	EnforceDefaultTimeoutsWhenUsingContextsEnvVarName = "GOMEGA_ENFORCE_DEFAULT_TIMEOUTS_WHEN_USING_CONTEXTS"
+
+	TraceAsyncAssertionsEnvVarName = "GOMEGA_TRACE_ASYNC_ASSERTIONS"
 )
 
 func FetchDefaultDurationBundle() DurationBundle {
 	_, EnforceDefaultTimeoutsWhenUsingContexts := os.LookupEnv(EnforceDefaultTimeoutsWhenUsingContextsEnvVarName)
+	_, TraceAsyncAssertions := os.LookupEnv(TraceAsyncAssertionsEnvVarName)
 	return DurationBundle{
 		EventuallyTimeout:         durationFromEnv(EventuallyTimeoutEnvVarName, time.Second),
 		EventuallyPollingInterval: durationFromEnv(EventuallyPollingIntervalEnvVarName, 10*time.Millisecond),
@@ -34,6 +44,7 @@ func FetchDefaultDurationBundle() DurationBundle {
 		ConsistentlyDuration:                    durationFromEnv(ConsistentlyDurationEnvVarName, 100*time.Millisecond),
 		ConsistentlyPollingInterval:             durationFromEnv(ConsistentlyPollingIntervalEnvVarName, 10*time.Millisecond),
 		EnforceDefaultTimeoutsWhenUsingContexts: EnforceDefaultTimeoutsWhenUsingContexts,
+		TraceAsyncAssertions:                    TraceAsyncAssertions,
 	}
 }
 
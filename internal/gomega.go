@@ -135,3 +135,19 @@ func (g *Gomega) EnforceDefaultTimeoutsWhenUsingContexts() {
 func (g *Gomega) DisableDefaultTimeoutsWhenUsingContext() {
 	g.DurationBundle.EnforceDefaultTimeoutsWhenUsingContexts = false
 }
+
+func (g *Gomega) EnableAsyncAssertionTracing() {
+	g.DurationBundle.TraceAsyncAssertions = true
+}
+
+func (g *Gomega) DisableAsyncAssertionTracing() {
+	g.DurationBundle.TraceAsyncAssertions = false
+}
+
+func (g *Gomega) SetDefaultEventuallyPollingStrategy(strategy types.PollingStrategy) {
+	g.DurationBundle.EventuallyPollingStrategy = strategy
+}
+
+func (g *Gomega) SetDefaultConsistentlyPollingStrategy(strategy types.PollingStrategy) {
+	g.DurationBundle.ConsistentlyPollingStrategy = strategy
+}
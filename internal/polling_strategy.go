@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/onsi/gomega/types"
+)
+
+type exponentialBackoff struct {
+	initial time.Duration
+	factor  float64
+	max     time.Duration
+}
+
+// NewExponentialBackoff returns a strategy that waits initial after the first attempt and multiplies the interval by factor
+// after every subsequent attempt.  The interval never exceeds max, unless max is zero.
+func NewExponentialBackoff(initial time.Duration, factor float64, max time.Duration) types.PollingStrategy {
+	return exponentialBackoff{initial: initial, factor: factor, max: max}
+}
+
+func (s exponentialBackoff) NextInterval(attempt int) time.Duration {
+	return capInterval(float64(s.initial)*math.Pow(s.factor, float64(attempt-1)), s.max)
+}
+
+type linearRamp struct {
+	initial time.Duration
+	step    time.Duration
+	max     time.Duration
+}
+
+// NewLinearRamp returns a strategy that waits initial after the first attempt and adds step to the interval
+// after every subsequent attempt.  The interval never exceeds max, unless max is zero.
+func NewLinearRamp(initial time.Duration, step time.Duration, max time.Duration) types.PollingStrategy {
+	return linearRamp{initial: initial, step: step, max: max}
+}
+
+func (s linearRamp) NextInterval(attempt int) time.Duration {
+	return capInterval(float64(s.initial)+float64(s.step)*float64(attempt-1), s.max)
+}
+
+type jitter struct {
+	strategy types.PollingStrategy
+	fraction float64
+}
+
+// NewJitter returns a strategy that randomly spreads the intervals computed by strategy by up to ±fraction of their duration
+func NewJitter(strategy types.PollingStrategy, fraction float64) types.PollingStrategy {
+	return jitter{strategy: strategy, fraction: fraction}
+}
+
+func (s jitter) NextInterval(attempt int) time.Duration {
+	interval := float64(s.strategy.NextInterval(attempt))
+	return capInterval(interval*(1+s.fraction*(2*rand.Float64()-1)), 0)
+}
+
+// capInterval converts interval to a duration no smaller than zero and no larger than max (unless max is zero)
+func capInterval(interval float64, max time.Duration) time.Duration {
+	if max > 0 && interval > float64(max) {
+		return max
+	}
+	if interval < 0 {
+		return 0
+	}
+	if interval > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(interval)
+}
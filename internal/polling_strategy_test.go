@@ -0,0 +1,66 @@
+package internal_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PollingStrategies", func() {
+	intervals := func(strategy PollingStrategy, n int) []time.Duration {
+		out := []time.Duration{}
+		for attempt := 1; attempt <= n; attempt++ {
+			out = append(out, strategy.NextInterval(attempt))
+		}
+		return out
+	}
+
+	Describe("ExponentialBackoff", func() {
+		It("multiplies the interval by the factor after every attempt, up to the cap", func() {
+			Ω(intervals(ExponentialBackoff(10*time.Millisecond, 2, 100*time.Millisecond), 6)).Should(Equal([]time.Duration{
+				10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 80 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond,
+			}))
+		})
+
+		It("is uncapped when the cap is zero", func() {
+			Ω(ExponentialBackoff(time.Millisecond, 10, 0).NextInterval(5)).Should(Equal(10 * time.Second))
+		})
+
+		It("does not overflow", func() {
+			Ω(ExponentialBackoff(time.Second, 10, 0).NextInterval(1000)).Should(BeNumerically(">", 0))
+		})
+	})
+
+	Describe("LinearRamp", func() {
+		It("adds the step to the interval after every attempt, up to the cap", func() {
+			Ω(intervals(LinearRamp(10*time.Millisecond, 5*time.Millisecond, 25*time.Millisecond), 5)).Should(Equal([]time.Duration{
+				10 * time.Millisecond, 15 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond, 25 * time.Millisecond,
+			}))
+		})
+
+		It("never returns a negative interval", func() {
+			Ω(LinearRamp(10*time.Millisecond, -5*time.Millisecond, 0).NextInterval(5)).Should(BeZero())
+		})
+	})
+
+	Describe("WithJitter", func() {
+		It("spreads the wrapped strategy's intervals by up to the fraction", func() {
+			strategy := WithJitter(LinearRamp(100*time.Millisecond, 100*time.Millisecond, 0), 0.1)
+			seen := map[time.Duration]bool{}
+			for i := 0; i < 100; i++ {
+				interval := strategy.NextInterval(2)
+				Ω(interval).Should(BeNumerically("~", 200*time.Millisecond, 20*time.Millisecond))
+				seen[interval] = true
+			}
+			Ω(len(seen)).Should(BeNumerically(">", 1))
+		})
+	})
+
+	Describe("PollingStrategyFunc", func() {
+		It("adapts a function", func() {
+			strategy := PollingStrategyFunc(func(attempt int) time.Duration { return time.Duration(attempt) * time.Second })
+			Ω(intervals(strategy, 3)).Should(Equal([]time.Duration{time.Second, 2 * time.Second, 3 * time.Second}))
+		})
+	})
+})
@@ -291,9 +291,143 @@ func HaveSuffix(suffix string, args ...any) types.GomegaMatcher {
 // MatchJSON succeeds if actual is a string or stringer of JSON that matches
 // the expected JSON.  The JSONs are decoded and the resulting objects are compared via
 // reflect.DeepEqual so things like key-ordering and whitespace shouldn't matter.
-func MatchJSON(json any) types.GomegaMatcher {
+//
+// MatchJSON accepts options to relax the comparison.  When options are passed in, MatchJSON's failure message additionally lists every path at which the documents differ:
+//
+//	Expect(response).To(MatchJSON(expected,
+//		JSONIgnoringPaths("$.metadata.uid", "$..createdAt"),
+//		JSONNumbersWithin(0.001),
+//		JSONUnorderedArraysAt("$.items[*].tags"),
+//		JSONWithPlaceholders(),
+//	))
+func MatchJSON(json any, options ...matchers.SemiStructuredDataOption) types.GomegaMatcher {
 	return &matchers.MatchJSONMatcher{
 		JSONToMatch: json,
+		Options:     options,
+	}
+}
+
+// MatchJSONSubset succeeds if the expected JSON document is a structural subset of the actual JSON document: every key in an expected
+// object must be present in the corresponding actual object (additional actual keys are ignored) and all values must match.
+//
+//	Expect(response).To(MatchJSONSubset(`{"status": "ready", "spec": {"replicas": 3}}`))
+//
+// By default arrays must have the same length and match element by element.  Pass in JSONUnorderedArrays to allow the expected elements to match
+// any (distinct) actual elements - additional actual elements are ignored - or JSONPrefixArrays to require the expected elements to match the leading actual elements:
+//
+//	Expect(response).To(MatchJSONSubset(`{"items": [{"name": "b"}, {"name": "a"}]}`, JSONUnorderedArrays))
+//
+// When MatchJSONSubset fails it lists every path that is missing or different.
+func MatchJSONSubset(json any, options ...matchers.SemiStructuredDataOption) types.GomegaMatcher {
+	return &matchers.MatchJSONSubsetMatcher{
+		JSONToMatch: json,
+		Options:     options,
+	}
+}
+
+// MatchYAMLSubset is the YAML equivalent of MatchJSONSubset
+func MatchYAMLSubset(yaml any, options ...matchers.SemiStructuredDataOption) types.GomegaMatcher {
+	return &matchers.MatchYAMLSubsetMatcher{
+		YAMLToMatch: yaml,
+		Options:     options,
+	}
+}
+
+// Options that control how arrays are compared by MatchJSON, MatchYAML, MatchJSONSubset and MatchYAMLSubset
+const (
+	// JSONOrderedArrays (the default) requires arrays to have the same length and to match element by element
+	JSONOrderedArrays = matchers.OrderedArrayMatching
+	// JSONUnorderedArrays allows the elements of the expected array to match the elements of the actual array in any order
+	JSONUnorderedArrays = matchers.UnorderedArrayMatching
+	// JSONPrefixArrays requires the elements of the expected array to match the leading elements of the actual array
+	JSONPrefixArrays = matchers.PrefixArrayMatching
+)
+
+// JSONIgnoringPaths is an option for MatchJSON, MatchYAML, MatchJSONSubset and MatchYAMLSubset that skips the values at the
+// passed-in JSONPath expressions in both documents:
+//
+//	Expect(response).To(MatchJSON(expected, JSONIgnoringPaths("$.id", "$.items[*].createdAt", "$..etag")))
+//
+// Negative array indices are not supported.
+func JSONIgnoringPaths(paths ...string) matchers.SemiStructuredDataOption {
+	return matchers.IgnorePathsOption{Paths: paths}
+}
+
+// JSONUnorderedArraysAt is an option for MatchJSON, MatchYAML, MatchJSONSubset and MatchYAMLSubset that compares the arrays
+// at the passed-in JSONPath expressions regardless of the order of their elements:
+//
+//	Expect(response).To(MatchJSON(expected, JSONUnorderedArraysAt("$.items", "$.items[*].tags")))
+func JSONUnorderedArraysAt(paths ...string) matchers.SemiStructuredDataOption {
+	return matchers.UnorderedArraysAtOption{Paths: paths}
+}
+
+// JSONNumbersWithin is an option for MatchJSON, MatchYAML, MatchJSONSubset and MatchYAMLSubset that considers
+// numbers equal if they differ by no more than epsilon
+func JSONNumbersWithin(epsilon float64) matchers.SemiStructuredDataOption {
+	return matchers.NumericToleranceOption{Epsilon: epsilon}
+}
+
+// JSONWithPlaceholders is an option for MatchJSON, MatchYAML, MatchJSONSubset and MatchYAMLSubset that enables the built-in placeholders.
+// Any string in the expected document that is equal to a placeholder matches any actual value of the corresponding kind:
+//
+//	<any>  <any-string>  <any-number>  <any-bool>  <any-uuid>  <any-timestamp>
+//
+// <any-timestamp> matches RFC 3339 timestamps.  For example:
+//
+//	Expect(response).To(MatchJSON(`{"id": "<any-uuid>", "createdAt": "<any-timestamp>", "name": "widget"}`, JSONWithPlaceholders()))
+func JSONWithPlaceholders() matchers.SemiStructuredDataOption {
+	return matchers.DefaultPlaceholdersOption{}
+}
+
+// JSONWithPlaceholder is an option for MatchJSON, MatchYAML, MatchJSONSubset and MatchYAMLSubset that registers a custom placeholder.
+// Any string in the expected document that is equal to token matches actual values that satisfy the passed-in matcher.
+// By default JSONWithPlaceholder uses Equal() to perform the match, however a matcher can be passed in instead:
+//
+//	Expect(response).To(MatchJSON(`{"replicas": "<positive>"}`, JSONWithPlaceholder("<positive>", BeNumerically(">", 0))))
+func JSONWithPlaceholder(token string, matcher any) matchers.SemiStructuredDataOption {
+	placeholderMatcher, ok := matcher.(types.GomegaMatcher)
+	if !ok {
+		placeholderMatcher = Equal(matcher)
+	}
+	return matchers.PlaceholderOption{Token: token, Matcher: placeholderMatcher}
+}
+
+// HaveJSONPath succeeds if the node selected by the passed-in JSONPath expression satisfies the passed-in matcher.
+// By default HaveJSONPath uses Equal() to perform the match, however a matcher can be passed in instead.
+//
+// Actual can be JSON or YAML text (a string, []byte, json.RawMessage or stringer) or already-decoded data (maps, slices and structs - struct fields
+// are addressed by their json tag, if present).  Note that JSON numbers decode to float64:
+//
+//	Expect(response).To(HaveJSONPath("$.spec.containers[0].name", "nginx"))
+//	Expect(response).To(HaveJSONPath("$.spec.replicas", BeNumerically(">=", 3)))
+//	Expect(response).To(HaveJSONPath("$['metadata']['labels']", HaveKeyWithValue("app", "web")))
+//
+// Paths that can select several nodes (wildcards like $.items[*].name, recursive descent like $..name, slices like $.items[1:3] and unions like $.items[0,2])
+// pass the (possibly empty) list of selected nodes to the matcher:
+//
+//	Expect(response).To(HaveJSONPath("$.items[*].status", HaveEach("ready")))
+//
+// Filter expressions are not supported.
+func HaveJSONPath(path string, expected any) types.GomegaMatcher {
+	return &matchers.HaveJSONPathMatcher{
+		Path:     path,
+		Expected: expected,
+	}
+}
+
+// MatchJSONSchema succeeds if actual is a JSON document that is valid according to the passed-in draft 2020-12 JSON Schema.
+// The schema can be provided inline (as a string, []byte, json.RawMessage or decoded value) or as the path to a schema file:
+//
+//	Expect(resp).To(MatchJSONSchema("schemas/widget.json"))
+//	Expect(body).To(MatchJSONSchema(`{"type": "object", "required": ["id"]}`))
+//
+// Actual can be JSON text (a string, []byte, json.RawMessage or Stringer), an *http.Response or *httptest.ResponseRecorder (whose body is validated),
+// or any other value, which is encoded to JSON before being validated.
+//
+// When MatchJSONSchema fails it lists every violation by instance path and keyword.
+func MatchJSONSchema(schema any) types.GomegaMatcher {
+	return &matchers.MatchJSONSchemaMatcher{
+		Schema: schema,
 	}
 }
 
@@ -309,9 +443,12 @@ func MatchXML(xml any) types.GomegaMatcher {
 // MatchYAML succeeds if actual is a string or stringer of YAML that matches
 // the expected YAML.  The YAML's are decoded and the resulting objects are compared via
 // reflect.DeepEqual so things like key-ordering and whitespace shouldn't matter.
-func MatchYAML(yaml any) types.GomegaMatcher {
+//
+// MatchYAML accepts the same options as MatchJSON.
+func MatchYAML(yaml any, options ...matchers.SemiStructuredDataOption) types.GomegaMatcher {
 	return &matchers.MatchYAMLMatcher{
 		YAMLToMatch: yaml,
+		Options:     options,
 	}
 }
 
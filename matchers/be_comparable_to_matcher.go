@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"reflect"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/onsi/gomega/format"
@@ -42,9 +43,65 @@ func (matcher *BeComparableToMatcher) Match(actual any) (success bool, matchErr
 }
 
 func (matcher *BeComparableToMatcher) FailureMessage(actual any) (message string) {
+	if format.UseStructuralDiff {
+		reporter := &structuralDiffReporter{}
+		cmp.Equal(actual, matcher.Expected, append(cmp.Options{cmp.Reporter(reporter)}, matcher.Options...)...)
+		if len(reporter.differences) > 0 {
+			return format.MessageWithStructuralDiff(actual, "to be comparable to", matcher.Expected, reporter.differences)
+		}
+	}
 	return fmt.Sprint("Expected object to be comparable, diff: ", cmp.Diff(actual, matcher.Expected, matcher.Options...))
 }
 
 func (matcher *BeComparableToMatcher) NegatedFailureMessage(actual any) (message string) {
 	return format.Message(actual, "not to be comparable to", matcher.Expected)
 }
+
+// structuralDiffReporter is a cmp.Reporter that collects the unequal leaves of a comparison as format.Differences
+// so that BeComparableTo honors format.UseStructuralDiff while still respecting the provided cmp.Options
+type structuralDiffReporter struct {
+	path        cmp.Path
+	differences []format.Difference
+}
+
+func (r *structuralDiffReporter) PushStep(step cmp.PathStep) {
+	r.path = append(r.path, step)
+}
+
+func (r *structuralDiffReporter) Report(result cmp.Result) {
+	if result.Equal() {
+		return
+	}
+	actual, expected := r.path.Last().Values()
+	r.differences = append(r.differences, format.Difference{Path: r.pathString(), Actual: actual, Expected: expected})
+}
+
+func (r *structuralDiffReporter) PopStep() {
+	r.path = r.path[:len(r.path)-1]
+}
+
+func (r *structuralDiffReporter) pathString() string {
+	path := ""
+	for _, step := range r.path {
+		switch step := step.(type) {
+		case cmp.StructField:
+			path += "." + step.Name()
+		case cmp.SliceIndex:
+			actualIndex, expectedIndex := step.SplitKeys()
+			if actualIndex == -1 {
+				actualIndex = expectedIndex
+			}
+			path += fmt.Sprintf("[%d]", actualIndex)
+		case cmp.MapIndex:
+			path += "[" + formatMapKey(step.Key()) + "]"
+		}
+	}
+	return path
+}
+
+func formatMapKey(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return fmt.Sprintf("%q", key.String())
+	}
+	return fmt.Sprint(key)
+}
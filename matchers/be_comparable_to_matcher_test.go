@@ -9,6 +9,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
 	. "github.com/onsi/gomega/matchers"
 )
 
@@ -152,5 +153,28 @@ var _ = Describe("BeComparableTo", func() {
 				Expect(matcherWithDifference.FailureMessage(actual)).To(ContainSubstring("Exported: \"exported field value\""))
 			})
 		})
+
+		Context("when format.UseStructuralDiff is set", func() {
+			BeforeEach(func() {
+				format.UseStructuralDiff = true
+			})
+
+			AfterEach(func() {
+				format.UseStructuralDiff = false
+			})
+
+			It("renders a path-annotated diff that honors the passed-in cmp.Options", func() {
+				actual := myCustomType{s: "abc", n: 3, f: 2.0, arr: []string{"a", "b"}}
+				expected := myCustomType{s: "foo", n: 3, f: 2.0, arr: []string{"a", "c", "d"}}
+				message := BeComparableTo(expected, cmp.AllowUnexported(myCustomType{})).FailureMessage(actual)
+				Expect(message).To(HavePrefix("Expected\n" + format.Object(actual, 1) + "\nto be comparable to\n" + format.Object(expected, 1) + "\nwith differences:\n"))
+				Expect(message).To(ContainSubstring(`.s: "abc" != "foo"`))
+				Expect(message).To(ContainSubstring(`.arr[2]: <missing> != "d"`))
+				Expect(message).NotTo(ContainSubstring(".n:"))
+
+				message = BeComparableTo(expected, cmpopts.IgnoreFields(myCustomType{}, "s"), cmp.AllowUnexported(myCustomType{})).FailureMessage(actual)
+				Expect(message).NotTo(ContainSubstring(".s:"))
+			})
+		})
 	})
 })
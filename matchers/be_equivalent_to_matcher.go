@@ -28,7 +28,7 @@ func (matcher *BeEquivalentToMatcher) Match(actual any) (success bool, err error
 }
 
 func (matcher *BeEquivalentToMatcher) FailureMessage(actual any) (message string) {
-	return format.Message(actual, "to be equivalent to", matcher.Expected)
+	return comparisonMessage(actual, "to be equivalent to", matcher.Expected)
 }
 
 func (matcher *BeEquivalentToMatcher) NegatedFailureMessage(actual any) (message string) {
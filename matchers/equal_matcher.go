@@ -34,7 +34,7 @@ func (matcher *EqualMatcher) FailureMessage(actual any) (message string) {
 		return format.MessageWithDiff(actualString, "to equal", expectedString)
 	}
 
-	return format.Message(actual, "to equal", matcher.Expected)
+	return comparisonMessage(actual, "to equal", matcher.Expected)
 }
 
 func (matcher *EqualMatcher) NegatedFailureMessage(actual any) (message string) {
@@ -6,6 +6,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
 	. "github.com/onsi/gomega/matchers"
 )
 
@@ -63,6 +64,41 @@ var _ = Describe("Equal", func() {
 			failureMessage := subject.FailureMessage(stringWithB)
 			Expect(failureMessage).To(BeEquivalentTo(expectedLongStringFailureMessage))
 		})
+
+		It("shows a line diff when both strings span multiple lines", func() {
+			subject := EqualMatcher{Expected: "first\nsecond\nthird"}
+
+			failureMessage := subject.FailureMessage("first\n2nd\nthird")
+			Expect(failureMessage).To(HaveSuffix("Diff (-actual +expected):\n    @@ -1,3 +1,3 @@\n     first\n    -2nd\n    +second\n     third"))
+		})
+
+		Context("when format.UseStructuralDiff is set", func() {
+			BeforeEach(func() {
+				format.UseStructuralDiff = true
+				DeferCleanup(func() { format.UseStructuralDiff = false })
+			})
+
+			It("lists the differences between the two values", func() {
+				failureMessage := (&EqualMatcher{Expected: map[string][]int{"a": {1, 2}}}).FailureMessage(map[string][]int{"a": {1, 3}})
+				Expect(failureMessage).To(HaveSuffix("with differences:\n    [\"a\"][1]: 3 != 2"))
+
+				failureMessage = (&BeEquivalentToMatcher{Expected: []int{1, 2}}).FailureMessage([]int{1, 3})
+				Expect(failureMessage).To(HaveSuffix("with differences:\n    [1]: 3 != 2"))
+			})
+
+			It("leaves the failure messages of order-independent matchers alone", func() {
+				failures := InterceptGomegaFailures(func() {
+					Expect([]int{1, 2, 3}).To(ConsistOf(3, 2, 4))
+					Expect([]int{1, 2, 3}).To(ContainElements(3, 4))
+					Expect(2).To(BeElementOf(3, 4))
+				})
+				Expect(failures).To(HaveLen(3))
+				for _, failure := range failures {
+					Expect(failure).NotTo(ContainSubstring("with differences"))
+					Expect(failure).NotTo(ContainSubstring("!="))
+				}
+			})
+		})
 	})
 })
 
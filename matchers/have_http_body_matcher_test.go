@@ -156,7 +156,13 @@ to equal
 to match JSON of
     <string>: {
       "other": "stuff"
-    }`))
+    }
+Diff (-actual +expected):
+    @@ -1,3 +1,3 @@
+     {
+    -  "some": "json"
+    +  "other": "stuff"
+     }`))
 			})
 		})
 	})
@@ -0,0 +1,96 @@
+package matchers
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/onsi/gomega/format"
+)
+
+type HaveJSONPathMatcher struct {
+	Path     string
+	Expected any
+
+	selected any
+	found    bool
+
+	// when the path does not resolve: the path up to the segment that failed to resolve, the value there and why
+	resolvedPath  string
+	resolvedValue any
+	unresolved    string
+}
+
+func (matcher *HaveJSONPathMatcher) expectedMatcher() omegaMatcher {
+	expectedMatcher, isMatcher := matcher.Expected.(omegaMatcher)
+	if !isMatcher {
+		expectedMatcher = &EqualMatcher{Expected: matcher.Expected}
+	}
+	return expectedMatcher
+}
+
+func (matcher *HaveJSONPathMatcher) Match(actual any) (success bool, err error) {
+	segments, err := parseJSONPath(matcher.Path)
+	if err != nil {
+		return false, fmt.Errorf("HaveJSONPath matcher was given an %w", err)
+	}
+	document, err := decodeSemiStructuredData(actual, "HaveJSONPath")
+	if err != nil {
+		return false, err
+	}
+
+	nodes, definite := evaluateJSONPath(segments, document)
+	if definite {
+		matcher.found = len(nodes) == 1
+		if !matcher.found {
+			matcher.explainUnresolvedPath(segments, document)
+			return false, nil
+		}
+		matcher.selected = nodes[0]
+	} else {
+		matcher.found = true
+		matcher.selected = nodes
+	}
+
+	return matcher.expectedMatcher().Match(matcher.selected)
+}
+
+func (matcher *HaveJSONPathMatcher) FailureMessage(actual any) (message string) {
+	if !matcher.found {
+		return format.Message(actual, fmt.Sprintf("to have JSON path '%s'", matcher.Path)) +
+			fmt.Sprintf("\nbut %s.  The value at '%s' is:\n%s", matcher.unresolved, matcher.resolvedPath, format.Object(matcher.resolvedValue, 1))
+	}
+	message = fmt.Sprintf("Value at JSON path '%s' failed to satisfy matcher.\n", matcher.Path)
+	message += matcher.expectedMatcher().FailureMessage(matcher.selected)
+	return message
+}
+
+func (matcher *HaveJSONPathMatcher) NegatedFailureMessage(actual any) (message string) {
+	message = fmt.Sprintf("Value at JSON path '%s' satisfied matcher, but should not have.\n", matcher.Path)
+	message += matcher.expectedMatcher().NegatedFailureMessage(matcher.selected)
+	return message
+}
+
+// explainUnresolvedPath walks the (definite) path segment by segment to find the first segment that fails to resolve
+func (matcher *HaveJSONPathMatcher) explainUnresolvedPath(segments []jsonPathSegment, document any) {
+	matcher.resolvedPath, matcher.resolvedValue = "$", document
+	for _, segment := range segments {
+		selected := segment.apply(matcher.resolvedValue)
+		if len(selected) == 1 {
+			matcher.resolvedPath += segment.String()
+			matcher.resolvedValue = selected[0]
+			continue
+		}
+		v := jsonPathIndirect(reflect.ValueOf(matcher.resolvedValue))
+		switch {
+		case segment.kind == jsonPathName && (v.Kind() == reflect.Map || v.Kind() == reflect.Struct):
+			matcher.unresolved = fmt.Sprintf("'%s' has no key '%s'", matcher.resolvedPath, segment.name)
+		case segment.kind == jsonPathName:
+			matcher.unresolved = fmt.Sprintf("'%s' is not an object, so '%s' can't be resolved", matcher.resolvedPath, segment)
+		case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+			matcher.unresolved = fmt.Sprintf("'%s' has %d elements, so '%s' is out of range", matcher.resolvedPath, v.Len(), segment)
+		default:
+			matcher.unresolved = fmt.Sprintf("'%s' is not an array, so '%s' can't be resolved", matcher.resolvedPath, segment)
+		}
+		return
+	}
+}
@@ -0,0 +1,127 @@
+package matchers_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/matchers"
+)
+
+var _ = Describe("HaveJSONPath", func() {
+	const document = `{
+		"kind": "Pod",
+		"metadata": {"name": "web", "labels": {"app": "web", "tier.k8s/role": "frontend"}},
+		"spec": {
+			"replicas": 3,
+			"containers": [
+				{"name": "nginx", "env": [{"name": "FOO", "value": "a"}]},
+				{"name": "sidecar", "env": []},
+				{"name": "logger", "env": [{"name": "BAR", "value": "b"}]}
+			]
+		}
+	}`
+
+	Context("with definite paths", func() {
+		It("applies the matcher to the selected node", func() {
+			Expect(document).To(HaveJSONPath("$.kind", "Pod"))
+			Expect(document).To(HaveJSONPath("kind", "Pod"))
+			Expect(document).To(HaveJSONPath("$.spec.replicas", BeNumerically("==", 3)))
+			Expect(document).To(HaveJSONPath("$.spec.containers[0].name", "nginx"))
+			Expect(document).To(HaveJSONPath("$.spec.containers[-1].name", "logger"))
+			Expect(document).To(HaveJSONPath("$['metadata'][\"labels\"]['tier.k8s/role']", "frontend"))
+			Expect(document).To(HaveJSONPath("$.metadata.labels", HaveKeyWithValue("app", "web")))
+			Expect(document).To(HaveJSONPath("$", HaveKey("spec")))
+			Expect([]byte(document)).To(HaveJSONPath("$.spec.containers[0].env[0].value", "a"))
+
+			Expect(document).NotTo(HaveJSONPath("$.kind", "Service"))
+		})
+
+		It("fails when the path does not select anything", func() {
+			Expect(document).NotTo(HaveJSONPath("$.status", BeNil()))
+			Expect(document).NotTo(HaveJSONPath("$.spec.containers[3]", BeNil()))
+		})
+	})
+
+	Context("with paths that can select several nodes", func() {
+		It("applies the matcher to the list of selected nodes", func() {
+			Expect(document).To(HaveJSONPath("$.spec.containers[*].name", Equal([]any{"nginx", "sidecar", "logger"})))
+			Expect(document).To(HaveJSONPath("$.spec.containers.*.name", HaveLen(3)))
+			Expect(document).To(HaveJSONPath("$..env[*].name", ConsistOf("FOO", "BAR")))
+			Expect(document).To(HaveJSONPath("$.spec.containers[1:].name", Equal([]any{"sidecar", "logger"})))
+			Expect(document).To(HaveJSONPath("$.spec.containers[:-2].name", Equal([]any{"nginx"})))
+			Expect(document).To(HaveJSONPath("$.spec.containers[0,2].name", Equal([]any{"nginx", "logger"})))
+			Expect(document).To(HaveJSONPath("$.metadata['name','kind']", Equal([]any{"web"})))
+			Expect(document).To(HaveJSONPath("$.spec.containers[1].env[*]", BeEmpty()))
+		})
+	})
+
+	It("works with YAML", func() {
+		Expect("spec:\n  replicas: 3\n  containers:\n  - name: nginx\n").To(HaveJSONPath("$.spec.containers[0].name", "nginx"))
+		Expect("spec:\n  replicas: 3\n").To(HaveJSONPath("$.spec.replicas", 3))
+	})
+
+	It("works with decoded values", func() {
+		type Container struct {
+			Name  string `json:"name"`
+			Image string
+		}
+		type Spec struct {
+			Containers []*Container `json:"containers"`
+		}
+		value := map[string]any{"spec": Spec{Containers: []*Container{{Name: "nginx", Image: "nginx:1.25"}}}}
+		Expect(value).To(HaveJSONPath("$.spec.containers[0].name", "nginx"))
+		Expect(value).To(HaveJSONPath("$.spec.containers[0].Image", "nginx:1.25"))
+		Expect(value).To(HaveJSONPath("$..name", Equal([]any{"nginx"})))
+		Expect(map[int]string{1: "one"}).To(HaveJSONPath("$.1", "one"))
+	})
+
+	Describe("errors", func() {
+		It("errors when the path is invalid", func() {
+			for _, path := range []string{"$.", "$[", "$[abc]", "$[1:2:3]", "$[*,1]", "$foo"} {
+				success, err := (&HaveJSONPathMatcher{Path: path, Expected: 1}).Match(document)
+				Expect(success).To(BeFalse())
+				Expect(err).To(MatchError(ContainSubstring("invalid JSON path")), path)
+			}
+		})
+
+		It("errors when the document is not valid JSON or YAML", func() {
+			success, err := (&HaveJSONPathMatcher{Path: "$.a", Expected: 1}).Match("{[}")
+			Expect(success).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring("HaveJSONPath matcher requires valid JSON or YAML")))
+		})
+	})
+
+	Describe("failure messages", func() {
+		It("shows the path and the selected value", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(document).To(HaveJSONPath("$.spec.containers[1].name", "nginx"))
+			})
+			Expect(failures).To(ConsistOf("Value at JSON path '$.spec.containers[1].name' failed to satisfy matcher.\nExpected\n    <string>: sidecar\nto equal\n    <string>: nginx"))
+		})
+
+		It("shows the document when the path does not select anything", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(`{"a": 1}`).To(HaveJSONPath("$.b", 1))
+			})
+			Expect(failures).To(ConsistOf("Expected\n    <string>: {\"a\": 1}\nto have JSON path '$.b'\nbut '$' has no key 'b'.  The value at '$' is:\n    <map[string]interface {} | len:1>: {\"a\": <float64>1}"))
+		})
+
+		It("shows where the path stops resolving and the value found there", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(document).To(HaveJSONPath("$.spec.containers[5].name", "nginx"))
+				Expect(document).To(HaveJSONPath("$.spec.containers[0].name.first", "nginx"))
+				Expect(document).To(HaveJSONPath("$.kind[0]", "nginx"))
+			})
+			Expect(failures).To(HaveLen(3))
+			Expect(failures[0]).To(ContainSubstring("to have JSON path '$.spec.containers[5].name'\nbut '$.spec.containers' has 3 elements, so '[5]' is out of range.  The value at '$.spec.containers' is:\n    <[]interface {} | len:3"))
+			Expect(failures[1]).To(HaveSuffix("but '$.spec.containers[0].name' is not an object, so '.first' can't be resolved.  The value at '$.spec.containers[0].name' is:\n    <string>: nginx"))
+			Expect(failures[2]).To(HaveSuffix("but '$.kind' is not an array, so '[0]' can't be resolved.  The value at '$.kind' is:\n    <string>: Pod"))
+		})
+
+		It("shows the path and the selected value when negated", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(document).NotTo(HaveJSONPath("$.kind", "Pod"))
+			})
+			Expect(failures).To(ConsistOf("Value at JSON path '$.kind' satisfied matcher, but should not have.\nExpected\n    <string>: Pod\nnot to equal\n    <string>: Pod"))
+		})
+	})
+})
@@ -2,6 +2,7 @@ package matchers
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/onsi/gomega/format"
 )
@@ -28,7 +29,10 @@ func (matcher *HavePrefixMatcher) prefix() string {
 }
 
 func (matcher *HavePrefixMatcher) FailureMessage(actual any) (message string) {
-	return format.Message(actual, "to have prefix", matcher.prefix())
+	message = format.Message(actual, "to have prefix", matcher.prefix())
+	actualString, _ := toString(actual)
+	prefix := matcher.prefix()
+	return message + format.LineDiff(leadingLines(actualString, strings.Count(prefix, "\n")+1), prefix)
 }
 
 func (matcher *HavePrefixMatcher) NegatedFailureMessage(actual any) (message string) {
@@ -41,6 +41,13 @@ var _ = Describe("HavePrefixMatcher", func() {
 		Expect(failuresMessages[0]).To(Equal("Expected\n    <string>: foo\nto have prefix\n    <string>: bar"))
 	})
 
+	It("shows a line diff against the leading lines when both sides span multiple lines", func() {
+		failuresMessages := InterceptGomegaFailures(func() {
+			Expect("a\nb\nc\nd").To(HavePrefix("a\nB"))
+		})
+		Expect(failuresMessages[0]).To(HaveSuffix("to have prefix\n    <string>: a\n    B\nDiff (-actual +expected):\n    @@ -1,2 +1,2 @@\n     a\n    -b\n    +B"))
+	})
+
 	It("shows negated failure message", func() {
 		failuresMessages := InterceptGomegaFailures(func() {
 			Expect("foo").ToNot(HavePrefix("fo"))
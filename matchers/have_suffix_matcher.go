@@ -2,6 +2,7 @@ package matchers
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/onsi/gomega/format"
 )
@@ -28,7 +29,10 @@ func (matcher *HaveSuffixMatcher) suffix() string {
 }
 
 func (matcher *HaveSuffixMatcher) FailureMessage(actual any) (message string) {
-	return format.Message(actual, "to have suffix", matcher.suffix())
+	message = format.Message(actual, "to have suffix", matcher.suffix())
+	actualString, _ := toString(actual)
+	suffix := matcher.suffix()
+	return message + format.LineDiff(trailingLines(actualString, strings.Count(suffix, "\n")+1), suffix)
 }
 
 func (matcher *HaveSuffixMatcher) NegatedFailureMessage(actual any) (message string) {
@@ -41,6 +41,13 @@ var _ = Describe("HaveSuffixMatcher", func() {
 		Expect(failuresMessages[0]).To(Equal("Expected\n    <string>: foo\nto have suffix\n    <string>: bar"))
 	})
 
+	It("shows a line diff against the trailing lines when both sides span multiple lines", func() {
+		failuresMessages := InterceptGomegaFailures(func() {
+			Expect("a\nb\nc\nd").To(HaveSuffix("C\nd"))
+		})
+		Expect(failuresMessages[0]).To(HaveSuffix("to have suffix\n    <string>: C\n    d\nDiff (-actual +expected):\n    @@ -1,2 +1,2 @@\n    -c\n    +C\n     d"))
+	})
+
 	It("shows negated failure message", func() {
 		failuresMessages := InterceptGomegaFailures(func() {
 			Expect("foo").ToNot(HaveSuffix("oo"))
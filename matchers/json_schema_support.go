@@ -0,0 +1,715 @@
+package matchers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxJSONSchemaRefDepth bounds the number of $refs followed without descending into the instance, guarding against $ref cycles
+const maxJSONSchemaRefDepth = 64
+
+type jsonSchemaViolation struct {
+	path    string
+	keyword string
+	message string
+}
+
+func (v jsonSchemaViolation) String() string {
+	return fmt.Sprintf("%s: %s: %s", v.path, v.keyword, v.message)
+}
+
+// jsonSchemaResult holds the violations found while validating an instance against a (sub)schema along with the
+// annotations needed by unevaluatedProperties and unevaluatedItems
+type jsonSchemaResult struct {
+	violations []jsonSchemaViolation
+	properties map[string]bool
+	items      int
+	allItems   bool
+	contained  map[int]bool
+}
+
+func newJSONSchemaResult() *jsonSchemaResult {
+	return &jsonSchemaResult{properties: map[string]bool{}, contained: map[int]bool{}}
+}
+
+func (r *jsonSchemaResult) valid() bool {
+	return len(r.violations) == 0
+}
+
+func (r *jsonSchemaResult) fail(path string, keyword string, format string, args ...any) {
+	r.violations = append(r.violations, jsonSchemaViolation{path: path, keyword: keyword, message: fmt.Sprintf(format, args...)})
+}
+
+// absorb collects the violations and annotations of a subschema applied to the same instance
+func (r *jsonSchemaResult) absorb(other *jsonSchemaResult) {
+	r.violations = append(r.violations, other.violations...)
+	r.mergeAnnotations(other)
+}
+
+func (r *jsonSchemaResult) mergeAnnotations(other *jsonSchemaResult) {
+	for property := range other.properties {
+		r.properties[property] = true
+	}
+	for index := range other.contained {
+		r.contained[index] = true
+	}
+	r.items = max(r.items, other.items)
+	r.allItems = r.allItems || other.allItems
+}
+
+/*
+jsonSchemaValidator validates decoded JSON documents against a draft 2020-12 JSON Schema.
+
+Schemas and documents must be decoded with json.Decoder.UseNumber so that numeric keywords can be evaluated exactly.
+$ref (and, approximately, $dynamicRef) can point into the schema via JSON pointers or $anchors, at other resources identified by $id,
+or at schema files on disk.  The format keyword is treated as an annotation, as the specification mandates by default.
+*/
+type jsonSchemaValidator struct {
+	root      any
+	base      string
+	resources map[string]any
+	anchors   map[string]any
+	regexps   map[string]*regexp.Regexp
+	err       error
+}
+
+func newJSONSchemaValidator(schema any, base string) *jsonSchemaValidator {
+	v := &jsonSchemaValidator{
+		root:      schema,
+		base:      base,
+		resources: map[string]any{base: schema},
+		anchors:   map[string]any{},
+		regexps:   map[string]*regexp.Regexp{},
+	}
+	v.index(schema, base)
+	return v
+}
+
+func (v *jsonSchemaValidator) validateDocument(instance any) ([]jsonSchemaViolation, error) {
+	result := v.validate(v.root, instance, "$", v.base, 0)
+	sort.SliceStable(result.violations, func(i, j int) bool {
+		return result.violations[i].path < result.violations[j].path
+	})
+	return result.violations, v.err
+}
+
+func (v *jsonSchemaValidator) error(err error) {
+	if v.err == nil {
+		v.err = err
+	}
+}
+
+// index registers the resources ($id) and anchors ($anchor, $dynamicAnchor) found in a schema
+func (v *jsonSchemaValidator) index(node any, base string) {
+	switch n := node.(type) {
+	case map[string]any:
+		if id, ok := n["$id"].(string); ok {
+			base = resolveJSONSchemaURI(base, id)
+			v.resources[base] = n
+		}
+		for _, keyword := range []string{"$anchor", "$dynamicAnchor"} {
+			if anchor, ok := n[keyword].(string); ok {
+				v.anchors[base+"#"+anchor] = n
+			}
+		}
+		for keyword, child := range n {
+			switch keyword {
+			case "enum", "const", "default", "examples":
+				// these hold data, not schemas
+			default:
+				v.index(child, base)
+			}
+		}
+	case []any:
+		for _, child := range n {
+			v.index(child, base)
+		}
+	}
+}
+
+// resolveJSONSchemaURI resolves ref against base and drops any fragment
+func resolveJSONSchemaURI(base string, ref string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	resolved := baseURL.ResolveReference(refURL)
+	resolved.Fragment, resolved.RawFragment = "", ""
+	return resolved.String()
+}
+
+func (v *jsonSchemaValidator) resolve(base string, ref string) (any, string, error) {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid $ref %q: %w", ref, err)
+	}
+	resourceURI := resolveJSONSchemaURI(base, ref)
+	resource, ok := v.resources[resourceURI]
+	if !ok {
+		resource, err = v.load(resourceURI)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	fragment := refURL.Fragment
+	switch {
+	case fragment == "":
+		return resource, resourceURI, nil
+	case strings.HasPrefix(fragment, "/"):
+		target, err := resolveJSONPointer(resource, fragment)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not resolve $ref %q: %w", ref, err)
+		}
+		return target, resourceURI, nil
+	default:
+		target, ok := v.anchors[resourceURI+"#"+fragment]
+		if !ok {
+			return nil, "", fmt.Errorf("could not resolve $ref %q: no such anchor", ref)
+		}
+		return target, resourceURI, nil
+	}
+}
+
+// load reads a schema referenced by a $ref from disk; remote schemas are not supported
+func (v *jsonSchemaValidator) load(uri string) (any, error) {
+	u, err := url.Parse(uri)
+	if err != nil || (u.Scheme != "" && u.Scheme != "file") {
+		return nil, fmt.Errorf("could not resolve $ref to %q: only local schema files are supported", uri)
+	}
+	data, err := os.ReadFile(filepath.FromSlash(u.Path))
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve $ref to %q: %w", uri, err)
+	}
+	schema, err := decodeJSONWithNumbers(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve $ref to %q: %w", uri, err)
+	}
+	v.resources[uri] = schema
+	v.index(schema, uri)
+	return schema, nil
+}
+
+func resolveJSONPointer(node any, pointer string) (any, error) {
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("%q not found", token)
+			}
+			node = child
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(n) {
+				return nil, fmt.Errorf("invalid index %q", token)
+			}
+			node = n[index]
+		default:
+			return nil, fmt.Errorf("%q not found", token)
+		}
+	}
+	return node, nil
+}
+
+func (v *jsonSchemaValidator) regexp(pattern string) *regexp.Regexp {
+	if re, ok := v.regexps[pattern]; ok {
+		return re
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		v.error(fmt.Errorf("invalid pattern %q in schema: %w", pattern, err))
+	}
+	v.regexps[pattern] = re
+	return re
+}
+
+// validateChild applies a subschema found under keyword - a `false` subschema is reported against keyword
+func (v *jsonSchemaValidator) validateChild(keyword string, schema any, instance any, path string, base string, refs int) *jsonSchemaResult {
+	if schema == false {
+		result := newJSONSchemaResult()
+		result.fail(path, keyword, "is not allowed")
+		return result
+	}
+	return v.validate(schema, instance, path, base, refs)
+}
+
+func (v *jsonSchemaValidator) validate(schema any, instance any, path string, base string, refs int) *jsonSchemaResult {
+	result := newJSONSchemaResult()
+	s, ok := schema.(map[string]any)
+	if !ok {
+		switch schema {
+		case true:
+		case false:
+			result.fail(path, "false", "no value is allowed")
+		default:
+			v.error(fmt.Errorf("invalid schema at %s: expected an object or a boolean, got %s", path, renderSemiStructuredValue(schema)))
+		}
+		return result
+	}
+	if v.err != nil {
+		return result
+	}
+	if id, ok := s["$id"].(string); ok {
+		base = resolveJSONSchemaURI(base, id)
+	}
+
+	v.validateType(s, instance, path, result)
+	v.validateNumber(s, instance, path, result)
+	v.validateString(s, instance, path, result)
+	v.validateReferences(s, instance, path, base, refs, result)
+	v.validateApplicators(s, instance, path, base, refs, result)
+	if array, ok := instance.([]any); ok {
+		v.validateArray(s, array, path, base, result)
+	}
+	if object, ok := instance.(map[string]any); ok {
+		v.validateObject(s, object, path, base, refs, result)
+	}
+	return result
+}
+
+func (v *jsonSchemaValidator) validateType(s map[string]any, instance any, path string, result *jsonSchemaResult) {
+	if t, ok := s["type"]; ok {
+		types := []string{}
+		switch t := t.(type) {
+		case string:
+			types = append(types, t)
+		case []any:
+			for _, element := range t {
+				if name, ok := element.(string); ok {
+					types = append(types, name)
+				}
+			}
+		}
+		matched := false
+		for _, name := range types {
+			if jsonSchemaHasType(instance, name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			result.fail(path, "type", "expected %s, got %s", strings.Join(types, " or "), jsonSchemaTypeOf(instance))
+		}
+	}
+
+	if enum, ok := s["enum"].([]any); ok {
+		matched := false
+		for _, candidate := range enum {
+			if jsonSchemaEqual(instance, candidate) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			result.fail(path, "enum", "%s is not one of %s", renderSemiStructuredValue(instance), renderSemiStructuredValue(enum))
+		}
+	}
+
+	if expected, ok := s["const"]; ok && !jsonSchemaEqual(instance, expected) {
+		result.fail(path, "const", "expected %s, got %s", renderSemiStructuredValue(expected), renderSemiStructuredValue(instance))
+	}
+}
+
+func (v *jsonSchemaValidator) validateNumber(s map[string]any, instance any, path string, result *jsonSchemaResult) {
+	number, ok := jsonSchemaRat(instance)
+	if !ok {
+		return
+	}
+	if multipleOf, ok := jsonSchemaRat(s["multipleOf"]); ok && multipleOf.Sign() > 0 {
+		if !new(big.Rat).Quo(number, multipleOf).IsInt() {
+			result.fail(path, "multipleOf", "%s is not a multiple of %s", instance, s["multipleOf"])
+		}
+	}
+	if maximum, ok := jsonSchemaRat(s["maximum"]); ok && number.Cmp(maximum) > 0 {
+		result.fail(path, "maximum", "%s is greater than %s", instance, s["maximum"])
+	}
+	if maximum, ok := jsonSchemaRat(s["exclusiveMaximum"]); ok && number.Cmp(maximum) >= 0 {
+		result.fail(path, "exclusiveMaximum", "%s is not less than %s", instance, s["exclusiveMaximum"])
+	}
+	if minimum, ok := jsonSchemaRat(s["minimum"]); ok && number.Cmp(minimum) < 0 {
+		result.fail(path, "minimum", "%s is less than %s", instance, s["minimum"])
+	}
+	if minimum, ok := jsonSchemaRat(s["exclusiveMinimum"]); ok && number.Cmp(minimum) <= 0 {
+		result.fail(path, "exclusiveMinimum", "%s is not greater than %s", instance, s["exclusiveMinimum"])
+	}
+}
+
+func (v *jsonSchemaValidator) validateString(s map[string]any, instance any, path string, result *jsonSchemaResult) {
+	str, ok := instance.(string)
+	if !ok {
+		return
+	}
+	length := utf8.RuneCountInString(str)
+	if maxLength, ok := jsonSchemaInt(s["maxLength"]); ok && length > maxLength {
+		result.fail(path, "maxLength", "%q is longer than %d characters", str, maxLength)
+	}
+	if minLength, ok := jsonSchemaInt(s["minLength"]); ok && length < minLength {
+		result.fail(path, "minLength", "%q is shorter than %d characters", str, minLength)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		if re := v.regexp(pattern); re != nil && !re.MatchString(str) {
+			result.fail(path, "pattern", "%q does not match %q", str, pattern)
+		}
+	}
+}
+
+func (v *jsonSchemaValidator) validateReferences(s map[string]any, instance any, path string, base string, refs int, result *jsonSchemaResult) {
+	for _, keyword := range []string{"$ref", "$dynamicRef"} {
+		ref, ok := s[keyword].(string)
+		if !ok {
+			continue
+		}
+		if refs >= maxJSONSchemaRefDepth {
+			v.error(fmt.Errorf("%s %q at %s is nested too deeply - is the schema recursive?", keyword, ref, path))
+			return
+		}
+		target, targetBase, err := v.resolve(base, ref)
+		if err != nil {
+			v.error(err)
+			return
+		}
+		result.absorb(v.validate(target, instance, path, targetBase, refs+1))
+	}
+}
+
+func (v *jsonSchemaValidator) validateApplicators(s map[string]any, instance any, path string, base string, refs int, result *jsonSchemaResult) {
+	if allOf, ok := s["allOf"].([]any); ok {
+		for _, subschema := range allOf {
+			result.absorb(v.validateChild("allOf", subschema, instance, path, base, refs))
+		}
+	}
+
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		matched := false
+		for _, subschema := range anyOf {
+			if subresult := v.validate(subschema, instance, path, base, refs); subresult.valid() {
+				matched = true
+				result.mergeAnnotations(subresult)
+			}
+		}
+		if !matched {
+			result.fail(path, "anyOf", "does not match any of the %d schemas", len(anyOf))
+		}
+	}
+
+	if oneOf, ok := s["oneOf"].([]any); ok {
+		matches := []int{}
+		var matchingResult *jsonSchemaResult
+		for i, subschema := range oneOf {
+			if subresult := v.validate(subschema, instance, path, base, refs); subresult.valid() {
+				matches = append(matches, i)
+				matchingResult = subresult
+			}
+		}
+		switch len(matches) {
+		case 0:
+			result.fail(path, "oneOf", "does not match any of the %d schemas", len(oneOf))
+		case 1:
+			result.mergeAnnotations(matchingResult)
+		default:
+			result.fail(path, "oneOf", "matches %d schemas (at indices %v), expected exactly one", len(matches), matches)
+		}
+	}
+
+	if not, ok := s["not"]; ok {
+		if v.validate(not, instance, path, base, refs).valid() {
+			result.fail(path, "not", "matches a schema it must not match")
+		}
+	}
+
+	if condition, ok := s["if"]; ok {
+		if conditionResult := v.validate(condition, instance, path, base, refs); conditionResult.valid() {
+			result.mergeAnnotations(conditionResult)
+			if then, ok := s["then"]; ok {
+				result.absorb(v.validateChild("then", then, instance, path, base, refs))
+			}
+		} else if otherwise, ok := s["else"]; ok {
+			result.absorb(v.validateChild("else", otherwise, instance, path, base, refs))
+		}
+	}
+}
+
+func (v *jsonSchemaValidator) validateArray(s map[string]any, array []any, path string, base string, result *jsonSchemaResult) {
+	elementPath := func(i int) string { return fmt.Sprintf("%s[%d]", path, i) }
+
+	prefixItems, _ := s["prefixItems"].([]any)
+	items, hasItems := s["items"]
+	if legacyItems, ok := items.([]any); ok {
+		// drafts before 2020-12 spelled prefixItems and items as items and additionalItems
+		prefixItems = legacyItems
+		items, hasItems = s["additionalItems"]
+	}
+	for i := 0; i < len(prefixItems) && i < len(array); i++ {
+		result.violations = append(result.violations, v.validateChild("prefixItems", prefixItems[i], array[i], elementPath(i), base, 0).violations...)
+	}
+	result.items = max(result.items, min(len(prefixItems), len(array)))
+	if hasItems {
+		for i := len(prefixItems); i < len(array); i++ {
+			result.violations = append(result.violations, v.validateChild("items", items, array[i], elementPath(i), base, 0).violations...)
+		}
+		result.allItems = true
+	}
+
+	if contains, ok := s["contains"]; ok {
+		matches := 0
+		for i, element := range array {
+			if v.validate(contains, element, elementPath(i), base, 0).valid() {
+				matches++
+				result.contained[i] = true
+			}
+		}
+		minContains, ok := jsonSchemaInt(s["minContains"])
+		if !ok {
+			minContains = 1
+		}
+		if matches < minContains {
+			if minContains == 1 {
+				result.fail(path, "contains", "no element matches")
+			} else {
+				result.fail(path, "minContains", "%d elements match, expected at least %d", matches, minContains)
+			}
+		}
+		if maxContains, ok := jsonSchemaInt(s["maxContains"]); ok && matches > maxContains {
+			result.fail(path, "maxContains", "%d elements match, expected at most %d", matches, maxContains)
+		}
+	}
+
+	if maxItems, ok := jsonSchemaInt(s["maxItems"]); ok && len(array) > maxItems {
+		result.fail(path, "maxItems", "has %d elements, expected at most %d", len(array), maxItems)
+	}
+	if minItems, ok := jsonSchemaInt(s["minItems"]); ok && len(array) < minItems {
+		result.fail(path, "minItems", "has %d elements, expected at least %d", len(array), minItems)
+	}
+	if s["uniqueItems"] == true {
+	uniqueness:
+		for i := range array {
+			for j := i + 1; j < len(array); j++ {
+				if jsonSchemaEqual(array[i], array[j]) {
+					result.fail(path, "uniqueItems", "elements %d and %d are equal", i, j)
+					break uniqueness
+				}
+			}
+		}
+	}
+
+	if unevaluated, ok := s["unevaluatedItems"]; ok && !result.allItems {
+		for i := result.items; i < len(array); i++ {
+			if !result.contained[i] {
+				result.violations = append(result.violations, v.validateChild("unevaluatedItems", unevaluated, array[i], elementPath(i), base, 0).violations...)
+			}
+		}
+		result.allItems = true
+	}
+}
+
+func (v *jsonSchemaValidator) validateObject(s map[string]any, object map[string]any, path string, base string, refs int, result *jsonSchemaResult) {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	properties, _ := s["properties"].(map[string]any)
+	for _, key := range keys {
+		if subschema, ok := properties[key]; ok {
+			result.violations = append(result.violations, v.validateChild("properties", subschema, object[key], semiStructuredChildPath(path, key), base, 0).violations...)
+			result.properties[key] = true
+		}
+	}
+
+	patternProperties, _ := s["patternProperties"].(map[string]any)
+	patterns := make([]string, 0, len(patternProperties))
+	for pattern := range patternProperties {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	matchedPattern := map[string]bool{}
+	for _, pattern := range patterns {
+		re := v.regexp(pattern)
+		if re == nil {
+			return
+		}
+		for _, key := range keys {
+			if re.MatchString(key) {
+				result.violations = append(result.violations, v.validateChild("patternProperties", patternProperties[pattern], object[key], semiStructuredChildPath(path, key), base, 0).violations...)
+				result.properties[key] = true
+				matchedPattern[key] = true
+			}
+		}
+	}
+
+	if additional, ok := s["additionalProperties"]; ok {
+		for _, key := range keys {
+			if _, ok := properties[key]; ok || matchedPattern[key] {
+				continue
+			}
+			result.violations = append(result.violations, v.validateChild("additionalProperties", additional, object[key], semiStructuredChildPath(path, key), base, 0).violations...)
+			result.properties[key] = true
+		}
+	}
+
+	if propertyNames, ok := s["propertyNames"]; ok {
+		for _, key := range keys {
+			if nameResult := v.validate(propertyNames, key, semiStructuredChildPath(path, key), base, 0); !nameResult.valid() {
+				result.fail(semiStructuredChildPath(path, key), "propertyNames", "invalid property name: %s", nameResult.violations[0].message)
+			}
+		}
+	}
+
+	if required, ok := s["required"].([]any); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				if _, ok := object[name]; !ok {
+					result.fail(semiStructuredChildPath(path, name), "required", "missing property")
+				}
+			}
+		}
+	}
+
+	if dependentRequired, ok := s["dependentRequired"].(map[string]any); ok {
+		for _, key := range keys {
+			dependencies, _ := dependentRequired[key].([]any)
+			for _, name := range dependencies {
+				if name, ok := name.(string); ok {
+					if _, ok := object[name]; !ok {
+						result.fail(semiStructuredChildPath(path, name), "dependentRequired", "missing property (required by %q)", key)
+					}
+				}
+			}
+		}
+	}
+
+	if dependentSchemas, ok := s["dependentSchemas"].(map[string]any); ok {
+		for _, key := range keys {
+			if subschema, ok := dependentSchemas[key]; ok {
+				result.absorb(v.validateChild("dependentSchemas", subschema, object, path, base, refs))
+			}
+		}
+	}
+
+	if maxProperties, ok := jsonSchemaInt(s["maxProperties"]); ok && len(object) > maxProperties {
+		result.fail(path, "maxProperties", "has %d properties, expected at most %d", len(object), maxProperties)
+	}
+	if minProperties, ok := jsonSchemaInt(s["minProperties"]); ok && len(object) < minProperties {
+		result.fail(path, "minProperties", "has %d properties, expected at least %d", len(object), minProperties)
+	}
+
+	if unevaluated, ok := s["unevaluatedProperties"]; ok {
+		for _, key := range keys {
+			if !result.properties[key] {
+				result.violations = append(result.violations, v.validateChild("unevaluatedProperties", unevaluated, object[key], semiStructuredChildPath(path, key), base, 0).violations...)
+				result.properties[key] = true
+			}
+		}
+	}
+}
+
+// decodeJSONWithNumbers decodes JSON, preserving numbers as json.Number
+func decodeJSONWithNumbers(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the top-level JSON value")
+	}
+	return decoded, nil
+}
+
+func jsonSchemaTypeOf(instance any) string {
+	switch instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", instance)
+}
+
+func jsonSchemaHasType(instance any, name string) bool {
+	if name == "integer" {
+		number, ok := jsonSchemaRat(instance)
+		return ok && number.IsInt()
+	}
+	return jsonSchemaTypeOf(instance) == name
+}
+
+func jsonSchemaRat(v any) (*big.Rat, bool) {
+	number, ok := v.(json.Number)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Rat).SetString(string(number))
+}
+
+func jsonSchemaInt(v any) (int, bool) {
+	number, ok := jsonSchemaRat(v)
+	if !ok || !number.IsInt() || !number.Num().IsInt64() {
+		return 0, false
+	}
+	return int(number.Num().Int64()), true
+}
+
+// jsonSchemaEqual implements JSON Schema equality, under which numbers are equal if they are mathematically equal
+func jsonSchemaEqual(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		ra, okA := jsonSchemaRat(a)
+		rb, okB := jsonSchemaRat(b)
+		return okA && okB && ra.Cmp(rb) == 0
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonSchemaEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !jsonSchemaEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	switch b.(type) {
+	case []any, map[string]any:
+		return false
+	}
+	return a == b
+}
@@ -10,7 +10,9 @@ import (
 
 type MatchJSONMatcher struct {
 	JSONToMatch      any
+	Options          []SemiStructuredDataOption
 	firstFailurePath []any
+	differences      []string
 }
 
 func (matcher *MatchJSONMatcher) Match(actual any) (success bool, err error) {
@@ -25,6 +27,15 @@ func (matcher *MatchJSONMatcher) Match(actual any) (success bool, err error) {
 	// this is guarded by prettyPrint
 	json.Unmarshal([]byte(actualString), &aval)
 	json.Unmarshal([]byte(expectedString), &eval)
+	if len(matcher.Options) > 0 {
+		comparison, err := newSemiStructuredDataComparison(false, matcher.Options)
+		if err != nil {
+			return false, err
+		}
+		comparison.compare(nil, aval, eval)
+		matcher.differences = comparison.differences
+		return len(matcher.differences) == 0, nil
+	}
 	var equal bool
 	equal, matcher.firstFailurePath = deepEqual(aval, eval)
 	return equal, nil
@@ -32,7 +43,12 @@ func (matcher *MatchJSONMatcher) Match(actual any) (success bool, err error) {
 
 func (matcher *MatchJSONMatcher) FailureMessage(actual any) (message string) {
 	actualString, expectedString, _ := matcher.prettyPrint(actual)
-	return formattedMessage(format.Message(actualString, "to match JSON of", expectedString), matcher.firstFailurePath)
+	message = format.MessageWithLineDiff(actualString, "to match JSON of", expectedString)
+	if len(matcher.Options) > 0 {
+		// the line diff includes differences the options disregard, the list of mismatched paths does not
+		return formattedDifferences(message, matcher.differences)
+	}
+	return formattedMessage(message, matcher.firstFailurePath)
 }
 
 func (matcher *MatchJSONMatcher) NegatedFailureMessage(actual any) (message string) {
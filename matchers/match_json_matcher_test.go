@@ -107,4 +107,128 @@ var _ = Describe("MatchJSONMatcher", func() {
 		})
 		Expect(failuresMessages).To(Equal([]string{"Expected\n    <string>: 1\nnot to match JSON of\n    <string>: 1"}))
 	})
+
+	Describe("options", func() {
+		const actual = `{
+			"id": "2f1c1a52-9c4f-4b36-a8a1-6f0a4c1e3b2d",
+			"createdAt": "2024-01-02T03:04:05.678Z",
+			"name": "widget",
+			"weight": 1.0000001,
+			"active": true,
+			"items": [
+				{"id": 17, "sku": "b", "tags": ["x", "y"]},
+				{"id": 18, "sku": "a", "tags": ["z"]}
+			]
+		}`
+
+		It("ignores paths", func() {
+			Expect(actual).To(MatchJSON(`{
+				"name": "widget",
+				"weight": 1.0000001,
+				"active": true,
+				"items": [{"sku": "b", "tags": ["x", "y"]}, {"sku": "a", "tags": ["z"], "extra": 1}]
+			}`, JSONIgnoringPaths("$.id", "createdAt", "$.items[*].id", "$..extra")))
+			Expect(actual).NotTo(MatchJSON(`{}`, JSONIgnoringPaths("$.id", "$.createdAt")))
+		})
+
+		It("compares numbers within a tolerance", func() {
+			Expect(`[1.0000001, 2]`).To(MatchJSON(`[1, 2.0005]`, JSONNumbersWithin(0.001)))
+			Expect(`[1.1, 2]`).NotTo(MatchJSON(`[1, 2]`, JSONNumbersWithin(0.001)))
+		})
+
+		It("compares arrays at specific paths regardless of order", func() {
+			Expect(`{"a": [1, 2], "b": {"c": [3, 4]}}`).To(MatchJSON(`{"a": [2, 1], "b": {"c": [4, 3]}}`, JSONUnorderedArraysAt("$.a", "$..c")))
+			Expect(`{"a": [1, 2], "b": [3, 4]}`).NotTo(MatchJSON(`{"a": [2, 1], "b": [4, 3]}`, JSONUnorderedArraysAt("$.a")))
+			Expect(`{"a": [1, 2]}`).NotTo(MatchJSON(`{"a": [2]}`, JSONUnorderedArraysAt("$.a")))
+			Expect(`[[1, 2], [3, 4]]`).To(MatchJSON(`[[2, 1], [4, 3]]`, JSONUnorderedArraysAt("$[*]")))
+		})
+
+		It("supports the built-in placeholders", func() {
+			Expect(actual).To(MatchJSON(`{
+				"id": "<any-uuid>",
+				"createdAt": "<any-timestamp>",
+				"name": "<any-string>",
+				"weight": "<any-number>",
+				"active": "<any-bool>",
+				"items": "<any>"
+			}`, JSONWithPlaceholders()))
+			Expect(`{"a": null}`).To(MatchJSON(`{"a": "<any>"}`, JSONWithPlaceholders()))
+			Expect(`{"a": "<any>"}`).NotTo(MatchJSON(`{"a": "<any-uuid>"}`, JSONWithPlaceholders()))
+			Expect(`{"a": "x"}`).NotTo(MatchJSON(`{"a": "<any-uuid>"}`))
+			Expect(`{"a": "yesterday"}`).NotTo(MatchJSON(`{"a": "<any-timestamp>"}`, JSONWithPlaceholders()))
+			Expect(`{"a": 3}`).NotTo(MatchJSON(`{"a": "<any-string>"}`, JSONWithPlaceholders()))
+		})
+
+		It("supports custom placeholders", func() {
+			Expect(`{"replicas": 3, "name": "web"}`).To(MatchJSON(`{"replicas": "<positive>", "name": "<name>"}`,
+				JSONWithPlaceholder("<positive>", BeNumerically(">", 0)),
+				JSONWithPlaceholder("<name>", "web"),
+			))
+			Expect(`{"replicas": 0}`).NotTo(MatchJSON(`{"replicas": "<positive>"}`, JSONWithPlaceholder("<positive>", BeNumerically(">", 0))))
+		})
+
+		It("lists every path that differs", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(actual).To(MatchJSON(`{
+					"id": "<any-uuid>",
+					"createdAt": "<any-uuid>",
+					"name": "gadget",
+					"weight": 1,
+					"active": true,
+					"items": [{"sku": "a", "tags": ["z"]}, {"sku": "c", "tags": ["y", "x"]}],
+					"color": "red"
+				}`, JSONIgnoringPaths("$.items[*].id"), JSONUnorderedArraysAt("$.items", "$.items[*].tags"), JSONNumbersWithin(1e-9), JSONWithPlaceholders()))
+			})
+			Expect(failures).To(HaveLen(1))
+			Expect(failures[0]).To(HavePrefix("Expected\n"))
+			Expect(failures[0]).To(ContainSubstring("Diff (-actual +expected)"))
+			Expect(failures[0]).To(MatchRegexp(`(?s)to match JSON of\n.*"color": "red"\n    }\nDiff \(-actual \+expected\):\n`))
+			Expect(failures[0]).To(HaveSuffix(`
+
+mismatched paths:
+    $.color: missing
+    $.createdAt: expected <any-uuid>, got "2024-01-02T03:04:05.678Z"
+    $.items[1]: no matching element for {"sku":"c","tags":["y","x"]}
+    $.name: expected "gadget", got "widget"
+    $.weight: expected 1, got 1.0000001`))
+		})
+
+		It("reports unexpected keys", func() {
+			failures := InterceptGomegaFailures(func() {
+				Expect(`{"a": 1, "b": 2}`).To(MatchJSON(`{"a": 1}`, JSONNumbersWithin(0)))
+			})
+			Expect(failures[0]).To(HaveSuffix("mismatched paths:\n    $.b: unexpected key"))
+		})
+
+		It("reports paths that can be passed back to HaveJSONPath and JSONIgnoringPaths", func() {
+			actual := `{"it's": {"a\\b": 1}, "ok": true}`
+			failures := InterceptGomegaFailures(func() {
+				Expect(actual).To(MatchJSON(`{"it's": {"a\\b": 2}, "ok": true}`, JSONNumbersWithin(0)))
+			})
+			Expect(failures[0]).To(HaveSuffix(`mismatched paths:
+    $['it\'s']['a\\b']: expected 2, got 1`))
+
+			path := `$['it\'s']['a\\b']`
+			Expect(actual).To(HaveJSONPath(path, 1.0))
+			Expect(actual).To(HaveJSONPath(`$["it's","ok"]`, Equal([]any{map[string]any{`a\b`: 1.0}, true})))
+			Expect(actual).To(MatchJSON(`{"it's": {"a\\b": 2}, "ok": true}`, JSONIgnoringPaths(path)))
+		})
+
+		It("applies to MatchYAML", func() {
+			Expect("id: 7\nname: web\ntags: [b, a]\n").To(MatchYAML("name: web\ntags: [a, b]\n", JSONIgnoringPaths("$.id"), JSONUnorderedArraysAt("$.tags")))
+			Expect("created: 2024-01-02T03:04:05Z\n").To(MatchYAML("created: <any-timestamp>\n", JSONWithPlaceholders()))
+			Expect("a: 1\nb: 2\n").NotTo(MatchYAML("a: 1.0\nb: 2\n"))
+			Expect("a: 1\nb: 2\n").NotTo(MatchYAML("a: 1.0\nb: 2\n", JSONIgnoringPaths("$.b")))
+			failures := InterceptGomegaFailures(func() {
+				Expect("a: 1\nb: 2\n").To(MatchYAML("a: 1.5\nb: 2\n", JSONNumbersWithin(0.1)))
+			})
+			Expect(failures[0]).To(HaveSuffix("to match YAML of\n    <string>: a: 1.5\n    b: 2\nDiff (-actual +expected):\n    @@ -1,2 +1,2 @@\n    -a: 1\n    +a: 1.5\n     b: 2\n\nmismatched paths:\n    $.a: expected 1.5, got 1"))
+		})
+
+		It("errors when a path is invalid", func() {
+			success, err := (&MatchJSONMatcher{JSONToMatch: `{}`, Options: []SemiStructuredDataOption{IgnorePathsOption{Paths: []string{"$.a["}}}}).Match(`{}`)
+			Expect(success).To(BeFalse())
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,176 @@
+package matchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/internal/gutil"
+)
+
+type MatchJSONSchemaMatcher struct {
+	Schema         any
+	violations     []jsonSchemaViolation
+	cachedResponse any
+	cachedBody     []byte
+}
+
+func (matcher *MatchJSONSchemaMatcher) Match(actual any) (success bool, err error) {
+	schema, base, err := matcher.schema()
+	if err != nil {
+		return false, err
+	}
+	document, err := matcher.document(actual)
+	if err != nil {
+		return false, err
+	}
+	matcher.violations, err = newJSONSchemaValidator(schema, base).validateDocument(document)
+	if err != nil {
+		return false, fmt.Errorf("MatchJSONSchema matcher could not apply the schema: %w", err)
+	}
+	return len(matcher.violations) == 0, nil
+}
+
+func (matcher *MatchJSONSchemaMatcher) FailureMessage(actual any) (message string) {
+	violations := make([]string, len(matcher.violations))
+	for i, violation := range matcher.violations {
+		violations[i] = violation.String()
+	}
+	return fmt.Sprintf("%s\n\nschema violations:\n%s", format.Message(matcher.prettyPrintDocument(actual), "to match JSON schema", matcher.describeSchema()), format.IndentString(strings.Join(violations, "\n"), 1))
+}
+
+func (matcher *MatchJSONSchemaMatcher) NegatedFailureMessage(actual any) (message string) {
+	return format.Message(matcher.prettyPrintDocument(actual), "not to match JSON schema", matcher.describeSchema())
+}
+
+// schemaIsFile reports whether the schema refers to a file rather than holding the schema inline
+func (matcher *MatchJSONSchemaMatcher) schemaIsFile() bool {
+	s, ok := matcher.Schema.(string)
+	if !ok {
+		return false
+	}
+	s = strings.TrimSpace(s)
+	return !strings.HasPrefix(s, "{") && s != "true" && s != "false"
+}
+
+// schema decodes the schema and returns it along with the base URI against which its $refs are resolved
+func (matcher *MatchJSONSchemaMatcher) schema() (any, string, error) {
+	if matcher.schemaIsFile() {
+		path, err := filepath.Abs(matcher.Schema.(string))
+		if err != nil {
+			return nil, "", fmt.Errorf("MatchJSONSchema matcher could not read schema file %q: %w", matcher.Schema, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("MatchJSONSchema matcher could not read schema file %q: %w", matcher.Schema, err)
+		}
+		schema, err := decodeJSONWithNumbers(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("MatchJSONSchema matcher requires a valid JSON schema in %q.  Underlying error: %w", matcher.Schema, err)
+		}
+		return schema, "file://" + filepath.ToSlash(path), nil
+	}
+
+	schema, err := decodeJSONDocument(matcher.Schema)
+	if err != nil {
+		return nil, "", fmt.Errorf("MatchJSONSchema matcher requires a valid JSON schema.  Got:\n%s\nUnderlying error: %w", format.Object(matcher.Schema, 1), err)
+	}
+	switch schema.(type) {
+	case map[string]any, bool:
+	default:
+		return nil, "", fmt.Errorf("MatchJSONSchema matcher requires the schema to be a JSON object or boolean.  Got:\n%s", format.Object(matcher.Schema, 1))
+	}
+	return schema, "", nil
+}
+
+func (matcher *MatchJSONSchemaMatcher) describeSchema() string {
+	if matcher.schemaIsFile() {
+		return matcher.Schema.(string)
+	}
+	schema, _, err := matcher.schema()
+	if err != nil {
+		return fmt.Sprint(matcher.Schema)
+	}
+	return prettyPrintJSONDocument(schema)
+}
+
+// document decodes actual, reading the body of HTTP responses
+func (matcher *MatchJSONSchemaMatcher) document(actual any) (any, error) {
+	var body []byte
+	switch a := actual.(type) {
+	case *http.Response:
+		data, err := matcher.body(a, a)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	case *httptest.ResponseRecorder:
+		data, err := matcher.body(a, a.Result())
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+	if body != nil {
+		actual = body
+	}
+
+	document, err := decodeJSONDocument(actual)
+	if err != nil {
+		return nil, fmt.Errorf("MatchJSONSchema matcher requires a valid JSON document.  Got:\n%s\nUnderlying error: %w", format.Object(actual, 1), err)
+	}
+	return document, nil
+}
+
+// body returns the body of an HTTP response. It is cached because once we read it in Match()
+// the Reader is closed and it is not readable again in FailureMessage()
+func (matcher *MatchJSONSchemaMatcher) body(actual any, response *http.Response) ([]byte, error) {
+	if matcher.cachedResponse == actual && matcher.cachedBody != nil {
+		return matcher.cachedBody, nil
+	}
+	matcher.cachedResponse, matcher.cachedBody = actual, []byte{}
+	if response.Body != nil {
+		defer response.Body.Close()
+		body, err := gutil.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading response body: %w", err)
+		}
+		matcher.cachedBody = body
+	}
+	return matcher.cachedBody, nil
+}
+
+func (matcher *MatchJSONSchemaMatcher) prettyPrintDocument(actual any) string {
+	document, err := matcher.document(actual)
+	if err != nil {
+		return fmt.Sprint(actual)
+	}
+	return prettyPrintJSONDocument(document)
+}
+
+// decodeJSONDocument decodes JSON text (a string, []byte, json.RawMessage or Stringer).  Other values are treated as
+// already-decoded data and are normalized by round-tripping them through encoding/json.
+func decodeJSONDocument(v any) (any, error) {
+	text, ok := toString(v)
+	if !ok {
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		text = string(encoded)
+	}
+	return decodeJSONWithNumbers([]byte(text))
+}
+
+func prettyPrintJSONDocument(document any) string {
+	encoded, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Sprint(document)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,173 @@
+package matchers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/internal/gutil"
+	. "github.com/onsi/gomega/matchers"
+)
+
+var _ = Describe("MatchJSONSchemaMatcher", func() {
+	const widgetSchema = `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["id", "name"],
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"name": {"type": "string", "minLength": 1, "maxLength": 8},
+			"price": {"type": "number", "multipleOf": 0.01, "exclusiveMinimum": 0},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+			"status": {"enum": ["active", "retired"]}
+		},
+		"additionalProperties": false
+	}`
+
+	It("succeeds when the document satisfies the schema", func() {
+		Expect(`{"id": 1, "name": "sprocket", "price": 19.99, "tags": ["a", "b"], "status": "active"}`).To(MatchJSONSchema(widgetSchema))
+		Expect([]byte(`{"id": 2.0, "name": "cog"}`)).To(MatchJSONSchema(widgetSchema))
+		Expect(`{"id": 0, "name": "cog"}`).NotTo(MatchJSONSchema(widgetSchema))
+		Expect(`"anything"`).To(MatchJSONSchema(`true`))
+		Expect(`"anything"`).NotTo(MatchJSONSchema(`false`))
+	})
+
+	It("lists every violation by instance path and keyword", func() {
+		failures := InterceptGomegaFailures(func() {
+			Expect(`{"id": 1.5, "name": "a very long name", "price": 1.001, "tags": ["a", "a", 3], "status": "gone", "color": "red"}`).To(MatchJSONSchema(widgetSchema))
+		})
+		Expect(failures).To(HaveLen(1))
+		Expect(failures[0]).To(HavePrefix("Expected\n"))
+		Expect(failures[0]).To(ContainSubstring("to match JSON schema\n"))
+		Expect(failures[0]).To(HaveSuffix(`
+
+schema violations:
+    $.color: additionalProperties: is not allowed
+    $.id: type: expected integer, got number
+    $.name: maxLength: "a very long name" is longer than 8 characters
+    $.price: multipleOf: 1.001 is not a multiple of 0.01
+    $.status: enum: "gone" is not one of ["active","retired"]
+    $.tags: uniqueItems: elements 0 and 1 are equal
+    $.tags[2]: type: expected string, got number`))
+	})
+
+	It("reports missing required properties", func() {
+		failures := InterceptGomegaFailures(func() {
+			Expect(`{}`).To(MatchJSONSchema(widgetSchema))
+		})
+		Expect(failures[0]).To(HaveSuffix("$.id: required: missing property\n    $.name: required: missing property"))
+	})
+
+	Describe("the actual value", func() {
+		It("can be an HTTP response", func() {
+			resp := &http.Response{Body: gutil.NopCloser(strings.NewReader(`{"id": 1, "name": "cog"}`))}
+			Expect(resp).To(MatchJSONSchema(widgetSchema))
+
+			recorder := httptest.NewRecorder()
+			recorder.WriteString(`{"id": "1"}`)
+			failures := InterceptGomegaFailures(func() {
+				Expect(recorder).To(MatchJSONSchema(widgetSchema))
+			})
+			Expect(failures[0]).To(ContainSubstring(`"id": "1"`))
+			Expect(failures[0]).To(ContainSubstring("$.id: type: expected integer, got string"))
+		})
+
+		It("can be decoded data", func() {
+			type widget struct {
+				ID   int    `json:"id"`
+				Name string `json:"name"`
+			}
+			Expect(widget{ID: 3, Name: "cog"}).To(MatchJSONSchema(widgetSchema))
+			Expect(map[string]any{"id": 3}).NotTo(MatchJSONSchema(widgetSchema))
+		})
+	})
+
+	Describe("the schema", func() {
+		var dir string
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "widget.json"), []byte(`{
+				"type": "object",
+				"properties": {"parts": {"type": "array", "items": {"$ref": "part.json"}}}
+			}`), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "part.json"), []byte(`{
+				"type": "object",
+				"properties": {"sku": {"$ref": "#/$defs/sku"}},
+				"$defs": {"sku": {"type": "string", "pattern": "^[A-Z]{3}-\\d+$"}}
+			}`), 0o644)).To(Succeed())
+		})
+
+		It("can be read from a file, following relative $refs", func() {
+			Expect(`{"parts": [{"sku": "ABC-1"}]}`).To(MatchJSONSchema(filepath.Join(dir, "widget.json")))
+			failures := InterceptGomegaFailures(func() {
+				Expect(`{"parts": [{"sku": "ABC-1"}, {"sku": "abc"}]}`).To(MatchJSONSchema(filepath.Join(dir, "widget.json")))
+			})
+			Expect(failures[0]).To(ContainSubstring("to match JSON schema\n    <string>: " + filepath.Join(dir, "widget.json")))
+			Expect(failures[0]).To(HaveSuffix(`$.parts[1].sku: pattern: "abc" does not match "^[A-Z]{3}-\\d+$"`))
+		})
+
+		It("can be decoded data", func() {
+			Expect(`[1, 2]`).To(MatchJSONSchema(map[string]any{"type": "array", "maxItems": 2}))
+			Expect(`[1, 2, 3]`).NotTo(MatchJSONSchema(map[string]any{"type": "array", "maxItems": 2}))
+		})
+
+		It("errors when it is invalid", func() {
+			for _, schema := range []any{`{"type": `, `[1]`, filepath.Join(dir, "missing.json"), `{"pattern": "("}`, `{"$ref": "#/$defs/missing"}`, `{"$ref": "https://example.com/schema.json"}`, `{"$ref": "#"}`} {
+				success, err := (&MatchJSONSchemaMatcher{Schema: schema}).Match(`"a"`)
+				Expect(success).To(BeFalse())
+				Expect(err).To(HaveOccurred(), "%v", schema)
+			}
+		})
+	})
+
+	It("errors when the document is not valid JSON", func() {
+		success, err := (&MatchJSONSchemaMatcher{Schema: `true`}).Match(`{`)
+		Expect(success).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("requires a valid JSON document")))
+	})
+
+	DescribeTable("draft 2020-12 keywords",
+		func(schema string, valid string, invalid string, violation string) {
+			Expect(valid).To(MatchJSONSchema(schema))
+			failures := InterceptGomegaFailures(func() {
+				Expect(invalid).To(MatchJSONSchema(schema))
+			})
+			Expect(failures).To(HaveLen(1))
+			Expect(failures[0]).To(HaveSuffix("schema violations:\n    " + violation))
+		},
+		Entry("type (multiple)", `{"type": ["string", "null"]}`, `null`, `1`, "$: type: expected string or null, got number"),
+		Entry("const", `{"const": {"a": [1]}}`, `{"a": [1.0]}`, `{"a": [2]}`, `$: const: expected {"a":[1]}, got {"a":[2]}`),
+		Entry("maximum", `{"maximum": 3}`, `3`, `3.5`, "$: maximum: 3.5 is greater than 3"),
+		Entry("exclusiveMaximum", `{"exclusiveMaximum": 3}`, `2.9`, `3`, "$: exclusiveMaximum: 3 is not less than 3"),
+		Entry("minimum", `{"minimum": 3}`, `3`, `2`, "$: minimum: 2 is less than 3"),
+		Entry("exclusiveMinimum", `{"exclusiveMinimum": 3}`, `4`, `3`, "$: exclusiveMinimum: 3 is not greater than 3"),
+		Entry("minLength", `{"minLength": 2}`, `"日本"`, `"日"`, `$: minLength: "日" is shorter than 2 characters`),
+		Entry("prefixItems and items", `{"prefixItems": [{"type": "string"}], "items": {"type": "integer"}}`, `["a", 1, 2]`, `["a", 1, "b"]`, "$[2]: type: expected integer, got string"),
+		Entry("items: false", `{"prefixItems": [{}], "items": false}`, `[1]`, `[1, 2]`, "$[1]: items: is not allowed"),
+		Entry("contains", `{"contains": {"const": 1}}`, `[2, 1]`, `[2]`, "$: contains: no element matches"),
+		Entry("minContains", `{"contains": {"const": 1}, "minContains": 2}`, `[1, 1]`, `[1, 2]`, "$: minContains: 1 elements match, expected at least 2"),
+		Entry("maxContains", `{"contains": {"const": 1}, "maxContains": 1}`, `[1, 2]`, `[1, 1]`, "$: maxContains: 2 elements match, expected at most 1"),
+		Entry("minItems", `{"minItems": 1}`, `[1]`, `[]`, "$: minItems: has 0 elements, expected at least 1"),
+		Entry("unevaluatedItems", `{"prefixItems": [{}], "contains": {"type": "string"}, "unevaluatedItems": false}`, `[1, "a"]`, `[1, "a", 2]`, "$[2]: unevaluatedItems: is not allowed"),
+		Entry("patternProperties", `{"patternProperties": {"^x-": {"type": "string"}}, "additionalProperties": false}`, `{"x-a": "b"}`, `{"x-a": 1}`, "$.x-a: type: expected string, got number"),
+		Entry("propertyNames", `{"propertyNames": {"maxLength": 3}}`, `{"abc": 1}`, `{"abcd": 1}`, `$.abcd: propertyNames: invalid property name: "abcd" is longer than 3 characters`),
+		Entry("dependentRequired", `{"dependentRequired": {"a": ["b"]}}`, `{"a": 1, "b": 2}`, `{"a": 1}`, `$.b: dependentRequired: missing property (required by "a")`),
+		Entry("dependentSchemas", `{"dependentSchemas": {"a": {"required": ["b"]}}}`, `{"b": 1}`, `{"a": 1}`, "$.b: required: missing property"),
+		Entry("maxProperties", `{"maxProperties": 1}`, `{"a": 1}`, `{"a": 1, "b": 2}`, "$: maxProperties: has 2 properties, expected at most 1"),
+		Entry("minProperties", `{"minProperties": 1}`, `{"a": 1}`, `{}`, "$: minProperties: has 0 properties, expected at least 1"),
+		Entry("allOf", `{"allOf": [{"type": "integer"}, {"minimum": 2}]}`, `2`, `1`, "$: minimum: 1 is less than 2"),
+		Entry("anyOf", `{"anyOf": [{"type": "integer"}, {"type": "string"}]}`, `"a"`, `null`, "$: anyOf: does not match any of the 2 schemas"),
+		Entry("oneOf", `{"oneOf": [{"type": "integer"}, {"minimum": 2}]}`, `1`, `2`, "$: oneOf: matches 2 schemas (at indices [0 1]), expected exactly one"),
+		Entry("not", `{"not": {"type": "string"}}`, `1`, `"a"`, "$: not: matches a schema it must not match"),
+		Entry("if/then/else", `{"if": {"type": "string"}, "then": {"minLength": 1}, "else": {"type": "integer"}}`, `"a"`, `1.5`, "$: type: expected integer, got number"),
+		Entry("unevaluatedProperties", `{"properties": {"a": {}}, "anyOf": [{"properties": {"b": {}}}], "unevaluatedProperties": false}`, `{"a": 1, "b": 2}`, `{"a": 1, "c": 3}`, "$.c: unevaluatedProperties: is not allowed"),
+		Entry("$ref to $defs", `{"$defs": {"positive": {"exclusiveMinimum": 0}}, "items": {"$ref": "#/$defs/positive"}}`, `[1]`, `[1, 0]`, "$[1]: exclusiveMinimum: 0 is not greater than 0"),
+		Entry("$ref to an $anchor", `{"$defs": {"name": {"$anchor": "name", "type": "string"}}, "properties": {"n": {"$ref": "#name"}}}`, `{"n": "a"}`, `{"n": 1}`, "$.n: type: expected string, got number"),
+		Entry("$ref to an $id", `{"$id": "https://example.com/root.json", "$defs": {"s": {"$id": "string.json", "type": "string"}}, "items": {"$ref": "string.json"}}`, `["a"]`, `[1]`, "$[0]: type: expected string, got number"),
+		Entry("recursive $ref", `{"type": "object", "properties": {"child": {"$ref": "#"}}, "required": ["name"]}`, `{"name": "a", "child": {"name": "b"}}`, `{"name": "a", "child": {"child": {"name": "c"}}}`, "$.child.name: required: missing property"),
+	)
+})
@@ -0,0 +1,45 @@
+package matchers
+
+import (
+	"encoding/json"
+
+	"github.com/onsi/gomega/format"
+)
+
+type MatchJSONSubsetMatcher struct {
+	JSONToMatch any
+	Options     []SemiStructuredDataOption
+	differences []string
+}
+
+func (matcher *MatchJSONSubsetMatcher) Match(actual any) (success bool, err error) {
+	actualString, expectedString, err := (&MatchJSONMatcher{JSONToMatch: matcher.JSONToMatch}).prettyPrint(actual)
+	if err != nil {
+		return false, err
+	}
+
+	var aval any
+	var eval any
+
+	// this is guarded by prettyPrint
+	json.Unmarshal([]byte(actualString), &aval)
+	json.Unmarshal([]byte(expectedString), &eval)
+
+	comparison, err := newSemiStructuredDataComparison(true, matcher.Options)
+	if err != nil {
+		return false, err
+	}
+	comparison.compare(nil, aval, eval)
+	matcher.differences = comparison.differences
+	return len(matcher.differences) == 0, nil
+}
+
+func (matcher *MatchJSONSubsetMatcher) FailureMessage(actual any) (message string) {
+	actualString, expectedString, _ := (&MatchJSONMatcher{JSONToMatch: matcher.JSONToMatch}).prettyPrint(actual)
+	return formattedDifferences(format.Message(actualString, "to contain JSON subset", expectedString), matcher.differences)
+}
+
+func (matcher *MatchJSONSubsetMatcher) NegatedFailureMessage(actual any) (message string) {
+	actualString, expectedString, _ := (&MatchJSONMatcher{JSONToMatch: matcher.JSONToMatch}).prettyPrint(actual)
+	return format.Message(actualString, "not to contain JSON subset", expectedString)
+}
@@ -0,0 +1,129 @@
+package matchers_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/matchers"
+)
+
+var _ = Describe("MatchJSONSubsetMatcher", func() {
+	const actual = `{
+		"status": "ready",
+		"spec": {"replicas": 3, "paused": false, "labels": {"app": "web", "tier.k8s/role": "frontend"}},
+		"items": [{"name": "a", "size": 1}, {"name": "b", "size": 2}, {"name": "c", "size": 3}],
+		"nothing": null
+	}`
+
+	It("succeeds when the expected document is a subset of the actual document", func() {
+		Expect(actual).To(MatchJSONSubset(`{}`))
+		Expect(actual).To(MatchJSONSubset(`{"status": "ready"}`))
+		Expect(actual).To(MatchJSONSubset(`{"spec": {"labels": {"app": "web"}}, "nothing": null}`))
+		Expect(actual).To(MatchJSONSubset(`{"items": [{"name": "a"}, {"name": "b"}, {"size": 3}]}`))
+		Expect([]byte(`[1, 2]`)).To(MatchJSONSubset(`[1, 2]`))
+
+		Expect(actual).NotTo(MatchJSONSubset(`{"status": "pending"}`))
+		Expect(actual).NotTo(MatchJSONSubset(`{"missing": true}`))
+		Expect(actual).NotTo(MatchJSONSubset(`{"nothing": {}}`))
+	})
+
+	Describe("array handling", func() {
+		It("requires arrays to match element by element by default", func() {
+			Expect(actual).NotTo(MatchJSONSubset(`{"items": [{"name": "a"}, {"name": "b"}]}`))
+			Expect(actual).NotTo(MatchJSONSubset(`{"items": [{"name": "b"}, {"name": "a"}, {"name": "c"}]}`))
+			Expect(actual).To(MatchJSONSubset(`{"items": [{"name": "a"}, {"name": "b"}, {"name": "c"}]}`, JSONOrderedArrays))
+		})
+
+		It("can match arrays in any order", func() {
+			Expect(actual).To(MatchJSONSubset(`{"items": [{"name": "c"}, {"size": 1}]}`, JSONUnorderedArrays))
+			Expect(actual).NotTo(MatchJSONSubset(`{"items": [{"name": "c"}, {"size": 3}]}`, JSONUnorderedArrays))
+			Expect(actual).NotTo(MatchJSONSubset(`{"items": [{}, {}, {}, {}]}`, JSONUnorderedArrays))
+		})
+
+		It("can match a prefix of the actual array", func() {
+			Expect(actual).To(MatchJSONSubset(`{"items": [{"name": "a"}, {"size": 2}]}`, JSONPrefixArrays))
+			Expect(actual).NotTo(MatchJSONSubset(`{"items": [{"name": "b"}]}`, JSONPrefixArrays))
+		})
+	})
+
+	It("compares YAML documents", func() {
+		yaml := "status: ready\nspec:\n  replicas: 3\n  labels:\n    app: web\nitems:\n- name: a\n- name: b\n"
+		Expect(yaml).To(MatchYAMLSubset("spec:\n  replicas: 3\n"))
+		Expect(yaml).To(MatchYAMLSubset("items:\n- name: b\n", JSONUnorderedArrays))
+		Expect(yaml).NotTo(MatchYAMLSubset("spec:\n  replicas: 4\n"))
+	})
+
+	It("compares numbers the way MatchYAML does", func() {
+		yaml := "replicas: 3\nstatus: ready\n"
+		Expect(yaml).NotTo(MatchYAML("replicas: 3.0\nstatus: ready\n"))
+		Expect(yaml).NotTo(MatchYAMLSubset("replicas: 3.0\n"))
+		Expect(yaml).NotTo(MatchYAMLSubset("replicas: 3.0\n", JSONIgnoringPaths("$.status")))
+		Expect(yaml).To(MatchYAMLSubset("replicas: 3.0\n", JSONNumbersWithin(0)))
+
+		failures := InterceptGomegaFailures(func() {
+			Expect(yaml).To(MatchYAMLSubset("replicas: 3.0\n"))
+		})
+		Expect(failures[0]).To(HaveSuffix("$.replicas: expected 3 (float64), got 3 (int)"))
+	})
+
+	It("lists every path that is missing or different", func() {
+		failures := InterceptGomegaFailures(func() {
+			Expect(actual).To(MatchJSONSubset(`{
+				"status": "pending",
+				"spec": {"replicas": "3", "labels": {"tier.k8s/role": "backend", "team": "x"}, "paused": []},
+				"items": [{"name": "a"}, {"name": "z"}],
+				"nothing": 1
+			}`, JSONUnorderedArrays))
+		})
+		Expect(failures).To(HaveLen(1))
+		Expect(failures[0]).To(HavePrefix("Expected\n"))
+		Expect(failures[0]).To(ContainSubstring("to contain JSON subset\n"))
+		Expect(failures[0]).To(HaveSuffix(`
+
+mismatched paths:
+    $.items[1]: no matching element for {"name":"z"}
+    $.nothing: expected 1, got null
+    $.spec.labels.team: missing
+    $.spec.labels['tier.k8s/role']: expected "backend", got "frontend"
+    $.spec.paused: expected an array, got false
+    $.spec.replicas: expected "3", got 3
+    $.status: expected "pending", got "ready"`))
+	})
+
+	It("reports array length mismatches", func() {
+		failures := InterceptGomegaFailures(func() {
+			Expect(`{"a": [1, 2, 3]}`).To(MatchJSONSubset(`{"a": [1, 2]}`))
+			Expect(`{"a": [1]}`).To(MatchJSONSubset(`{"a": [1, 2]}`, JSONPrefixArrays))
+			Expect(`{"a": {"b": 1}}`).To(MatchJSONSubset(`{"a": [1, 2]}`))
+		})
+		Expect(failures[0]).To(HaveSuffix("$.a: expected 2 elements, got 3"))
+		Expect(failures[1]).To(HaveSuffix("$.a: expected at least 2 elements, got 1"))
+		Expect(failures[2]).To(HaveSuffix(`$.a: expected an array, got an object {"b":1}`))
+	})
+
+	It("errors on invalid input", func() {
+		success, err := (&MatchJSONSubsetMatcher{JSONToMatch: `{}`}).Match(`{`)
+		Expect(success).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+
+		success, err = (&MatchJSONSubsetMatcher{JSONToMatch: `{`}).Match(`{}`)
+		Expect(success).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+
+		success, err = (&MatchYAMLSubsetMatcher{YAMLToMatch: "a: 1"}).Match(":\n\t-")
+		Expect(success).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+
+		success, err = (&MatchYAMLSubsetMatcher{YAMLToMatch: 3}).Match("a: 1")
+		Expect(success).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("has a negated failure message", func() {
+		failures := InterceptGomegaFailures(func() {
+			Expect(`{"a": 1}`).NotTo(MatchJSONSubset(`{"a": 1}`))
+			Expect("a: 1").NotTo(MatchYAMLSubset("a: 1"))
+		})
+		Expect(failures[0]).To(ContainSubstring("not to contain JSON subset"))
+		Expect(failures[1]).To(ContainSubstring("not to contain YAML subset"))
+	})
+})
@@ -10,7 +10,9 @@ import (
 
 type MatchYAMLMatcher struct {
 	YAMLToMatch      any
+	Options          []SemiStructuredDataOption
 	firstFailurePath []any
+	differences      []string
 }
 
 func (matcher *MatchYAMLMatcher) Match(actual any) (success bool, err error) {
@@ -29,6 +31,15 @@ func (matcher *MatchYAMLMatcher) Match(actual any) (success bool, err error) {
 		return false, fmt.Errorf("Expected '%s' should be valid YAML, but it is not.\nUnderlying error:%s", expectedString, err)
 	}
 
+	if len(matcher.Options) > 0 {
+		comparison, err := newSemiStructuredDataComparison(false, matcher.Options)
+		if err != nil {
+			return false, err
+		}
+		comparison.compare(nil, aval, eval)
+		matcher.differences = comparison.differences
+		return len(matcher.differences) == 0, nil
+	}
 	var equal bool
 	equal, matcher.firstFailurePath = deepEqual(aval, eval)
 	return equal, nil
@@ -36,7 +47,12 @@ func (matcher *MatchYAMLMatcher) Match(actual any) (success bool, err error) {
 
 func (matcher *MatchYAMLMatcher) FailureMessage(actual any) (message string) {
 	actualString, expectedString, _ := matcher.toNormalisedStrings(actual)
-	return formattedMessage(format.Message(actualString, "to match YAML of", expectedString), matcher.firstFailurePath)
+	message = format.MessageWithLineDiff(actualString, "to match YAML of", expectedString)
+	if len(matcher.Options) > 0 {
+		// the line diff includes differences the options disregard, the list of mismatched paths does not
+		return formattedDifferences(message, matcher.differences)
+	}
+	return formattedMessage(message, matcher.firstFailurePath)
 }
 
 func (matcher *MatchYAMLMatcher) NegatedFailureMessage(actual any) (message string) {
@@ -0,0 +1,49 @@
+package matchers
+
+import (
+	"fmt"
+
+	"github.com/onsi/gomega/format"
+	"go.yaml.in/yaml/v3"
+)
+
+type MatchYAMLSubsetMatcher struct {
+	YAMLToMatch any
+	Options     []SemiStructuredDataOption
+	differences []string
+}
+
+func (matcher *MatchYAMLSubsetMatcher) Match(actual any) (success bool, err error) {
+	actualString, expectedString, err := (&MatchYAMLMatcher{YAMLToMatch: matcher.YAMLToMatch}).toStrings(actual)
+	if err != nil {
+		return false, err
+	}
+
+	var aval any
+	var eval any
+
+	if err := yaml.Unmarshal([]byte(actualString), &aval); err != nil {
+		return false, fmt.Errorf("Actual '%s' should be valid YAML, but it is not.\nUnderlying error:%s", actualString, err)
+	}
+	if err := yaml.Unmarshal([]byte(expectedString), &eval); err != nil {
+		return false, fmt.Errorf("Expected '%s' should be valid YAML, but it is not.\nUnderlying error:%s", expectedString, err)
+	}
+
+	comparison, err := newSemiStructuredDataComparison(true, matcher.Options)
+	if err != nil {
+		return false, err
+	}
+	comparison.compare(nil, aval, eval)
+	matcher.differences = comparison.differences
+	return len(matcher.differences) == 0, nil
+}
+
+func (matcher *MatchYAMLSubsetMatcher) FailureMessage(actual any) (message string) {
+	actualString, expectedString, _ := (&MatchYAMLMatcher{YAMLToMatch: matcher.YAMLToMatch}).toNormalisedStrings(actual)
+	return formattedDifferences(format.Message(actualString, "to contain YAML subset", expectedString), matcher.differences)
+}
+
+func (matcher *MatchYAMLSubsetMatcher) NegatedFailureMessage(actual any) (message string) {
+	actualString, expectedString, _ := (&MatchYAMLMatcher{YAMLToMatch: matcher.YAMLToMatch}).toNormalisedStrings(actual)
+	return format.Message(actualString, "not to contain YAML subset", expectedString)
+}
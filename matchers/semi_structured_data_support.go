@@ -3,9 +3,20 @@
 package matchers
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/matchers/support/goraph/bipartitegraph"
+	"github.com/onsi/gomega/types"
+	"go.yaml.in/yaml/v3"
 )
 
 func formattedMessage(comparisonMessage string, failurePath []any) string {
@@ -92,3 +103,760 @@ func deepEqual(a any, b any) (bool, []any) {
 		return a == b, errorPath
 	}
 }
+
+// decodeSemiStructuredData decodes JSON or YAML text into generic values.  Values that are not text are returned as-is
+// so that already-decoded data (maps, slices, structs...) can be navigated directly.
+func decodeSemiStructuredData(actual any, matcherName string) (any, error) {
+	switch actual.(type) {
+	case string, []byte, json.RawMessage, fmt.Stringer:
+	default:
+		return actual, nil
+	}
+	text, _ := toString(actual)
+	var decoded any
+	if err := json.Unmarshal([]byte(text), &decoded); err == nil {
+		return decoded, nil
+	}
+	if err := yaml.Unmarshal([]byte(text), &decoded); err != nil {
+		return nil, fmt.Errorf("%s matcher requires valid JSON or YAML.  Got:\n%s\nUnderlying error:%s", matcherName, format.Object(actual, 1), err)
+	}
+	return decoded, nil
+}
+
+type jsonPathSelectorKind int
+
+const (
+	jsonPathName jsonPathSelectorKind = iota
+	jsonPathIndex
+	jsonPathWildcard
+	jsonPathSlice
+	jsonPathUnion
+)
+
+type jsonPathSegment struct {
+	recursive bool
+	kind      jsonPathSelectorKind
+	name      string
+	index     int
+	start     *int
+	end       *int
+	union     []jsonPathSegment
+}
+
+// String renders definite (name and index) segments the way they appear in a path, e.g. `.name`, `['a b']` or `[0]`
+func (s jsonPathSegment) String() string {
+	prefix := ""
+	if s.recursive {
+		prefix = "."
+	}
+	switch s.kind {
+	case jsonPathName:
+		return prefix + semiStructuredChildPath("", s.name)
+	case jsonPathIndex:
+		return prefix + fmt.Sprintf("[%d]", s.index)
+	}
+	return prefix + "[...]"
+}
+
+func (s jsonPathSegment) isDefinite() bool {
+	return !s.recursive && (s.kind == jsonPathName || s.kind == jsonPathIndex)
+}
+
+/*
+parseJSONPath parses the subset of JSONPath supported by HaveJSONPath:
+
+	$.name  $['name']  $[0]  $[-1]  $[*]  $.*  $..name  $[1:3]  $[0,2]  $['a','b']
+
+The leading $ is optional.
+*/
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	rest := strings.TrimSpace(path)
+	if strings.HasPrefix(rest, "$") {
+		rest = rest[1:]
+	} else if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	segments := []jsonPathSegment{}
+	for rest != "" {
+		recursive := false
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				break
+			}
+			fallthrough
+		case rest[0] == '.':
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			if name == "" {
+				return nil, fmt.Errorf("invalid JSON path %q: expected a name after '.'", path)
+			}
+			if name == "*" {
+				segments = append(segments, jsonPathSegment{recursive: recursive, kind: jsonPathWildcard})
+			} else {
+				segments = append(segments, jsonPathSegment{recursive: recursive, kind: jsonPathName, name: name})
+			}
+			continue
+		case rest[0] != '[':
+			return nil, fmt.Errorf("invalid JSON path %q: unexpected %q", path, rest)
+		}
+
+		end := closingBracket(rest)
+		if end == -1 {
+			return nil, fmt.Errorf("invalid JSON path %q: unterminated '['", path)
+		}
+		segment, err := parseJSONPathBracket(rest[1:end])
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON path %q: %w", path, err)
+		}
+		segment.recursive = recursive
+		segments = append(segments, segment)
+		rest = rest[end+1:]
+	}
+	return segments, nil
+}
+
+func closingBracket(s string) int {
+	var quote rune
+	escaped := false
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && r == '\\':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func parseJSONPathBracket(selector string) (jsonPathSegment, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "*" {
+		return jsonPathSegment{kind: jsonPathWildcard}, nil
+	}
+
+	parts := splitJSONPathUnion(selector)
+	if len(parts) > 1 {
+		union := jsonPathSegment{kind: jsonPathUnion}
+		for _, part := range parts {
+			segment, err := parseJSONPathBracket(part)
+			if err != nil {
+				return jsonPathSegment{}, err
+			}
+			if segment.kind != jsonPathName && segment.kind != jsonPathIndex {
+				return jsonPathSegment{}, fmt.Errorf("unions may only contain names and indices, got %q", part)
+			}
+			union.union = append(union.union, segment)
+		}
+		return union, nil
+	}
+
+	if len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0] {
+		return jsonPathSegment{kind: jsonPathName, name: unescapeJSONPathName(selector[1 : len(selector)-1])}, nil
+	}
+
+	if strings.Contains(selector, ":") {
+		bounds := strings.Split(selector, ":")
+		if len(bounds) != 2 {
+			return jsonPathSegment{}, fmt.Errorf("slices must be of the form [start:end], got %q", selector)
+		}
+		segment := jsonPathSegment{kind: jsonPathSlice}
+		for i, bound := range bounds {
+			bound = strings.TrimSpace(bound)
+			if bound == "" {
+				continue
+			}
+			n, err := strconv.Atoi(bound)
+			if err != nil {
+				return jsonPathSegment{}, fmt.Errorf("invalid slice bound %q", bound)
+			}
+			if i == 0 {
+				segment.start = &n
+			} else {
+				segment.end = &n
+			}
+		}
+		return segment, nil
+	}
+
+	n, err := strconv.Atoi(selector)
+	if err != nil {
+		return jsonPathSegment{}, fmt.Errorf("invalid selector [%s]", selector)
+	}
+	return jsonPathSegment{kind: jsonPathIndex, index: n}, nil
+}
+
+// unescapeJSONPathName drops the backslashes that escape quotes and backslashes in quoted names
+func unescapeJSONPathName(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func splitJSONPathUnion(s string) []string {
+	parts := []string{}
+	var quote rune
+	escaped := false
+	start := 0
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && r == '\\':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ',':
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+// evaluateJSONPath returns the nodes selected by segments and whether the path is definite (i.e. can select at most one node)
+func evaluateJSONPath(segments []jsonPathSegment, root any) ([]any, bool) {
+	definite := true
+	nodes := []any{root}
+	for _, segment := range segments {
+		definite = definite && segment.isDefinite()
+		selected := []any{}
+		for _, node := range nodes {
+			if segment.recursive {
+				for _, descendant := range jsonPathDescendants(node) {
+					selected = append(selected, segment.apply(descendant)...)
+				}
+			} else {
+				selected = append(selected, segment.apply(node)...)
+			}
+		}
+		nodes = selected
+	}
+	return nodes, definite
+}
+
+func (s jsonPathSegment) apply(node any) []any {
+	v := jsonPathIndirect(reflect.ValueOf(node))
+	switch s.kind {
+	case jsonPathName:
+		if child, ok := jsonPathChild(v, s.name); ok {
+			return []any{child}
+		}
+	case jsonPathIndex:
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			i := s.index
+			if i < 0 {
+				i += v.Len()
+			}
+			if i >= 0 && i < v.Len() {
+				return []any{v.Index(i).Interface()}
+			}
+		}
+	case jsonPathWildcard:
+		return jsonPathChildren(v)
+	case jsonPathSlice:
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			start, end := 0, v.Len()
+			if s.start != nil {
+				start = *s.start
+			}
+			if s.end != nil {
+				end = *s.end
+			}
+			if start < 0 {
+				start += v.Len()
+			}
+			if end < 0 {
+				end += v.Len()
+			}
+			start, end = max(start, 0), min(end, v.Len())
+			selected := []any{}
+			for i := start; i < end; i++ {
+				selected = append(selected, v.Index(i).Interface())
+			}
+			return selected
+		}
+	case jsonPathUnion:
+		selected := []any{}
+		for _, segment := range s.union {
+			selected = append(selected, segment.apply(node)...)
+		}
+		return selected
+	}
+	return []any{}
+}
+
+func jsonPathIndirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+func jsonPathChild(v reflect.Value, name string) (any, bool) {
+	switch v.Kind() {
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			if fmt.Sprint(key.Interface()) == name {
+				return v.MapIndex(key).Interface(), true
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			if t.Field(i).IsExported() && jsonPathFieldName(t.Field(i)) == name {
+				return v.Field(i).Interface(), true
+			}
+		}
+	}
+	return nil, false
+}
+
+func jsonPathChildren(v reflect.Value) []any {
+	children := []any{}
+	switch v.Kind() {
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface()) })
+		for _, key := range keys {
+			children = append(children, v.MapIndex(key).Interface())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			children = append(children, v.Index(i).Interface())
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			if t.Field(i).IsExported() && jsonPathFieldName(t.Field(i)) != "-" {
+				children = append(children, v.Field(i).Interface())
+			}
+		}
+	}
+	return children
+}
+
+// jsonPathDescendants returns node and all of its descendants in document order
+func jsonPathDescendants(node any) []any {
+	descendants := []any{node}
+	for _, child := range jsonPathChildren(jsonPathIndirect(reflect.ValueOf(node))) {
+		descendants = append(descendants, jsonPathDescendants(child)...)
+	}
+	return descendants
+}
+
+func jsonPathFieldName(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// ArrayMatching controls how arrays in semi-structured documents are compared
+type ArrayMatching int
+
+const (
+	// OrderedArrayMatching requires arrays to have the same length and to match element by element
+	OrderedArrayMatching ArrayMatching = iota
+	// UnorderedArrayMatching allows the elements of the expected array to match the elements of the actual array in any order
+	UnorderedArrayMatching
+	// PrefixArrayMatching requires the elements of the expected array to match the leading elements of the actual array
+	PrefixArrayMatching
+)
+
+func (a ArrayMatching) configure(comparison *semiStructuredDataComparison) error {
+	comparison.arrays = a
+	return nil
+}
+
+// SemiStructuredDataOption configures how semi-structured (JSON and YAML) documents are compared
+type SemiStructuredDataOption interface {
+	configure(*semiStructuredDataComparison) error
+}
+
+// IgnorePathsOption skips the values at the given JSONPath expressions (e.g. `$.metadata.uid` or `$..createdAt`) in both documents
+type IgnorePathsOption struct {
+	Paths []string
+}
+
+func (o IgnorePathsOption) configure(comparison *semiStructuredDataComparison) error {
+	paths, err := parseJSONPaths(o.Paths)
+	comparison.ignoredPaths = append(comparison.ignoredPaths, paths...)
+	return err
+}
+
+// UnorderedArraysAtOption compares the arrays at the given JSONPath expressions (e.g. `$.items[*].tags`) regardless of order
+type UnorderedArraysAtOption struct {
+	Paths []string
+}
+
+func (o UnorderedArraysAtOption) configure(comparison *semiStructuredDataComparison) error {
+	paths, err := parseJSONPaths(o.Paths)
+	comparison.unorderedPaths = append(comparison.unorderedPaths, paths...)
+	return err
+}
+
+// NumericToleranceOption considers numbers equal if they differ by no more than Epsilon.  Numbers are compared by value, so
+// an integer and a float (which YAML distinguishes) are equal if they are close enough.
+type NumericToleranceOption struct {
+	Epsilon float64
+}
+
+func (o NumericToleranceOption) configure(comparison *semiStructuredDataComparison) error {
+	comparison.numericTolerance = true
+	comparison.epsilon = o.Epsilon
+	return nil
+}
+
+// PlaceholderOption makes any string in the expected document equal to Token match the actual value iff Matcher does
+type PlaceholderOption struct {
+	Token   string
+	Matcher types.GomegaMatcher
+}
+
+func (o PlaceholderOption) configure(comparison *semiStructuredDataComparison) error {
+	if comparison.placeholders == nil {
+		comparison.placeholders = map[string]types.GomegaMatcher{}
+	}
+	comparison.placeholders[o.Token] = o.Matcher
+	return nil
+}
+
+// DefaultPlaceholdersOption enables the built-in placeholder tokens: <any>, <any-string>, <any-number>, <any-bool>,
+// <any-uuid> and <any-timestamp> (an RFC 3339 timestamp)
+type DefaultPlaceholdersOption struct{}
+
+var uuidPlaceholderRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var defaultPlaceholders = map[string]func(any) bool{
+	"<any>":        func(any) bool { return true },
+	"<any-string>": isString,
+	"<any-number>": isNumber,
+	"<any-bool>":   isBool,
+	"<any-uuid>": func(v any) bool {
+		s, ok := v.(string)
+		return ok && uuidPlaceholderRegexp.MatchString(s)
+	},
+	"<any-timestamp>": func(v any) bool {
+		if _, ok := v.(time.Time); ok {
+			return true
+		}
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	},
+}
+
+func (o DefaultPlaceholdersOption) configure(comparison *semiStructuredDataComparison) error {
+	for token, predicate := range defaultPlaceholders {
+		PlaceholderOption{Token: token, Matcher: NewSatisfyMatcher(predicate)}.configure(comparison)
+	}
+	return nil
+}
+
+// semiStructuredDataComparison walks two decoded documents and records every path at which they differ
+type semiStructuredDataComparison struct {
+	subset           bool
+	arrays           ArrayMatching
+	ignoredPaths     [][]jsonPathSegment
+	unorderedPaths   [][]jsonPathSegment
+	numericTolerance bool
+	epsilon          float64
+	placeholders     map[string]types.GomegaMatcher
+	differences      []string
+}
+
+func newSemiStructuredDataComparison(subset bool, options []SemiStructuredDataOption) (*semiStructuredDataComparison, error) {
+	comparison := &semiStructuredDataComparison{subset: subset}
+	for _, option := range options {
+		if err := option.configure(comparison); err != nil {
+			return nil, err
+		}
+	}
+	return comparison, nil
+}
+
+func parseJSONPaths(paths []string) ([][]jsonPathSegment, error) {
+	parsed := make([][]jsonPathSegment, len(paths))
+	for i, path := range paths {
+		segments, err := parseJSONPath(path)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = segments
+	}
+	return parsed, nil
+}
+
+// semiStructuredPath is the location of a value within a document: a sequence of object keys (strings) and array indices (ints)
+type semiStructuredPath []any
+
+func (p semiStructuredPath) child(key any) semiStructuredPath {
+	return append(p[:len(p):len(p)], key)
+}
+
+func (p semiStructuredPath) String() string {
+	rendered := "$"
+	for _, key := range p {
+		switch key := key.(type) {
+		case int:
+			rendered += fmt.Sprintf("[%d]", key)
+		case string:
+			rendered = semiStructuredChildPath(rendered, key)
+		}
+	}
+	return rendered
+}
+
+// matches reports whether the path is selected by the parsed JSONPath expression
+func (p semiStructuredPath) matches(segments []jsonPathSegment) bool {
+	if len(segments) == 0 {
+		return len(p) == 0
+	}
+	if segments[0].recursive {
+		for i := range p {
+			if segments[0].selects(p[i]) && p[i+1:].matches(segments[1:]) {
+				return true
+			}
+		}
+		return false
+	}
+	return len(p) > 0 && segments[0].selects(p[0]) && p[1:].matches(segments[1:])
+}
+
+func (p semiStructuredPath) matchesAny(paths [][]jsonPathSegment) bool {
+	for _, segments := range paths {
+		if p.matches(segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// selects reports whether a single (non-recursive) JSONPath selector selects key.  Negative indices are not supported.
+func (s jsonPathSegment) selects(key any) bool {
+	switch s.kind {
+	case jsonPathWildcard:
+		return true
+	case jsonPathName:
+		return key == s.name
+	case jsonPathIndex:
+		return key == s.index
+	case jsonPathSlice:
+		index, ok := key.(int)
+		return ok && (s.start == nil || index >= *s.start) && (s.end == nil || index < *s.end)
+	case jsonPathUnion:
+		for _, selector := range s.union {
+			if selector.selects(key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *semiStructuredDataComparison) fork() *semiStructuredDataComparison {
+	forked := *c
+	forked.differences = nil
+	return &forked
+}
+
+func (c *semiStructuredDataComparison) report(path semiStructuredPath, format string, args ...any) {
+	c.differences = append(c.differences, path.String()+": "+fmt.Sprintf(format, args...))
+}
+
+func (c *semiStructuredDataComparison) compare(path semiStructuredPath, actual, expected any) {
+	if path.matchesAny(c.ignoredPaths) {
+		return
+	}
+	if token, ok := expected.(string); ok {
+		if matcher, ok := c.placeholders[token]; ok {
+			if success, err := matcher.Match(actual); !success || err != nil {
+				c.report(path, "expected %s, got %s", token, describeSemiStructuredValue(actual))
+			}
+			return
+		}
+	}
+	actualValue, expectedValue := reflect.ValueOf(actual), reflect.ValueOf(expected)
+	switch expectedValue.Kind() {
+	case reflect.Map:
+		if actualValue.Kind() != reflect.Map {
+			c.report(path, "expected an object, got %s", describeSemiStructuredValue(actual))
+			return
+		}
+		c.compareObjects(path, actualValue, expectedValue)
+	case reflect.Slice:
+		if actualValue.Kind() != reflect.Slice {
+			c.report(path, "expected an array, got %s", describeSemiStructuredValue(actual))
+			return
+		}
+		c.compareArrays(path, actualValue, expectedValue)
+	default:
+		if !c.scalarsEqual(actual, expected) {
+			renderedExpected, renderedActual := renderSemiStructuredValue(expected), describeSemiStructuredValue(actual)
+			if renderedExpected == renderedActual {
+				// e.g. YAML's 3 and 3.0
+				renderedExpected, renderedActual = fmt.Sprintf("%s (%T)", renderedExpected, expected), fmt.Sprintf("%s (%T)", renderedActual, actual)
+			}
+			c.report(path, "expected %s, got %s", renderedExpected, renderedActual)
+		}
+	}
+}
+
+func (c *semiStructuredDataComparison) compareObjects(path semiStructuredPath, actual, expected reflect.Value) {
+	actualKeys := semiStructuredKeys(actual)
+	expectedKeys := semiStructuredKeys(expected)
+	for _, key := range sortedKeys(expectedKeys) {
+		actualKey, ok := actualKeys[key]
+		if !ok {
+			if !path.child(key).matchesAny(c.ignoredPaths) {
+				c.report(path.child(key), "missing")
+			}
+			continue
+		}
+		c.compare(path.child(key), actual.MapIndex(actualKey).Interface(), expected.MapIndex(expectedKeys[key]).Interface())
+	}
+	if c.subset {
+		return
+	}
+	for _, key := range sortedKeys(actualKeys) {
+		if _, ok := expectedKeys[key]; !ok && !path.child(key).matchesAny(c.ignoredPaths) {
+			c.report(path.child(key), "unexpected key")
+		}
+	}
+}
+
+func (c *semiStructuredDataComparison) compareArrays(path semiStructuredPath, actual, expected reflect.Value) {
+	arrays := c.arrays
+	if arrays == OrderedArrayMatching && path.matchesAny(c.unorderedPaths) {
+		arrays = UnorderedArrayMatching
+	}
+	// subset comparisons (and PrefixArrayMatching) tolerate additional elements in the actual array
+	lengthsMustMatch := arrays == OrderedArrayMatching || (arrays == UnorderedArrayMatching && !c.subset)
+	if lengthsMustMatch && actual.Len() != expected.Len() {
+		c.report(path, "expected %d elements, got %d", expected.Len(), actual.Len())
+		return
+	}
+	if !lengthsMustMatch && actual.Len() < expected.Len() {
+		c.report(path, "expected at least %d elements, got %d", expected.Len(), actual.Len())
+		return
+	}
+
+	if arrays != UnorderedArrayMatching {
+		for i := range expected.Len() {
+			c.compare(path.child(i), actual.Index(i).Interface(), expected.Index(i).Interface())
+		}
+		return
+	}
+
+	expectedIndices, actualIndices := make([]any, expected.Len()), make([]any, actual.Len())
+	for i := range expectedIndices {
+		expectedIndices[i] = i
+	}
+	for i := range actualIndices {
+		actualIndices[i] = i
+	}
+	graph, _ := bipartitegraph.NewBipartiteGraph(expectedIndices, actualIndices, func(e, a any) (bool, error) {
+		elementComparison := c.fork()
+		elementComparison.compare(path.child(a.(int)), actual.Index(a.(int)).Interface(), expected.Index(e.(int)).Interface())
+		return len(elementComparison.differences) == 0, nil
+	})
+	unmatched, _ := graph.FreeLeftRight(graph.LargestMatching())
+	for _, e := range unmatched {
+		c.report(path.child(e.(int)), "no matching element for %s", renderSemiStructuredValue(expected.Index(e.(int)).Interface()))
+	}
+}
+
+func semiStructuredKeys(v reflect.Value) map[string]reflect.Value {
+	keys := map[string]reflect.Value{}
+	for _, key := range v.MapKeys() {
+		keys[fmt.Sprint(key.Interface())] = key
+	}
+	return keys
+}
+
+func sortedKeys(keys map[string]reflect.Value) []string {
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+var jsonPathIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+func semiStructuredChildPath(path string, key string) string {
+	if jsonPathIdentifier.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "['" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(key) + "']"
+}
+
+// scalarsEqual compares scalars the way MatchJSON and MatchYAML do without options (see deepEqual) - unless a numeric tolerance is configured
+func (c *semiStructuredDataComparison) scalarsEqual(actual, expected any) bool {
+	if c.numericTolerance && isNumber(actual) && isNumber(expected) {
+		return math.Abs(toFloat(actual)-toFloat(expected)) <= c.epsilon
+	}
+	equal, _ := deepEqual(actual, expected)
+	return equal
+}
+
+func renderSemiStructuredValue(v any) string {
+	if encoded, err := json.Marshal(v); err == nil {
+		return string(encoded)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func describeSemiStructuredValue(v any) string {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Map:
+		return "an object " + renderSemiStructuredValue(v)
+	case reflect.Slice:
+		return "an array " + renderSemiStructuredValue(v)
+	}
+	return renderSemiStructuredValue(v)
+}
+
+func formattedDifferences(comparisonMessage string, differences []string) string {
+	if len(differences) == 0 {
+		return comparisonMessage
+	}
+	return fmt.Sprintf("%s\n\nmismatched paths:\n%s", comparisonMessage, format.IndentString(strings.Join(differences, "\n"), 1))
+}
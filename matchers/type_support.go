@@ -15,7 +15,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/onsi/gomega/format"
 	"github.com/onsi/gomega/matchers/internal/miter"
 )
 
@@ -147,6 +149,27 @@ func toString(a any) (string, bool) {
 	return "", false
 }
 
+// comparisonMessage is format.Message for matchers that compare actual against a single expected value.  When format.UseStructuralDiff
+// is set it lists the differences between the two - order-independent matchers (ConsistOf, ContainElements...) must not use it.
+func comparisonMessage(actual any, message string, expected any) string {
+	if format.UseStructuralDiff {
+		if differences := format.StructuralDiff(actual, expected); len(differences) > 0 {
+			return format.MessageWithStructuralDiff(actual, message, expected, differences)
+		}
+	}
+	return format.Message(actual, message, expected)
+}
+
+func leadingLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	return strings.Join(lines[:min(n, len(lines))], "\n")
+}
+
+func trailingLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	return strings.Join(lines[max(len(lines)-n, 0):], "\n")
+}
+
 func lengthOf(a any) (int, bool) {
 	if a == nil {
 		return 0, false
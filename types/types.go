@@ -31,6 +31,10 @@ type Gomega interface {
 	SetDefaultConsistentlyPollingInterval(time.Duration)
 	EnforceDefaultTimeoutsWhenUsingContexts()
 	DisableDefaultTimeoutsWhenUsingContext()
+	EnableAsyncAssertionTracing()
+	DisableAsyncAssertionTracing()
+	SetDefaultEventuallyPollingStrategy(PollingStrategy)
+	SetDefaultConsistentlyPollingStrategy(PollingStrategy)
 }
 
 // All Gomega matchers must implement the GomegaMatcher interface
@@ -89,6 +93,23 @@ type AsyncAssertion interface {
 	WithContext(ctx context.Context) AsyncAssertion
 	WithArguments(argsToForward ...any) AsyncAssertion
 	MustPassRepeatedly(count int) AsyncAssertion
+	WithTrace() AsyncAssertion
+	WithPollingStrategy(strategy PollingStrategy) AsyncAssertion
+}
+
+// PollingStrategy determines how long Eventually and Consistently wait between attempts.
+//
+// NextInterval is passed the number of attempts made so far (starting at 1) and returns how long to wait before the next attempt.
+// PollingStrategies are shared between assertions and may be called concurrently.
+type PollingStrategy interface {
+	NextInterval(attempt int) time.Duration
+}
+
+// PollingStrategyFunc allows an ordinary function to be used as a PollingStrategy
+type PollingStrategyFunc func(attempt int) time.Duration
+
+func (f PollingStrategyFunc) NextInterval(attempt int) time.Duration {
+	return f(attempt)
 }
 
 // Assertions are returned by Ω and Expect and enable assertions against Gomega matchers